@@ -10,16 +10,23 @@ import (
 type CacheManager struct {
 	tokenCache    *gocache.Cache
 	analysisCache *gocache.Cache
+	chatCache     *gocache.Cache
+
+	chatSessionDuration time.Duration
 }
 
 // NewCacheManager creates a new cache manager with configured durations
-func NewCacheManager(tokenCacheDuration, analysisCacheDuration time.Duration) *CacheManager {
+func NewCacheManager(tokenCacheDuration, analysisCacheDuration, chatSessionDuration time.Duration) *CacheManager {
 	return &CacheManager{
 		// Token data cache: shorter duration for fresh market data
 		tokenCache: gocache.New(tokenCacheDuration, tokenCacheDuration*2),
 
 		// AI analysis cache: longer duration to reduce API calls
 		analysisCache: gocache.New(analysisCacheDuration, analysisCacheDuration*2),
+
+		// Chat sessions: idle timeout, refreshed on every turn
+		chatCache:           gocache.New(chatSessionDuration, time.Minute),
+		chatSessionDuration: chatSessionDuration,
 	}
 }
 
@@ -57,6 +64,23 @@ func (c *CacheManager) InvalidateAnalysis() {
 	c.analysisCache.Flush()
 }
 
+// Chat Session Methods
+
+// GetChatSession retrieves a live chat session
+func (c *CacheManager) GetChatSession(id string) (interface{}, bool) {
+	return c.chatCache.Get(id)
+}
+
+// SetChatSession stores a chat session, restarting its expiry
+func (c *CacheManager) SetChatSession(id string, session interface{}) {
+	c.chatCache.Set(id, session, gocache.DefaultExpiration)
+}
+
+// ChatSessionDuration is the idle time after which a chat session expires
+func (c *CacheManager) ChatSessionDuration() time.Duration {
+	return c.chatSessionDuration
+}
+
 // GetTokenCacheItemCount returns the number of items in token cache
 func (c *CacheManager) GetTokenCacheItemCount() int {
 	return c.tokenCache.ItemCount()
@@ -67,8 +91,14 @@ func (c *CacheManager) GetAnalysisCacheItemCount() int {
 	return c.analysisCache.ItemCount()
 }
 
+// GetChatSessionCount returns the number of live chat sessions
+func (c *CacheManager) GetChatSessionCount() int {
+	return c.chatCache.ItemCount()
+}
+
 // FlushAll clears all caches
 func (c *CacheManager) FlushAll() {
 	c.tokenCache.Flush()
 	c.analysisCache.Flush()
+	c.chatCache.Flush()
 }
@@ -0,0 +1,78 @@
+// Command webhook-receiver is a local endpoint for developing against AlphaAgent webhooks.
+// It prints every delivery, verifies its signature when a secret is given, and can
+// simulate a failing endpoint to exercise retries and automatic disabling.
+//
+//	go run ./cmd/webhook-receiver -addr :9090 -secret whsec_...
+//
+// then register http://localhost:9090/ with POST /api/webhooks.
+package main
+
+import (
+	"backend/services"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// maxClockSkew rejects signatures with stale timestamps (replays)
+const maxClockSkew = 5 * time.Minute
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	secret := flag.String("secret", os.Getenv("WEBHOOK_SECRET"), "webhook signing secret; empty skips verification")
+	status := flag.Int("status", http.StatusOK, "status code to answer with (e.g. 500 to test retries)")
+	flag.Parse()
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST only", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		event := r.Header.Get(services.WebhookEventHeader)
+		delivery := r.Header.Get(services.WebhookDeliveryHeader)
+		timestamp := r.Header.Get(services.WebhookTimestampHeader)
+		signature := r.Header.Get(services.WebhookSignatureHeader)
+
+		verdict := "unverified (no -secret)"
+		if *secret != "" {
+			sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+			switch {
+			case err != nil:
+				verdict = "❌ missing timestamp"
+			case time.Since(time.Unix(sentAt, 0)).Abs() > maxClockSkew:
+				verdict = "❌ stale timestamp"
+			case !services.VerifyWebhookSignature(*secret, timestamp, body, signature):
+				verdict = "❌ bad signature"
+			default:
+				verdict = "✅ signature valid"
+			}
+		}
+
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "  ") != nil {
+			pretty.Write(body)
+		}
+		log.Printf("📨 %s delivery %s: %s\n%s", event, delivery, verdict, pretty.String())
+
+		if *secret != "" && verdict != "✅ signature valid" {
+			http.Error(w, verdict, http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(*status)
+	})
+
+	log.Printf("🌐 Webhook receiver listening on %s (answering %d)", *addr, *status)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
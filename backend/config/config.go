@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -10,16 +12,51 @@ import (
 
 type Config struct {
 	// Server settings
-	Port    string
-	GinMode string
+	Port               string
+	GinMode            string
+	CORSAllowedOrigins []string // "*" allows any origin
+	TrustedProxies     []string // proxy IPs/CIDRs whose X-Forwarded-For is believed; none by default
 
 	// API Keys
 	GeminiAPIKey string
 
+	// LLM provider selection (gemini, openai, template; empty = auto)
+	LLMProvider string
+	LLMModel    string
+	LLMBaseURL  string // OpenAI-compatible endpoint, e.g. http://localhost:11434/v1
+	LLMAPIKey   string
+
+	// Prompt templates
+	PromptsDir     string // extra/overriding *.tmpl files, optional
+	AnalysisPrompt string // default analysis template ID
+	ComparePrompt  string // default comparison template ID
+	BriefPrompt    string // daily market brief template ID
+	ChatPrompt     string // chat session system prompt template ID
+
+	// Daily market brief
+	BriefHourUTC int // hour of day (UTC) the brief is generated
+
 	// Cache settings
 	TokenCacheDuration    time.Duration
 	AnalysisCacheDuration time.Duration
 
+	// Chat sessions
+	ChatSessionDuration time.Duration // idle expiry
+	ChatTokenBudget     int           // LLM tokens per session
+
+	// AI usage budgets (per UTC day, 0 = unlimited) and model prices
+	AIDailyTokenBudget         int
+	AIDailyRequestBudget       int
+	AIClientDailyTokenBudget   int
+	AIClientDailyRequestBudget int
+	AIModelPrices              string // "model=input:output,..." USD per million tokens
+
+	// Persistence
+	DataDir string // directory for JSON-file collections
+
+	// AI recommendation backtesting
+	BacktestInterval time.Duration
+
 	// External API URLs
 	DefiLlamaAPIURL   string
 	CoinGeckoAPIURL   string
@@ -37,6 +74,43 @@ type Config struct {
 	GlassnodeAPIURL     string
 	GlassnodeAPIKey     string
 	CryptoCompareAPIURL string
+	CryptoCompareAPIKey string
+
+	// Analytics settings
+	MarketIndexSize     int           // constituents in the cap-weighted market index
+	BetaRefreshInterval time.Duration // background market beta computation
+	BetaTokenLimit      int           // largest tokens given a market beta
+
+	// Stablecoin depeg bands (basis points away from peg)
+	DepegWarnBps     float64
+	DepegAlertBps    float64
+	DepegCriticalBps float64
+
+	// Alerts
+	SnapshotRefreshInterval time.Duration // background snapshot refresh, 0 = only on request
+	AlertDefaultCooldown    time.Duration // per token, when a rule doesn't set one
+
+	// Webhook notifications
+	WebhookTimeout      time.Duration
+	WebhookMaxAttempts  int
+	WebhookRetryBase    time.Duration // first retry delay, doubled per attempt
+	WebhookDisableAfter int           // consecutive failures before a webhook is disabled, 0 = never
+	WebhookAllowedHosts []string      // hosts exempt from the private/loopback address block
+	GradeChangeMaxRank  int           // grade changes are notified for tokens ranked up to this
+	GradeChangeCooldown time.Duration // per token, between grade change notifications
+
+	// Live stream (/ws)
+	StreamHeartbeatInterval time.Duration
+	StreamResumeWindow      time.Duration // how long a disconnected subscription can be resumed
+	StreamBacklog           int           // updates kept per subscription for resume and slow clients
+	StreamMaxSubscriptions  int
+
+	// API key authentication and rate limits (requests per minute, 0 = unlimited)
+	AdminAPIKey        string        // bootstrap admin key, to create the others
+	AnonymousRole      string        // role of requests without a key: viewer, analyst, admin or none
+	APIKeyRateLimit    int           // default for keys without their own limit
+	AnonymousRateLimit int           // per client IP
+	StreamTokenTTL     time.Duration // lifetime of /ws credentials issued to API keys
 }
 
 var AppConfig *Config
@@ -48,16 +122,51 @@ func LoadConfig() *Config {
 
 	config := &Config{
 		// Server defaults
-		Port:    getEnv("PORT", "8080"),
-		GinMode: getEnv("GIN_MODE", "release"),
+		Port:               getEnv("PORT", "8080"),
+		GinMode:            getEnv("GIN_MODE", "release"),
+		CORSAllowedOrigins: parseList(getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173")),
+		TrustedProxies:     parseList(getEnv("TRUSTED_PROXIES", "")),
 
 		// API Keys
 		GeminiAPIKey: getEnv("GEMINI_API_KEY", ""),
 
+		// LLM provider
+		LLMProvider: getEnv("LLM_PROVIDER", ""),
+		LLMModel:    getEnv("LLM_MODEL", ""),
+		LLMBaseURL:  getEnv("LLM_BASE_URL", ""),
+		LLMAPIKey:   getEnv("LLM_API_KEY", ""),
+
+		// Prompt templates
+		PromptsDir:     getEnv("PROMPTS_DIR", ""),
+		AnalysisPrompt: getEnv("ANALYSIS_PROMPT", "analysis-v2"),
+		ComparePrompt:  getEnv("COMPARE_PROMPT", "compare-v1"),
+		BriefPrompt:    getEnv("BRIEF_PROMPT", "brief-v1"),
+		ChatPrompt:     getEnv("CHAT_PROMPT", "chat-v1"),
+
+		// Daily market brief
+		BriefHourUTC: parseInt(getEnv("BRIEF_HOUR_UTC", "6"), 6),
+
 		// Cache durations
 		TokenCacheDuration:    parseDuration(getEnv("TOKEN_CACHE_DURATION", "5m"), 5*time.Minute),
 		AnalysisCacheDuration: parseDuration(getEnv("ANALYSIS_CACHE_DURATION", "60m"), 60*time.Minute),
 
+		// Chat sessions
+		ChatSessionDuration: parseDuration(getEnv("CHAT_SESSION_DURATION", "30m"), 30*time.Minute),
+		ChatTokenBudget:     parseInt(getEnv("CHAT_TOKEN_BUDGET", "20000"), 20000),
+
+		// AI usage budgets
+		AIDailyTokenBudget:         parseInt(getEnv("AI_DAILY_TOKEN_BUDGET", "2000000"), 2000000),
+		AIDailyRequestBudget:       parseInt(getEnv("AI_DAILY_REQUEST_BUDGET", "2000"), 2000),
+		AIClientDailyTokenBudget:   parseInt(getEnv("AI_CLIENT_DAILY_TOKEN_BUDGET", "200000"), 200000),
+		AIClientDailyRequestBudget: parseInt(getEnv("AI_CLIENT_DAILY_REQUEST_BUDGET", "200"), 200),
+		AIModelPrices:              getEnv("AI_MODEL_PRICES", ""),
+
+		// Persistence
+		DataDir: getEnv("DATA_DIR", "./data"),
+
+		// Backtesting
+		BacktestInterval: parseDuration(getEnv("BACKTEST_INTERVAL", "6h"), 6*time.Hour),
+
 		// External APIs
 		DefiLlamaAPIURL:   getEnv("DEFILLAMA_API_URL", "https://api.llama.fi"),
 		CoinGeckoAPIURL:   getEnv("COINGECKO_API_URL", "https://api.coingecko.com/api/v3"),
@@ -75,11 +184,53 @@ func LoadConfig() *Config {
 		GlassnodeAPIURL:     getEnv("GLASSNODE_API_URL", "https://api.glassnode.com"),
 		GlassnodeAPIKey:     getEnv("GLASSNODE_API_KEY", ""),
 		CryptoCompareAPIURL: getEnv("CRYPTOCOMPARE_API_URL", "https://min-api.cryptocompare.com"),
+		CryptoCompareAPIKey: getEnv("CRYPTOCOMPARE_API_KEY", ""),
+
+		// Analytics
+		MarketIndexSize:     parseInt(getEnv("MARKET_INDEX_SIZE", "10"), 10),
+		BetaRefreshInterval: parseDuration(getEnv("BETA_REFRESH_INTERVAL", "6h"), 6*time.Hour),
+		BetaTokenLimit:      parseInt(getEnv("BETA_TOKEN_LIMIT", "100"), 100),
+
+		// Stablecoin monitoring
+		DepegWarnBps:     parseFloat(getEnv("DEPEG_WARN_BPS", "50"), 50),
+		DepegAlertBps:    parseFloat(getEnv("DEPEG_ALERT_BPS", "100"), 100),
+		DepegCriticalBps: parseFloat(getEnv("DEPEG_CRITICAL_BPS", "300"), 300),
+
+		// Alerts
+		SnapshotRefreshInterval: parseDuration(getEnv("SNAPSHOT_REFRESH_INTERVAL", "1m"), time.Minute),
+		AlertDefaultCooldown:    parseDuration(getEnv("ALERT_DEFAULT_COOLDOWN", "1h"), time.Hour),
+
+		// Webhook notifications
+		WebhookTimeout:      parseDuration(getEnv("WEBHOOK_TIMEOUT", "10s"), 10*time.Second),
+		WebhookMaxAttempts:  parseInt(getEnv("WEBHOOK_MAX_ATTEMPTS", "8"), 8),
+		WebhookRetryBase:    parseDuration(getEnv("WEBHOOK_RETRY_BASE", "30s"), 30*time.Second),
+		WebhookDisableAfter: parseInt(getEnv("WEBHOOK_DISABLE_AFTER", "20"), 20),
+		WebhookAllowedHosts: parseList(getEnv("WEBHOOK_ALLOWED_HOSTS", "")),
+		GradeChangeMaxRank:  parseInt(getEnv("GRADE_CHANGE_MAX_RANK", "100"), 100),
+		GradeChangeCooldown: parseDuration(getEnv("GRADE_CHANGE_COOLDOWN", "6h"), 6*time.Hour),
+
+		// Live stream (/ws)
+		StreamHeartbeatInterval: parseDuration(getEnv("STREAM_HEARTBEAT_INTERVAL", "15s"), 15*time.Second),
+		StreamResumeWindow:      parseDuration(getEnv("STREAM_RESUME_WINDOW", "5m"), 5*time.Minute),
+		StreamBacklog:           parseInt(getEnv("STREAM_BACKLOG", "32"), 32),
+		StreamMaxSubscriptions:  parseInt(getEnv("STREAM_MAX_SUBSCRIPTIONS", "1000"), 1000),
+
+		// API key authentication
+		AdminAPIKey:        getEnv("ADMIN_API_KEY", ""),
+		AnonymousRole:      getEnv("AUTH_ANONYMOUS_ROLE", "viewer"),
+		APIKeyRateLimit:    parseInt(getEnv("API_KEY_RATE_LIMIT", "120"), 120),
+		AnonymousRateLimit: parseInt(getEnv("ANONYMOUS_RATE_LIMIT", "60"), 60),
+		StreamTokenTTL:     parseDuration(getEnv("STREAM_TOKEN_TTL", "2m"), 2*time.Minute),
 	}
 
 	// Validate required fields
-	if config.GeminiAPIKey == "" {
-		log.Println("WARNING: GEMINI_API_KEY not set - AI analysis will not work")
+	if config.GeminiAPIKey == "" && config.LLMProvider == "" {
+		log.Println("WARNING: GEMINI_API_KEY not set - AI analysis will use the offline template provider")
+	}
+	if !(config.DepegWarnBps > 0 && config.DepegWarnBps < config.DepegAlertBps && config.DepegAlertBps < config.DepegCriticalBps) {
+		log.Printf("WARNING: depeg bands must satisfy 0 < DEPEG_WARN_BPS < DEPEG_ALERT_BPS < DEPEG_CRITICAL_BPS (got %g/%g/%g) - using 50/100/300",
+			config.DepegWarnBps, config.DepegAlertBps, config.DepegCriticalBps)
+		config.DepegWarnBps, config.DepegAlertBps, config.DepegCriticalBps = 50, 100, 300
 	}
 
 	AppConfig = config
@@ -94,6 +245,32 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func parseInt(value string, defaultValue int) int {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
+
+func parseFloat(value string, defaultValue float64) float64 {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return parsed
+}
+
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func parseDuration(value string, defaultDuration time.Duration) time.Duration {
 	duration, err := time.ParseDuration(value)
 	if err != nil {
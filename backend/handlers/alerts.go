@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"backend/models"
+	"backend/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultAlertHistoryLimit = 100
+	maxAlertHistoryLimit     = 1000
+)
+
+// AlertHandler manages alert rules and serves fired-alert history
+type AlertHandler struct {
+	alerts *services.AlertEngine
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(alerts *services.AlertEngine) *AlertHandler {
+	return &AlertHandler{alerts: alerts}
+}
+
+// CreateAlert handles POST /api/alerts
+func (h *AlertHandler) CreateAlert(c *gin.Context) {
+	var req models.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid request body: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	rule, err := h.alerts.Create(requestOwner(c), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrInvalidAlertRule):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrAlertRuleLimit):
+			status = http.StatusConflict
+		}
+		c.JSON(status, models.ErrorResponse{
+			Status:    "error",
+			Message:   err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      rule,
+	})
+}
+
+// GetAlerts handles GET /api/alerts: the rules of the calling client
+func (h *AlertHandler) GetAlerts(c *gin.Context) {
+	rules := h.alerts.List(requestOwner(c))
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(rules),
+		Data:      rules,
+	})
+}
+
+// GetAlert handles GET /api/alerts/:id
+func (h *AlertHandler) GetAlert(c *gin.Context) {
+	rule, found := h.alerts.Get(requestOwner(c), c.Param("id"))
+	if !found {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Alert rule not found: " + c.Param("id"),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      rule,
+	})
+}
+
+// DeleteAlert handles DELETE /api/alerts/:id
+func (h *AlertHandler) DeleteAlert(c *gin.Context) {
+	if err := h.alerts.Delete(requestOwner(c), c.Param("id")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrAlertRuleNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.ErrorResponse{
+			Status:    "error",
+			Message:   err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     0,
+	})
+}
+
+// GetAlertHistory handles GET /api/alerts/history?rule_id=&limit=100
+func (h *AlertHandler) GetAlertHistory(c *gin.Context) {
+	limit := defaultAlertHistoryLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > maxAlertHistoryLimit {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Status:    "error",
+				Message:   "limit must be between 1 and " + strconv.Itoa(maxAlertHistoryLimit),
+				Timestamp: time.Now(),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	events := h.alerts.History(requestOwner(c), c.Query("rule_id"), limit)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(events),
+		Data:      events,
+	})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"backend/models"
+	"backend/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultAnalysesLimit = 20
+
+// AnalysisHistoryHandler serves stored AI analyses
+type AnalysisHistoryHandler struct {
+	history *services.AnalysisHistory
+	market  *services.MarketData
+}
+
+// NewAnalysisHistoryHandler creates a new analysis history handler
+func NewAnalysisHistoryHandler(history *services.AnalysisHistory, market *services.MarketData) *AnalysisHistoryHandler {
+	return &AnalysisHistoryHandler{
+		history: history,
+		market:  market,
+	}
+}
+
+// GetTokenAnalyses handles GET /api/tokens/:id/analyses?limit=20
+func (h *AnalysisHistoryHandler) GetTokenAnalyses(c *gin.Context) {
+	limit := defaultAnalysesLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Status:    "error",
+				Message:   "limit must be a positive integer",
+				Timestamp: time.Now(),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	// Accept symbol or name like the other token routes; fall back to the raw ID
+	// so history stays readable for tokens that dropped out of the snapshot
+	tokenID := c.Param("id")
+	if token, found, err := h.market.FindToken(c.Request.Context(), tokenID); err == nil && found {
+		tokenID = token.ID
+	}
+
+	records := h.history.ForToken(tokenID, limit)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(records),
+		Data:      records,
+	})
+}
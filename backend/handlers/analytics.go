@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"backend/config"
+	"backend/indicators"
+	"backend/models"
+	"backend/services"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Window limits for history-based analytics (days)
+const (
+	minWindowDays = 7
+	maxWindowDays = 365
+
+	maxIndicatorDays = 1000
+)
+
+// AnalyticsHandler handles history-based analytics endpoints
+type AnalyticsHandler struct {
+	analytics *services.MarketAnalytics
+	history   *services.HistoryService
+	market    *services.MarketData
+	config    *config.Config
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(
+	analytics *services.MarketAnalytics,
+	history *services.HistoryService,
+	market *services.MarketData,
+	cfg *config.Config,
+) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analytics: analytics,
+		history:   history,
+		market:    market,
+		config:    cfg,
+	}
+}
+
+// GetTokenBeta handles GET /api/tokens/:id/beta
+func (h *AnalyticsHandler) GetTokenBeta(c *gin.Context) {
+	window, err := parseWindowDays(c.DefaultQuery("window", "30d"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	symbol, ok := h.resolveSymbol(c)
+	if !ok {
+		return
+	}
+	report, err := h.analytics.TokenBeta(c.Request.Context(), symbol, window)
+	if err != nil {
+		log.Printf("❌ Beta computation failed for %s: %v", symbol, err)
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Failed to compute beta: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Data:      report,
+	})
+}
+
+// GetTokenIndicators handles GET /api/tokens/:id/indicators?set=rsi,macd,bb&days=180
+func (h *AnalyticsHandler) GetTokenIndicators(c *gin.Context) {
+	days := 180
+	if val, err := strconv.Atoi(c.Query("days")); err == nil {
+		if val < minWindowDays || val > maxIndicatorDays {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Status:    "error",
+				Message:   fmt.Sprintf("days must be between %d and %d", minWindowDays, maxIndicatorDays),
+				Timestamp: time.Now(),
+			})
+			return
+		}
+		days = val
+	}
+
+	set := strings.Split(c.DefaultQuery("set", "rsi,macd,bb"), ",")
+
+	symbol, ok := h.resolveSymbol(c)
+	if !ok {
+		return
+	}
+	candles, err := h.history.GetDailyCandles(c.Request.Context(), symbol, days)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Failed to load price history: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	outputs, err := indicators.Compute(candles, set)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	report := models.IndicatorReport{
+		Symbol:     symbol,
+		Days:       len(candles),
+		Indicators: make(map[string]map[string][]models.IndicatorPoint, len(outputs)),
+	}
+	if c.Query("candles") == "true" {
+		report.Candles = candles
+	}
+	for name, output := range outputs {
+		report.Indicators[name] = make(map[string][]models.IndicatorPoint, len(output))
+		for line, series := range output {
+			report.Indicators[name][line] = indicators.ToPoints(candles, series)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Data:      report,
+	})
+}
+
+// GetCorrelations handles GET /api/correlations?symbols=BTC,ETH,SOL&window=30d
+func (h *AnalyticsHandler) GetCorrelations(c *gin.Context) {
+	window, err := parseWindowDays(c.DefaultQuery("window", "30d"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	var requested []string
+	seen := make(map[string]bool)
+	for _, symbol := range strings.Split(c.Query("symbols"), ",") {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol != "" && !seen[symbol] {
+			seen[symbol] = true
+			requested = append(requested, symbol)
+		}
+	}
+	if len(requested) < 2 || len(requested) > 25 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "symbols must list between 2 and 25 comma-separated symbols",
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	// Only tokens of the snapshot are fetched, so junk symbols cost no upstream calls
+	var symbols, unknown []string
+	for _, id := range requested {
+		token, found, err := h.market.FindToken(c.Request.Context(), id)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Status:    "error",
+				Message:   "Market data unavailable: " + err.Error(),
+				Timestamp: time.Now(),
+			})
+			return
+		}
+		if !found {
+			unknown = append(unknown, id)
+		} else if !slices.Contains(symbols, token.Symbol) {
+			symbols = append(symbols, token.Symbol)
+		}
+	}
+	if len(unknown) > 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "unknown symbols: " + strings.Join(unknown, ", "),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	matrix, err := h.analytics.CorrelationMatrix(c.Request.Context(), symbols, window)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Failed to compute correlations: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Data:      matrix,
+	})
+}
+
+// resolveSymbol maps the :id slug/name to the trading symbol of a snapshot token,
+// responding 404 (or 503 without market data) and false when there is none
+func (h *AnalyticsHandler) resolveSymbol(c *gin.Context) (string, bool) {
+	token, found, err := h.market.FindToken(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Market data unavailable: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return "", false
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Token not found",
+			Timestamp: time.Now(),
+		})
+		return "", false
+	}
+	return token.Symbol, true
+}
+
+// parseWindowDays accepts "30d" or "30" and validates the range
+func parseWindowDays(value string) (int, error) {
+	days, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), "d"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid window %q (use e.g. 30d)", value)
+	}
+	if days < minWindowDays || days > maxWindowDays {
+		return 0, fmt.Errorf("window must be between %dd and %dd", minWindowDays, maxWindowDays)
+	}
+	return days, nil
+}
@@ -4,10 +4,14 @@ import (
 	"backend/cache"
 	"backend/config"
 	"backend/models"
+	"backend/prompts"
 	"backend/services"
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,21 +19,30 @@ import (
 
 // AnalyzeHandler handles AI analysis endpoints
 type AnalyzeHandler struct {
-	aiService *services.AIService
-	cache     *cache.CacheManager
-	config    *config.Config
+	aiService      *services.AIService
+	contextBuilder *services.AnalysisContextBuilder
+	market         *services.MarketData
+	history        *services.AnalysisHistory
+	cache          *cache.CacheManager
+	config         *config.Config
 }
 
 // NewAnalyzeHandler creates a new analyze handler
 func NewAnalyzeHandler(
 	aiService *services.AIService,
+	contextBuilder *services.AnalysisContextBuilder,
+	market *services.MarketData,
+	history *services.AnalysisHistory,
 	cacheManager *cache.CacheManager,
 	cfg *config.Config,
 ) *AnalyzeHandler {
 	return &AnalyzeHandler{
-		aiService: aiService,
-		cache:     cacheManager,
-		config:    cfg,
+		aiService:      aiService,
+		contextBuilder: contextBuilder,
+		market:         market,
+		history:        history,
+		cache:          cacheManager,
+		config:         cfg,
 	}
 }
 
@@ -46,55 +59,280 @@ func (h *AnalyzeHandler) AnalyzeToken(c *gin.Context) {
 		return
 	}
 
-	// Validate required fields
-	if req.Symbol == "" || req.Name == "" {
+	token, ok := h.resolveToken(c, req.TokenID)
+	if !ok {
+		return
+	}
+
+	tmpl, ok := h.resolvePrompt(c, req.Prompt, req.Lang)
+	if !ok {
+		return
+	}
+
+	log.Printf("📊 Analysis request for %s (%s)", token.Name, token.Symbol)
+
+	// Reuse the latest analysis while the inputs haven't moved much
+	if record, found := h.history.Reusable(token.ID, h.aiService.Provider().Name(), h.aiService.Provider().Model(), tmpl.ID, tmpl.Lang, services.NewAnalysisFingerprint(token)); found {
+		log.Printf("✓ Reusing analysis %s for %s (generated %s)", record.ID, token.Symbol, record.GeneratedAt.Format(time.RFC3339))
+		c.JSON(http.StatusOK, analysisResponse(record, true))
+		return
+	}
+
+	log.Printf("✗ No reusable analysis for %s - generating fresh analysis", token.Symbol)
+
+	// Generate AI analysis from server-side data
+	actx := h.contextBuilder.BuildForToken(c.Request.Context(), token)
+	record, err := h.aiService.AnalyzeToken(c.Request.Context(), actx, tmpl)
+	if err != nil {
+		log.Printf("❌ AI analysis failed for %s: %v", token.Symbol, err)
+		writeAIError(c, err, "Failed to generate analysis")
+		return
+	}
+
+	h.storeRecord(record)
+	c.JSON(http.StatusOK, analysisResponse(record, false))
+}
+
+// AnalyzeTokenStream handles GET /api/analyze/stream?token_id= using Server-Sent Events.
+// Events: progress {stage}, chunk {text}, result (AnalysisResponse), error {message}
+func (h *AnalyzeHandler) AnalyzeTokenStream(c *gin.Context) {
+	var req models.AnalysisRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid query parameters: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	token, ok := h.resolveToken(c, req.TokenID)
+	if !ok {
+		return
+	}
+	tmpl, ok := h.resolvePrompt(c, req.Prompt, req.Lang)
+	if !ok {
+		return
+	}
+
+	log.Printf("📊 Streaming analysis request for %s (%s)", token.Name, token.Symbol)
+
+	// A budget rejection must be a plain 429 before the event stream starts
+	reusable, found := h.history.Reusable(token.ID, h.aiService.Provider().Name(), h.aiService.Provider().Model(), tmpl.ID, tmpl.Lang, services.NewAnalysisFingerprint(token))
+	if !found {
+		if err := h.aiService.CheckBudget(c.Request.Context()); err != nil {
+			writeAIError(c, err, "Failed to generate analysis")
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+
+	send := func(event string, data interface{}) {
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+	}
+
+	if found {
+		log.Printf("✓ Reusing analysis %s for %s", reusable.ID, token.Symbol)
+		send("result", analysisResponse(reusable, true))
+		return
+	}
+
+	actx := h.contextBuilder.BuildForToken(c.Request.Context(), token)
+	record, err := h.aiService.AnalyzeTokenStream(c.Request.Context(), actx, tmpl, func(event services.StreamEvent) {
+		switch event.Type {
+		case services.StreamEventProgress:
+			send("progress", gin.H{"stage": event.Stage})
+		case services.StreamEventChunk:
+			send("chunk", gin.H{"text": event.Text})
+		}
+	})
+	if err != nil {
+		log.Printf("❌ Streaming AI analysis failed for %s: %v", token.Symbol, err)
+		send("error", gin.H{"message": "Failed to generate analysis: " + err.Error()})
+		return
+	}
+
+	h.storeRecord(record)
+	send("result", analysisResponse(record, false))
+}
+
+// CompareTokens handles POST /api/analyze/compare
+func (h *AnalyzeHandler) CompareTokens(c *gin.Context) {
+	var req models.CompareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid request body: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	// Bound the lookups; duplicates get some slack since they collapse below
+	if len(req.TokenIDs) > models.MaxCompareTokens*2 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   fmt.Sprintf("Compare takes at most %d token IDs, got %d", models.MaxCompareTokens*2, len(req.TokenIDs)),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	// Resolve every ID first so duplicates by symbol/slug collapse to one token
+	var tokens []models.Token
+	seen := make(map[string]bool)
+	for _, id := range req.TokenIDs {
+		token, ok := h.resolveToken(c, id)
+		if !ok {
+			return
+		}
+		if !seen[token.ID] {
+			seen[token.ID] = true
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) < models.MinCompareTokens || len(tokens) > models.MaxCompareTokens {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   fmt.Sprintf("Compare takes %d to %d distinct tokens, got %d", models.MinCompareTokens, models.MaxCompareTokens, len(tokens)),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	tmpl, err := h.aiService.ResolvePrompt(prompts.KindCompare, req.Prompt)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Status:    "error",
-			Message:   "Symbol and Name are required",
+			Message:   err.Error(),
 			Timestamp: time.Now(),
 		})
 		return
 	}
 
-	log.Printf("📊 Analysis request for %s (%s)", req.Name, req.Symbol)
+	ids := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		ids = append(ids, token.ID)
+	}
+	sort.Strings(ids)
+	cacheKey := fmt.Sprintf("compare_%s_%s", tmpl.ID, strings.Join(ids, ","))
+
+	log.Printf("📊 Comparison request for %s", strings.Join(ids, ", "))
 
-	// Check cache first
-	cacheKey := fmt.Sprintf("analysis_%s", req.Symbol)
 	if cached, found := h.cache.GetAnalysis(cacheKey); found {
-		log.Printf("✓ Cache hit for analysis: %s", req.Symbol)
-
-		analysis := cached.(string)
-		c.JSON(http.StatusOK, models.AnalysisResponse{
-			Status:      "success",
-			Cached:      true,
-			Analysis:    analysis,
-			GeneratedAt: time.Now(),
+		log.Printf("✓ Cache hit for comparison: %s", cacheKey)
+		c.JSON(http.StatusOK, models.ComparisonResponse{
+			Status:           "success",
+			Cached:           true,
+			ComparisonRecord: *cached.(*models.ComparisonRecord),
 		})
 		return
 	}
 
-	log.Printf("✗ Cache miss for analysis: %s - generating fresh analysis", req.Symbol)
+	// Contexts are independent, so fetch their histories concurrently
+	cctx := &models.ComparisonContext{Tokens: make([]*models.AnalysisContext, len(tokens))}
+	var wg sync.WaitGroup
+	for i, token := range tokens {
+		wg.Add(1)
+		go func(i int, token models.Token) {
+			defer wg.Done()
+			cctx.Tokens[i] = h.contextBuilder.BuildForToken(c.Request.Context(), token)
+		}(i, token)
+	}
+	wg.Wait()
 
-	// Generate AI analysis
-	analysis, err := h.aiService.AnalyzeToken(c.Request.Context(), req)
+	record, err := h.aiService.CompareTokens(c.Request.Context(), cctx, tmpl)
+	if err != nil {
+		log.Printf("❌ AI comparison failed for %s: %v", strings.Join(ids, ", "), err)
+		writeAIError(c, err, "Failed to generate comparison")
+		return
+	}
+
+	h.cache.SetAnalysis(cacheKey, record)
+	log.Printf("✓ Cached comparison: %s", cacheKey)
+
+	c.JSON(http.StatusOK, models.ComparisonResponse{
+		Status:           "success",
+		Cached:           false,
+		ComparisonRecord: *record,
+	})
+}
+
+// resolveToken finds the token in the current snapshot, writing an error response if it can't
+func (h *AnalyzeHandler) resolveToken(c *gin.Context, tokenID string) (models.Token, bool) {
+	token, found, err := h.market.FindToken(c.Request.Context(), tokenID)
 	if err != nil {
-		log.Printf("❌ AI analysis failed for %s: %v", req.Symbol, err)
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Status:    "error",
-			Message:   "Failed to generate analysis: " + err.Error(),
+			Message:   "Failed to load market data: " + err.Error(),
 			Timestamp: time.Now(),
 		})
-		return
+		return models.Token{}, false
 	}
+	if !found {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Token not found: " + tokenID,
+			Timestamp: time.Now(),
+		})
+		return models.Token{}, false
+	}
+	return token, true
+}
 
-	// Cache the result
-	h.cache.SetAnalysis(cacheKey, analysis)
-	log.Printf("✓ Cached analysis for: %s", req.Symbol)
+// resolvePrompt looks up the requested analysis template in lang, writing a 400 if it doesn't exist
+func (h *AnalyzeHandler) resolvePrompt(c *gin.Context, id, lang string) (*prompts.Template, bool) {
+	tmpl, err := h.aiService.ResolvePromptLang(prompts.KindAnalysis, id, lang)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   err.Error(),
+			Timestamp: time.Now(),
+		})
+		return nil, false
+	}
+	return tmpl, true
+}
 
-	c.JSON(http.StatusOK, models.AnalysisResponse{
-		Status:      "success",
-		Cached:      false,
-		Analysis:    analysis,
-		GeneratedAt: time.Now(),
+// ListPrompts handles GET /api/ai/prompts?kind=analysis
+func (h *AnalyzeHandler) ListPrompts(c *gin.Context) {
+	templates := h.aiService.Prompts().List(c.Query("kind"))
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(templates),
+		Data:      templates,
 	})
 }
+
+// storeRecord persists a fresh analysis; a storage failure doesn't fail the request
+func (h *AnalyzeHandler) storeRecord(record *models.AnalysisRecord) {
+	if err := h.history.Add(record); err != nil {
+		log.Printf("⚠️  Failed to store analysis %s for %s: %v", record.ID, record.Symbol, err)
+		return
+	}
+	log.Printf("✓ Stored analysis %s for: %s", record.ID, record.Symbol)
+}
+
+// analysisResponse wraps a stored analysis with its real generation metadata
+func analysisResponse(record *models.AnalysisRecord, cached bool) models.AnalysisResponse {
+	lang := models.NormalizeLang(record.Lang)
+	return models.AnalysisResponse{
+		Status:        "success",
+		Cached:        cached,
+		AnalysisID:    record.ID,
+		Model:         record.Model,
+		PromptVersion: record.PromptVersion,
+		Lang:          lang,
+		Analysis:      record.Analysis,
+		ToolCalls:     record.ToolCalls,
+		Guardrails:    record.Guardrails,
+		Disclaimer:    models.Disclaimer(lang),
+		GeneratedAt:   record.GeneratedAt,
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"backend/models"
+	"backend/services"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler manages API keys
+type APIKeyHandler struct {
+	auth *services.AuthService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(auth *services.AuthService) *APIKeyHandler {
+	return &APIKeyHandler{auth: auth}
+}
+
+// CreateAPIKey handles POST /api/admin/keys; the key is only returned in this response
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req models.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid request body: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+	key, err := h.auth.Create(req)
+	if err != nil {
+		writeAPIKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      key,
+	})
+}
+
+// GetAPIKeys handles GET /api/admin/keys
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys := h.auth.List()
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(keys),
+		Data:      keys,
+	})
+}
+
+// DeleteAPIKey handles DELETE /api/admin/keys/:id
+func (h *APIKeyHandler) DeleteAPIKey(c *gin.Context) {
+	if err := h.auth.Delete(c.Param("id")); err != nil {
+		writeAPIKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+	})
+}
+
+// GetCurrentPrincipal handles GET /api/auth/me: the role and rate limit of the calling key
+func (h *APIKeyHandler) GetCurrentPrincipal(c *gin.Context) {
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      requestPrincipal(c),
+	})
+}
+
+// CreateStreamToken handles POST /api/auth/stream-token: a short-lived credential for
+// GET /ws?stream_token=, since EventSource cannot send the API key header
+func (h *APIKeyHandler) CreateStreamToken(c *gin.Context) {
+	token, err := h.auth.IssueStreamToken(requestPrincipal(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Status:    "error",
+			Message:   err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      token,
+	})
+}
+
+// writeAPIKeyError maps API key errors to status codes
+func writeAPIKeyError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidAPIKey):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, models.ErrorResponse{
+		Status:    "error",
+		Message:   err.Error(),
+		Timestamp: time.Now(),
+	})
+}
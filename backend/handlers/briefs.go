@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"backend/models"
+	"backend/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const recentBriefsLimit = 30
+
+// BriefHandler serves the daily AI market briefs
+type BriefHandler struct {
+	briefs *services.BriefService
+}
+
+// NewBriefHandler creates a new brief handler
+func NewBriefHandler(briefs *services.BriefService) *BriefHandler {
+	return &BriefHandler{briefs: briefs}
+}
+
+// GetLatestBrief handles GET /api/briefs/latest
+func (h *BriefHandler) GetLatestBrief(c *gin.Context) {
+	record, found := h.briefs.Latest()
+	if !found {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Status:    "error",
+			Message:   "No market brief has been generated yet",
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      record,
+	})
+}
+
+// GetBriefs handles GET /api/briefs?date=YYYY-MM-DD; without a date it lists recent briefs
+func (h *BriefHandler) GetBriefs(c *gin.Context) {
+	date := c.Query("date")
+	if date == "" {
+		records := h.briefs.Recent(recentBriefsLimit)
+		c.JSON(http.StatusOK, models.APIResponse{
+			Status:    "success",
+			Timestamp: time.Now(),
+			Total:     len(records),
+			Data:      records,
+		})
+		return
+	}
+
+	if _, err := time.Parse(models.BriefDateLayout, date); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "date must be formatted as YYYY-MM-DD",
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	record, found := h.briefs.ForDate(date)
+	if !found {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Status:    "error",
+			Message:   "No market brief for " + date,
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      record,
+	})
+}
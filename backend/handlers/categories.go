@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"backend/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCategories handles GET /api/categories
+func (h *TokenHandler) GetCategories(c *gin.Context) {
+	// Make sure a scoring pass has run so benchmarks exist
+	if _, err := h.market.Tokens(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Failed to fetch token data: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	top := -1
+	if val, err := strconv.Atoi(c.Query("top")); err == nil && val >= 0 {
+		top = val
+	}
+	category := strings.ToLower(c.Query("category"))
+
+	benchmarks := make([]models.CategoryBenchmark, 0)
+	for _, bench := range h.scorer.CategoryBenchmarks() {
+		if category != "" && strings.ToLower(bench.Category) != category {
+			continue
+		}
+		if top >= 0 && len(bench.TopConstituents) > top {
+			bench.TopConstituents = bench.TopConstituents[:top]
+		}
+		benchmarks = append(benchmarks, bench)
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(benchmarks),
+		Data:      benchmarks,
+	})
+}
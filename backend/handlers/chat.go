@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"backend/models"
+	"backend/services"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChatHandler handles follow-up chat about a token
+type ChatHandler struct {
+	chat *services.ChatService
+}
+
+// NewChatHandler creates a new chat handler
+func NewChatHandler(chat *services.ChatService) *ChatHandler {
+	return &ChatHandler{chat: chat}
+}
+
+// Chat handles POST /api/chat
+func (h *ChatHandler) Chat(c *gin.Context) {
+	var req models.ChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid request body: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" || len(req.Message) > models.MaxChatMessageLength {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   fmt.Sprintf("message must be 1-%d characters", models.MaxChatMessageLength),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	resp, err := h.chat.Send(c.Request.Context(), requestOwner(c), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrChatTokenRequired), errors.Is(err, services.ErrChatTokenMismatch):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrChatSessionNotFound), errors.Is(err, services.ErrTokenNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrChatBudgetExhausted):
+			status = http.StatusConflict // per-session cap; waiting won't help
+		default:
+			log.Printf("❌ Chat failed (session %q): %v", req.SessionID, err)
+			writeAIError(c, err, "Failed to generate reply")
+			return
+		}
+		c.JSON(status, models.ErrorResponse{
+			Status:    "error",
+			Message:   err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetSession handles GET /api/chat/:id
+func (h *ChatHandler) GetSession(c *gin.Context) {
+	session, found := h.chat.Session(requestOwner(c), c.Param("id"))
+	if !found {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Status:    "error",
+			Message:   services.ErrChatSessionNotFound.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(session.Messages),
+		Data:      session,
+	})
+}
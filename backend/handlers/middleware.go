@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"backend/models"
+	"backend/services"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API key credentials: the X-API-Key header or an "Authorization: Bearer" header. EventSource
+// clients, which cannot set headers, exchange their key for a short-lived stream token; keys
+// are never read from the URL, where access logs and browser history keep them.
+const (
+	APIKeyHeader     = "X-API-Key"
+	StreamTokenParam = "stream_token"
+	principalKey     = "principal"
+)
+
+// redactedQueryParams are credentials masked in access logs
+var redactedQueryParams = []string{StreamTokenParam, "api_key"}
+
+// Rate limit response headers
+const (
+	RateLimitHeader          = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+)
+
+// Authenticate resolves the request's API key and spends one request of its rate limit.
+// An unknown key is rejected rather than treated as anonymous.
+func Authenticate(auth *services.AuthService) gin.HandlerFunc {
+	return authenticate(auth, func(c *gin.Context) (models.Principal, error) {
+		return auth.Authenticate(requestAPIKey(c))
+	})
+}
+
+// AuthenticateStream is Authenticate for the live stream, which also accepts a stream token
+// from POST /api/auth/stream-token in the stream_token query parameter
+func AuthenticateStream(auth *services.AuthService) gin.HandlerFunc {
+	return authenticate(auth, func(c *gin.Context) (models.Principal, error) {
+		if token := strings.TrimSpace(c.Query(StreamTokenParam)); token != "" {
+			return auth.AuthenticateStreamToken(token)
+		}
+		return auth.Authenticate(requestAPIKey(c))
+	})
+}
+
+func authenticate(auth *services.AuthService, resolve func(c *gin.Context) (models.Principal, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := resolve(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{
+				Status:    "error",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			})
+			return
+		}
+
+		decision := auth.Allow(principal, c.ClientIP())
+		if decision.Limit > 0 {
+			c.Header(RateLimitHeader, strconv.Itoa(decision.Limit))
+			c.Header(RateLimitRemainingHeader, strconv.Itoa(decision.Remaining))
+		}
+		if !decision.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, models.ErrorResponse{
+				Status:    "error",
+				Message:   "rate limit exceeded",
+				Timestamp: time.Now(),
+			})
+			return
+		}
+
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// RequireRole rejects requests whose role ranks below the given one: 401 without a key, 403 with one
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := requestPrincipal(c)
+		if models.RoleRank(principal.Role) >= models.RoleRank(role) {
+			c.Next()
+			return
+		}
+		status := http.StatusForbidden
+		if principal.Anonymous() {
+			status = http.StatusUnauthorized
+		}
+		c.AbortWithStatusJSON(status, models.ErrorResponse{
+			Status:    "error",
+			Message:   "this endpoint requires an API key with the " + role + " role",
+			Timestamp: time.Now(),
+		})
+	}
+}
+
+// RequireAPIKey rejects anonymous requests with 401, whatever the anonymous role
+func RequireAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requestPrincipal(c).Anonymous() {
+			c.Next()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{
+			Status:    "error",
+			Message:   "this endpoint requires an API key",
+			Timestamp: time.Now(),
+		})
+	}
+}
+
+// UsageClient tags each request context with the client AI usage is charged to: the API
+// key, otherwise the client IP. The identity is never taken from the request itself, so a
+// caller cannot spend another client's budget or reach the exempt system client.
+func UsageClient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client := "ip:" + c.ClientIP()
+		if principal := requestPrincipal(c); !principal.Anonymous() {
+			client = "key:" + principal.KeyID
+		}
+		c.Request = c.Request.WithContext(services.WithUsageClient(c.Request.Context(), client))
+		c.Next()
+	}
+}
+
+// requestAPIKey returns the presented API key, or "" if none
+func requestAPIKey(c *gin.Context) string {
+	if key := strings.TrimSpace(c.GetHeader(APIKeyHeader)); key != "" {
+		return key
+	}
+	if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(bearer)
+	}
+	return ""
+}
+
+// RedactedLogger is gin's access log with credentials in the query string masked
+func RedactedLogger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		if param.Latency > time.Minute {
+			param.Latency = param.Latency.Truncate(time.Second)
+		}
+		return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+			param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			param.StatusCode,
+			param.Latency,
+			param.ClientIP,
+			param.Method,
+			redactQuery(param.Path),
+			param.ErrorMessage,
+		)
+	})
+}
+
+// redactQuery masks the values of redactedQueryParams in a request path with a query string
+func redactQuery(path string) string {
+	base, query, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		name, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil && slices.Contains(redactedQueryParams, unescaped) {
+			params[i] = name + "=REDACTED"
+		}
+	}
+	return base + "?" + strings.Join(params, "&")
+}
+
+// requestPrincipal returns the caller set by Authenticate (a role-less anonymous principal if unset)
+func requestPrincipal(c *gin.Context) models.Principal {
+	if value, ok := c.Get(principalKey); ok {
+		if principal, ok := value.(models.Principal); ok {
+			return principal
+		}
+	}
+	return models.Principal{}
+}
+
+// requestOwner returns the client that owns the watchlists, portfolios, alerts and chat sessions a request touches.
+// Only API keys can write them (RequireAPIKey), so anonymous callers just read their own, empty, state.
+func requestOwner(c *gin.Context) string {
+	return services.UsageClient(c.Request.Context())
+}
+
+// writeAIError responds 429 with Retry-After for an exhausted AI budget, 500 otherwise
+func writeAIError(c *gin.Context, err error, message string) {
+	var budgetErr *services.BudgetError
+	if errors.As(err, &budgetErr) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(budgetErr.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+			Status:    "error",
+			Message:   budgetErr.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Status:    "error",
+		Message:   message + ": " + err.Error(),
+		Timestamp: time.Now(),
+	})
+}
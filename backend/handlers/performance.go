@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"backend/models"
+	"backend/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PerformanceHandler reports how AI recommendations played out
+type PerformanceHandler struct {
+	backtester *services.Backtester
+}
+
+// NewPerformanceHandler creates a new performance handler
+func NewPerformanceHandler(backtester *services.Backtester) *PerformanceHandler {
+	return &PerformanceHandler{backtester: backtester}
+}
+
+// GetAIPerformance handles GET /api/ai/performance?model=&prompt_version=
+func (h *PerformanceHandler) GetAIPerformance(c *gin.Context) {
+	report := h.backtester.Performance(c.Query("model"), c.Query("prompt_version"))
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(report),
+		Data:      report,
+	})
+}
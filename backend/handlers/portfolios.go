@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"backend/models"
+	"backend/services"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRiskWindow is the history window of portfolio risk reports
+const defaultRiskWindow = "90d"
+
+// PortfolioHandler manages, values and risk-profiles the calling client's portfolios
+type PortfolioHandler struct {
+	portfolios *services.PortfolioService
+	analytics  *services.MarketAnalytics
+}
+
+// NewPortfolioHandler creates a new portfolio handler
+func NewPortfolioHandler(portfolios *services.PortfolioService, analytics *services.MarketAnalytics) *PortfolioHandler {
+	return &PortfolioHandler{portfolios: portfolios, analytics: analytics}
+}
+
+// CreatePortfolio handles POST /api/portfolios
+func (h *PortfolioHandler) CreatePortfolio(c *gin.Context) {
+	var req models.PortfolioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid request body: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+	portfolio, err := h.portfolios.Create(c.Request.Context(), requestOwner(c), req)
+	if err != nil {
+		writePortfolioError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      portfolio,
+	})
+}
+
+// GetPortfolios handles GET /api/portfolios; every portfolio is valued
+func (h *PortfolioHandler) GetPortfolios(c *gin.Context) {
+	details, err := h.portfolios.ListDetails(c.Request.Context(), requestOwner(c))
+	if err != nil {
+		writePortfolioError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(details),
+		Data:      details,
+	})
+}
+
+// GetPortfolio handles GET /api/portfolios/:id with valuation, trust score, concentration and sector exposure
+func (h *PortfolioHandler) GetPortfolio(c *gin.Context) {
+	detail, err := h.portfolios.Detail(c.Request.Context(), requestOwner(c), c.Param("id"))
+	if err != nil {
+		writePortfolioError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(detail.Holdings),
+		Data:      detail,
+	})
+}
+
+// GetPortfolioRisk handles GET /api/portfolios/:id/risk?window=90d
+func (h *PortfolioHandler) GetPortfolioRisk(c *gin.Context) {
+	window, err := parseWindowDays(c.DefaultQuery("window", defaultRiskWindow))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	detail, err := h.portfolios.Detail(c.Request.Context(), requestOwner(c), c.Param("id"))
+	if err != nil {
+		writePortfolioError(c, err)
+		return
+	}
+	report, err := h.analytics.PortfolioRisk(c.Request.Context(), detail.ID, detail.Valuation, window)
+	if err != nil {
+		log.Printf("❌ Risk computation failed for portfolio %s: %v", detail.ID, err)
+		writePortfolioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(report.Holdings),
+		Data:      report,
+	})
+}
+
+// UpdatePortfolio handles PUT /api/portfolios/:id, replacing the name and all holdings
+func (h *PortfolioHandler) UpdatePortfolio(c *gin.Context) {
+	var req models.PortfolioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid request body: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+	portfolio, err := h.portfolios.Update(c.Request.Context(), requestOwner(c), c.Param("id"), req)
+	h.respond(c, portfolio, err)
+}
+
+// AddHolding handles POST /api/portfolios/:id/holdings; an existing position grows by the quantity and cost basis
+func (h *PortfolioHandler) AddHolding(c *gin.Context) {
+	var req models.HoldingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid request body: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+	portfolio, err := h.portfolios.AddHolding(c.Request.Context(), requestOwner(c), c.Param("id"), req)
+	h.respond(c, portfolio, err)
+}
+
+// RemoveHolding handles DELETE /api/portfolios/:id/holdings/:token_id
+func (h *PortfolioHandler) RemoveHolding(c *gin.Context) {
+	portfolio, err := h.portfolios.RemoveHolding(requestOwner(c), c.Param("id"), c.Param("token_id"))
+	h.respond(c, portfolio, err)
+}
+
+// DeletePortfolio handles DELETE /api/portfolios/:id
+func (h *PortfolioHandler) DeletePortfolio(c *gin.Context) {
+	if err := h.portfolios.Delete(requestOwner(c), c.Param("id")); err != nil {
+		writePortfolioError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+	})
+}
+
+// respond writes the updated portfolio, or maps the error
+func (h *PortfolioHandler) respond(c *gin.Context, portfolio *models.Portfolio, err error) {
+	if err != nil {
+		writePortfolioError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      portfolio,
+	})
+}
+
+// writePortfolioError maps portfolio errors to status codes
+func writePortfolioError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidPortfolio):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrPortfolioNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrPortfolioLimit):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrInsufficientHistory):
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, models.ErrorResponse{
+		Status:    "error",
+		Message:   err.Error(),
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"backend/models"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStablecoins handles GET /api/stablecoins
+func (h *TokenHandler) GetStablecoins(c *gin.Context) {
+	tokens, err := h.market.Tokens(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Failed to fetch token data: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	stablecoins := make([]models.Token, 0)
+	for _, token := range tokens {
+		if token.ScoreBreakdown.Stablecoin != nil {
+			stablecoins = append(stablecoins, token)
+		}
+	}
+
+	// Largest supply first
+	sort.Slice(stablecoins, func(i, j int) bool {
+		return stablecoins[i].MarketCap > stablecoins[j].MarketCap
+	})
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(stablecoins),
+		Data:      stablecoins,
+	})
+}
+
+// GetDepegEvents handles GET /api/stablecoins/depegs
+func (h *TokenHandler) GetDepegEvents(c *gin.Context) {
+	limit := 100
+	if val, err := strconv.Atoi(c.Query("limit")); err == nil && val > 0 {
+		limit = val
+	}
+
+	events := h.scorer.DepegMonitor().Events(limit)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(events),
+		Data:      events,
+	})
+}
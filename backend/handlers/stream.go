@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/services"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// streamWriteTimeout drops clients that stop reading; they can resume with their last event ID
+const streamWriteTimeout = 10 * time.Second
+
+// StreamHandler serves live token updates
+type StreamHandler struct {
+	stream     *services.LiveStream
+	marketData *services.MarketData
+	cfg        *config.Config
+}
+
+// NewStreamHandler creates a new live stream handler
+func NewStreamHandler(stream *services.LiveStream, marketData *services.MarketData, cfg *config.Config) *StreamHandler {
+	return &StreamHandler{
+		stream:     stream,
+		marketData: marketData,
+		cfg:        cfg,
+	}
+}
+
+// Stream handles GET /ws?ids=btc,eth&filter=...&resume=...&stream_token=... as Server-Sent Events.
+// The first event is a snapshot of the subscription, followed by an update with the
+// diff of every new market snapshot. Each event ID is a resume token, so a reconnecting
+// EventSource picks up where it left off through the Last-Event-ID header.
+func (h *StreamHandler) Stream(c *gin.Context) {
+	var req models.StreamRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid query parameters: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	// New subscriptions start from real data rather than an empty snapshot
+	if _, err := h.marketData.Tokens(c.Request.Context()); err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Market data unavailable: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	resume := req.Resume
+	if resume == "" {
+		resume = c.GetHeader("Last-Event-ID")
+	}
+	conn, initial, err := h.stream.Attach(req, resume)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrInvalidStream):
+			status = http.StatusBadRequest
+		case errors.Is(err, services.ErrStreamLimit):
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, models.ErrorResponse{
+			Status:    "error",
+			Message:   err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+	defer h.stream.Detach(conn)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+
+	// A write deadline turns a stalled client into a write error instead of a stuck goroutine
+	rc := http.NewResponseController(c.Writer)
+	write := func(messages []services.StreamMessage) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		for _, msg := range messages {
+			if err := sse.Encode(c.Writer, sse.Event{Id: msg.ID, Event: msg.Event, Data: msg.Data}); err != nil {
+				return false
+			}
+		}
+		return rc.Flush() == nil
+	}
+
+	if !write(initial) {
+		return
+	}
+
+	var heartbeat <-chan time.Time
+	if h.cfg.StreamHeartbeatInterval > 0 {
+		ticker := time.NewTicker(h.cfg.StreamHeartbeatInterval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-conn.Done():
+			log.Printf("🔁 Stream subscription resumed on another connection")
+			return
+		case <-conn.Notify():
+			if !write(h.stream.Pending(conn)) {
+				return
+			}
+		case <-heartbeat:
+			if !write([]services.StreamMessage{h.stream.Heartbeat()}) {
+				return
+			}
+		}
+	}
+}
@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"backend/cache"
-	"backend/config"
 	"backend/models"
 	"backend/services"
 	"log"
@@ -19,22 +17,19 @@ import (
 type TokenHandler struct {
 	aggregator *services.Aggregator
 	scorer     *services.EnhancedScorer
-	cache      *cache.CacheManager
-	config     *config.Config
+	market     *services.MarketData
 }
 
 // NewTokenHandler creates a new token handler
 func NewTokenHandler(
 	aggregator *services.Aggregator,
 	scorer *services.EnhancedScorer,
-	cacheManager *cache.CacheManager,
-	cfg *config.Config,
+	market *services.MarketData,
 ) *TokenHandler {
 	return &TokenHandler{
 		aggregator: aggregator,
 		scorer:     scorer,
-		cache:      cacheManager,
-		config:     cfg,
+		market:     market,
 	}
 }
 
@@ -45,31 +40,8 @@ func (h *TokenHandler) GetTokens(c *gin.Context) {
 	// Parse query parameters
 	params := h.parseFilterParams(c)
 
-	// Create cache key based on params
-	cacheKey := h.buildCacheKey(params)
-
-	// Try cache first
-	if cached, found := h.cache.GetTokens(cacheKey); found {
-		log.Printf("✓ Cache hit for key: %s", cacheKey)
-
-		tokens := cached.([]models.Token)
-		filtered, total, hasMore := h.filterAndSortTokens(tokens, params)
-
-		c.JSON(http.StatusOK, models.TokensResponse{
-			Status:      "success",
-			Timestamp:   time.Now(),
-			Total:       total,
-			Data:        filtered,
-			HasMore:     hasMore,
-			FetchTimeMs: time.Since(startTime).Milliseconds(),
-		})
-		return
-	}
-
-	log.Printf("✗ Cache miss for key: %s - fetching fresh data", cacheKey)
-
-	// Fetch fresh data
-	tokens, err := h.aggregator.FetchAllTokenData(c.Request.Context())
+	// The scored snapshot is cached and shared with every other consumer
+	tokens, err := h.market.Tokens(c.Request.Context())
 	if err != nil {
 		log.Printf("❌ Failed to fetch token data: %v", err)
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -80,13 +52,6 @@ func (h *TokenHandler) GetTokens(c *gin.Context) {
 		return
 	}
 
-	// Calculate scores for all tokens
-	tokens = h.scorer.CalculateScoresForAll(tokens)
-
-	// Cache the results
-	h.cache.SetTokens(cacheKey, tokens)
-	log.Printf("✓ Cached %d tokens with key: %s", len(tokens), cacheKey)
-
 	// Filter and sort
 	filtered, total, hasMore := h.filterAndSortTokens(tokens, params)
 
@@ -121,31 +86,10 @@ func (h *TokenHandler) GetTokenByID(c *gin.Context) {
 		return
 	}
 
-	// Try to find the token in the "tokens_all" cache
-	if cached, found := h.cache.GetTokens("tokens_all"); found {
-		tokens := cached.([]models.Token)
-		for _, token := range tokens {
-			// Check if ID (coingecko id) or Symbol matches
-			// Note: We use lowercase comparison for loose matching
-			normalizedId := strings.ToLower(id)
-			if strings.ToLower(token.ID) == normalizedId || strings.ToLower(token.Symbol) == normalizedId || strings.ToLower(token.Name) == normalizedId {
-				c.JSON(http.StatusOK, token)
-				return
-			}
-		}
-	}
-
-	// If not in cache or not found, try a fresh fetch
-	tokens, err := h.aggregator.FetchAllTokenData(c.Request.Context())
-	if err == nil {
-		h.cache.SetTokens("tokens_all", tokens)
-		for _, token := range tokens {
-			normalizedId := strings.ToLower(id)
-			if strings.ToLower(token.ID) == normalizedId || strings.ToLower(token.Symbol) == normalizedId || strings.ToLower(token.Name) == normalizedId {
-				c.JSON(http.StatusOK, token)
-				return
-			}
-		}
+	token, found, err := h.market.FindToken(c.Request.Context(), id)
+	if err == nil && found {
+		c.JSON(http.StatusOK, token)
+		return
 	}
 
 	c.JSON(http.StatusNotFound, gin.H{"error": "token not found"})
@@ -217,6 +161,13 @@ func (h *TokenHandler) parseFilterParams(c *gin.Context) models.FilterParams {
 		}
 	}
 
+	// Confidence floor
+	if minConfidence := c.Query("min_confidence"); minConfidence != "" {
+		if val, err := strconv.ParseFloat(minConfidence, 64); err == nil {
+			params.MinConfidence = val
+		}
+	}
+
 	// Limit
 	if limit := c.Query("limit"); limit != "" {
 		if val, err := strconv.Atoi(limit); err == nil {
@@ -238,11 +189,6 @@ func (h *TokenHandler) parseFilterParams(c *gin.Context) models.FilterParams {
 	return params
 }
 
-// buildCacheKey creates a cache key from filter params
-func (h *TokenHandler) buildCacheKey(params models.FilterParams) string {
-	return "tokens_all" // Simple key for now; can be enhanced with params
-}
-
 // filterAndSortTokens applies filters and sorting, then returns the subset, total count and hasMore info
 func (h *TokenHandler) filterAndSortTokens(tokens []models.Token, params models.FilterParams) ([]models.Token, int, bool) {
 	filtered := make([]models.Token, 0)
@@ -290,6 +236,11 @@ func (h *TokenHandler) filterAndSortTokens(tokens []models.Token, params models.
 			continue
 		}
 
+		// Confidence filter
+		if params.MinConfidence > 0 && token.ScoreBreakdown.Confidence < params.MinConfidence {
+			continue
+		}
+
 		// Change filter
 		if params.MinChange != 0 && token.Change24h < params.MinChange {
 			continue
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"backend/models"
+	"backend/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultUsageDays = 7
+	maxUsageDays     = 90
+)
+
+// UsageHandler reports AI usage and budgets
+type UsageHandler struct {
+	meter *services.UsageMeter
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(meter *services.UsageMeter) *UsageHandler {
+	return &UsageHandler{meter: meter}
+}
+
+// GetAIUsage handles GET /api/admin/ai/usage?days=7
+func (h *UsageHandler) GetAIUsage(c *gin.Context) {
+	days := defaultUsageDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > maxUsageDays {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Status:    "error",
+				Message:   "days must be between 1 and " + strconv.Itoa(maxUsageDays),
+				Timestamp: time.Now(),
+			})
+			return
+		}
+		days = parsed
+	}
+
+	report := h.meter.Report(days)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(report.Days),
+		Data:      report,
+	})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"backend/models"
+	"backend/services"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WatchlistHandler manages the calling client's watchlists
+type WatchlistHandler struct {
+	watchlists *services.WatchlistService
+}
+
+// NewWatchlistHandler creates a new watchlist handler
+func NewWatchlistHandler(watchlists *services.WatchlistService) *WatchlistHandler {
+	return &WatchlistHandler{watchlists: watchlists}
+}
+
+// CreateWatchlist handles POST /api/watchlists
+func (h *WatchlistHandler) CreateWatchlist(c *gin.Context) {
+	var req models.WatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid request body: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+	watchlist, err := h.watchlists.Create(c.Request.Context(), requestOwner(c), req)
+	if err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      watchlist,
+	})
+}
+
+// GetWatchlists handles GET /api/watchlists
+func (h *WatchlistHandler) GetWatchlists(c *gin.Context) {
+	watchlists := h.watchlists.List(requestOwner(c))
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(watchlists),
+		Data:      watchlists,
+	})
+}
+
+// GetWatchlist handles GET /api/watchlists/:id, including the tokens' current data
+func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
+	detail, err := h.watchlists.Get(c.Request.Context(), requestOwner(c), c.Param("id"))
+	if err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(detail.Tokens),
+		Data:      detail,
+	})
+}
+
+// UpdateWatchlist handles PUT /api/watchlists/:id
+func (h *WatchlistHandler) UpdateWatchlist(c *gin.Context) {
+	var req models.WatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid request body: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+	watchlist, err := h.watchlists.Update(c.Request.Context(), requestOwner(c), c.Param("id"), req)
+	h.respond(c, watchlist, err)
+}
+
+// AddWatchlistToken handles POST /api/watchlists/:id/tokens
+func (h *WatchlistHandler) AddWatchlistToken(c *gin.Context) {
+	var req models.WatchlistTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid request body: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+	watchlist, err := h.watchlists.AddToken(c.Request.Context(), requestOwner(c), c.Param("id"), req.TokenID)
+	h.respond(c, watchlist, err)
+}
+
+// RemoveWatchlistToken handles DELETE /api/watchlists/:id/tokens/:token_id
+func (h *WatchlistHandler) RemoveWatchlistToken(c *gin.Context) {
+	watchlist, err := h.watchlists.RemoveToken(requestOwner(c), c.Param("id"), c.Param("token_id"))
+	h.respond(c, watchlist, err)
+}
+
+// DeleteWatchlist handles DELETE /api/watchlists/:id
+func (h *WatchlistHandler) DeleteWatchlist(c *gin.Context) {
+	if err := h.watchlists.Delete(requestOwner(c), c.Param("id")); err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+	})
+}
+
+// respond writes the updated watchlist, or maps the error
+func (h *WatchlistHandler) respond(c *gin.Context, watchlist *models.Watchlist, err error) {
+	if err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      watchlist,
+	})
+}
+
+// writeWatchlistError maps watchlist errors to status codes
+func writeWatchlistError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidWatchlist):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrWatchlistNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrWatchlistLimit):
+		status = http.StatusConflict
+	}
+	c.JSON(status, models.ErrorResponse{
+		Status:    "error",
+		Message:   err.Error(),
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"backend/models"
+	"backend/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// WebhookHandler manages notification webhooks
+type WebhookHandler struct {
+	notifier *services.Notifier
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(notifier *services.Notifier) *WebhookHandler {
+	return &WebhookHandler{notifier: notifier}
+}
+
+// CreateWebhook handles POST /api/webhooks; the response is the only time the signing secret is shown
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req models.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Status:    "error",
+			Message:   "Invalid request body: " + err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	webhook, err := h.notifier.Create(req)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      webhook,
+	})
+}
+
+// GetWebhooks handles GET /api/webhooks
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	webhooks := h.notifier.List()
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(webhooks),
+		Data:      webhooks,
+	})
+}
+
+// DeleteWebhook handles DELETE /api/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.notifier.Delete(c.Param("id")); err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+	})
+}
+
+// EnableWebhook handles POST /api/webhooks/:id/enable after an automatic disable
+func (h *WebhookHandler) EnableWebhook(c *gin.Context) {
+	webhook, err := h.notifier.Enable(c.Param("id"))
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      webhook,
+	})
+}
+
+// TestWebhook handles POST /api/webhooks/:id/test
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	delivery, err := h.notifier.SendTest(c.Param("id"))
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     1,
+		Data:      delivery,
+	})
+}
+
+// GetDeliveries handles GET /api/webhooks/:id/deliveries?limit=50
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	limit := defaultDeliveryLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > maxDeliveryLimit {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Status:    "error",
+				Message:   "limit must be between 1 and " + strconv.Itoa(maxDeliveryLimit),
+				Timestamp: time.Now(),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.notifier.Deliveries(c.Param("id"), limit)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:    "success",
+		Timestamp: time.Now(),
+		Total:     len(deliveries),
+		Data:      deliveries,
+	})
+}
+
+// writeWebhookError maps notifier errors to status codes
+func writeWebhookError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhook):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrWebhookNotFound):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, models.ErrorResponse{
+		Status:    "error",
+		Message:   err.Error(),
+		Timestamp: time.Now(),
+	})
+}
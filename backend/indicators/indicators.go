@@ -0,0 +1,272 @@
+// Package indicators computes full technical indicator series from OHLCV candles.
+//
+// Every function returns slices aligned with its input; positions inside the
+// warm-up period are NaN so callers can keep timestamps in step.
+package indicators
+
+import (
+	"backend/models"
+	"math"
+)
+
+// nanSeries returns a series of length n filled with NaN
+func nanSeries(n int) []float64 {
+	series := make([]float64, n)
+	for i := range series {
+		series[i] = math.NaN()
+	}
+	return series
+}
+
+// Closes extracts closing prices from candles
+func Closes(candles []models.Candle) []float64 {
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close
+	}
+	return closes
+}
+
+// SMA computes the simple moving average series
+func SMA(values []float64, period int) []float64 {
+	result := nanSeries(len(values))
+	if period <= 0 || len(values) < period {
+		return result
+	}
+
+	sum := 0.0
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			result[i] = sum / float64(period)
+		}
+	}
+	return result
+}
+
+// EMA computes the exponential moving average series, seeded with the first SMA.
+// Leading NaNs in the input (e.g. another indicator's warm-up) are skipped.
+func EMA(values []float64, period int) []float64 {
+	result := nanSeries(len(values))
+	if period <= 0 {
+		return result
+	}
+
+	start := 0
+	for start < len(values) && math.IsNaN(values[start]) {
+		start++
+	}
+	if len(values)-start < period {
+		return result
+	}
+
+	multiplier := 2.0 / float64(period+1)
+
+	seed := 0.0
+	for i := start; i < start+period; i++ {
+		seed += values[i]
+	}
+	ema := seed / float64(period)
+	result[start+period-1] = ema
+
+	for i := start + period; i < len(values); i++ {
+		ema = (values[i]-ema)*multiplier + ema
+		result[i] = ema
+	}
+	return result
+}
+
+// RSI computes the Relative Strength Index with Wilder smoothing
+func RSI(closes []float64, period int) []float64 {
+	result := nanSeries(len(closes))
+	if period <= 0 || len(closes) < period+1 {
+		return result
+	}
+
+	// Seed averages with the first `period` changes
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		change := closes[i] - closes[i-1]
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+	}
+	avgGain := gain / float64(period)
+	avgLoss := loss / float64(period)
+	result[period] = rsiValue(avgGain, avgLoss)
+
+	// Wilder smoothing for every later change
+	for i := period + 1; i < len(closes); i++ {
+		change := closes[i] - closes[i-1]
+		currentGain, currentLoss := 0.0, 0.0
+		if change > 0 {
+			currentGain = change
+		} else {
+			currentLoss = -change
+		}
+		avgGain = (avgGain*float64(period-1) + currentGain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + currentLoss) / float64(period)
+		result[i] = rsiValue(avgGain, avgLoss)
+	}
+	return result
+}
+
+func rsiValue(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50.0 // Flat series
+		}
+		return 100.0
+	}
+	rs := avgGain / avgLoss
+	return 100 - (100 / (1 + rs))
+}
+
+// MACD computes the MACD line, its signal line and the histogram
+func MACD(closes []float64, fast, slow, signal int) (macdLine, signalLine, histogram []float64) {
+	fastEMA := EMA(closes, fast)
+	slowEMA := EMA(closes, slow)
+
+	macdLine = nanSeries(len(closes))
+	for i := range closes {
+		if !math.IsNaN(fastEMA[i]) && !math.IsNaN(slowEMA[i]) {
+			macdLine[i] = fastEMA[i] - slowEMA[i]
+		}
+	}
+
+	signalLine = EMA(macdLine, signal)
+
+	histogram = nanSeries(len(closes))
+	for i := range closes {
+		if !math.IsNaN(macdLine[i]) && !math.IsNaN(signalLine[i]) {
+			histogram[i] = macdLine[i] - signalLine[i]
+		}
+	}
+	return macdLine, signalLine, histogram
+}
+
+// BollingerBands computes the middle SMA and upper/lower bands at k standard deviations
+func BollingerBands(closes []float64, period int, k float64) (upper, middle, lower []float64) {
+	middle = SMA(closes, period)
+	upper = nanSeries(len(closes))
+	lower = nanSeries(len(closes))
+
+	for i := period - 1; i < len(closes) && i >= 0; i++ {
+		if math.IsNaN(middle[i]) {
+			continue
+		}
+		sumSquares := 0.0
+		for j := i - period + 1; j <= i; j++ {
+			diff := closes[j] - middle[i]
+			sumSquares += diff * diff
+		}
+		stdDev := math.Sqrt(sumSquares / float64(period))
+		upper[i] = middle[i] + k*stdDev
+		lower[i] = middle[i] - k*stdDev
+	}
+	return upper, middle, lower
+}
+
+// ATR computes the Average True Range with Wilder smoothing
+func ATR(candles []models.Candle, period int) []float64 {
+	result := nanSeries(len(candles))
+	if period <= 0 || len(candles) < period+1 {
+		return result
+	}
+
+	trueRange := func(i int) float64 {
+		high, low, prevClose := candles[i].High, candles[i].Low, candles[i-1].Close
+		return math.Max(high-low, math.Max(math.Abs(high-prevClose), math.Abs(low-prevClose)))
+	}
+
+	sum := 0.0
+	for i := 1; i <= period; i++ {
+		sum += trueRange(i)
+	}
+	atr := sum / float64(period)
+	result[period] = atr
+
+	for i := period + 1; i < len(candles); i++ {
+		atr = (atr*float64(period-1) + trueRange(i)) / float64(period)
+		result[i] = atr
+	}
+	return result
+}
+
+// Stochastic computes the %K oscillator and its %D signal (SMA of %K)
+func Stochastic(candles []models.Candle, kPeriod, dPeriod int) (k, d []float64) {
+	k = nanSeries(len(candles))
+	if kPeriod <= 0 || len(candles) < kPeriod {
+		return k, nanSeries(len(candles))
+	}
+
+	for i := kPeriod - 1; i < len(candles); i++ {
+		highest, lowest := candles[i].High, candles[i].Low
+		for j := i - kPeriod + 1; j <= i; j++ {
+			highest = math.Max(highest, candles[j].High)
+			lowest = math.Min(lowest, candles[j].Low)
+		}
+		if highest == lowest {
+			k[i] = 50.0
+			continue
+		}
+		k[i] = (candles[i].Close - lowest) / (highest - lowest) * 100
+	}
+
+	// %D skips the %K warm-up
+	d = nanSeries(len(candles))
+	valid := SMA(k[kPeriod-1:], dPeriod)
+	copy(d[kPeriod-1:], valid)
+	return k, d
+}
+
+// OBV computes On-Balance Volume
+func OBV(candles []models.Candle) []float64 {
+	result := make([]float64, len(candles))
+	for i := 1; i < len(candles); i++ {
+		switch {
+		case candles[i].Close > candles[i-1].Close:
+			result[i] = result[i-1] + candles[i].Volume
+		case candles[i].Close < candles[i-1].Close:
+			result[i] = result[i-1] - candles[i].Volume
+		default:
+			result[i] = result[i-1]
+		}
+	}
+	return result
+}
+
+// VWAP computes the volume-weighted average price anchored at the first candle
+func VWAP(candles []models.Candle) []float64 {
+	result := nanSeries(len(candles))
+
+	var cumulativePV, cumulativeVolume float64
+	for i, candle := range candles {
+		typical := (candle.High + candle.Low + candle.Close) / 3
+		// Volume here is quoted in USD, so convert back to base units
+		if typical > 0 {
+			baseVolume := candle.Volume / typical
+			cumulativePV += typical * baseVolume
+			cumulativeVolume += baseVolume
+		}
+		if cumulativeVolume > 0 {
+			result[i] = cumulativePV / cumulativeVolume
+		}
+	}
+	return result
+}
+
+// Latest returns the last non-NaN value of a series, or 0
+func Latest(series []float64) float64 {
+	for i := len(series) - 1; i >= 0; i-- {
+		if !math.IsNaN(series[i]) {
+			return series[i]
+		}
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package indicators
+
+import (
+	"backend/models"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Output is a named group of aligned series, e.g. "macd" -> {macd, signal, histogram}
+type Output map[string][]float64
+
+// calculator computes one indicator group with its default parameters
+type calculator func(candles []models.Candle) Output
+
+var registry = map[string]calculator{
+	"sma": func(c []models.Candle) Output {
+		closes := Closes(c)
+		return Output{"sma_20": SMA(closes, 20), "sma_50": SMA(closes, 50)}
+	},
+	"ema": func(c []models.Candle) Output {
+		closes := Closes(c)
+		return Output{"ema_12": EMA(closes, 12), "ema_26": EMA(closes, 26)}
+	},
+	"rsi": func(c []models.Candle) Output {
+		return Output{"rsi": RSI(Closes(c), 14)}
+	},
+	"macd": func(c []models.Candle) Output {
+		macd, signal, histogram := MACD(Closes(c), 12, 26, 9)
+		return Output{"macd": macd, "signal": signal, "histogram": histogram}
+	},
+	"bb": func(c []models.Candle) Output {
+		upper, middle, lower := BollingerBands(Closes(c), 20, 2)
+		return Output{"upper": upper, "middle": middle, "lower": lower}
+	},
+	"atr": func(c []models.Candle) Output {
+		return Output{"atr": ATR(c, 14)}
+	},
+	"stoch": func(c []models.Candle) Output {
+		k, d := Stochastic(c, 14, 3)
+		return Output{"k": k, "d": d}
+	},
+	"obv": func(c []models.Candle) Output {
+		return Output{"obv": OBV(c)}
+	},
+	"vwap": func(c []models.Candle) Output {
+		return Output{"vwap": VWAP(c)}
+	},
+}
+
+// Available lists the supported indicator set names
+func Available() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Compute runs each named indicator over the candles
+func Compute(candles []models.Candle, names []string) (map[string]Output, error) {
+	results := make(map[string]Output, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		calc, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown indicator %q (available: %s)", name, strings.Join(Available(), ", "))
+		}
+		results[name] = calc(candles)
+	}
+	return results, nil
+}
+
+// ToPoints pairs a series with candle timestamps, dropping warm-up NaNs
+func ToPoints(candles []models.Candle, series []float64) []models.IndicatorPoint {
+	points := make([]models.IndicatorPoint, 0, len(series))
+	for i, value := range series {
+		if i >= len(candles) || math.IsNaN(value) {
+			continue
+		}
+		points = append(points, models.IndicatorPoint{Time: candles[i].Time, Value: value})
+	}
+	return points
+}
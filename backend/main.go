@@ -4,10 +4,16 @@ import (
 	"backend/cache"
 	"backend/config"
 	"backend/handlers"
+	"backend/models"
+	"backend/prompts"
 	"backend/services"
+	"backend/store"
+	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 
 	"github.com/gin-contrib/cors"
@@ -26,46 +32,168 @@ func main() {
 	gin.SetMode(cfg.GinMode)
 
 	// Initialize services
-	cacheManager := cache.NewCacheManager(cfg.TokenCacheDuration, cfg.AnalysisCacheDuration)
+	cacheManager := cache.NewCacheManager(cfg.TokenCacheDuration, cfg.AnalysisCacheDuration, cfg.ChatSessionDuration)
 	log.Printf("✅ Cache manager initialized (Token: %v, Analysis: %v)", cfg.TokenCacheDuration, cfg.AnalysisCacheDuration)
 
+	dataStore, err := store.New(cfg.DataDir)
+	if err != nil {
+		log.Fatalf("❌ Failed to open data store: %v", err)
+	}
+	log.Printf("✅ Data store initialized (%s)", dataStore.Dir())
+
 	aggregator := services.NewAggregator(cfg)
 	log.Println("✅ Data aggregator initialized")
 
-	scorer := services.NewEnhancedScorer()
+	scorer := services.NewEnhancedScorer(cfg)
 	log.Println("✅ Enhanced scorer initialized")
 
-	// Initialize AI service (may fail if API key not set)
-	aiService, err := services.NewAIService(cfg)
+	marketData := services.NewMarketData(aggregator, scorer, cacheManager)
+	historyService := services.NewHistoryService(cfg)
+	analytics := services.NewMarketAnalytics(historyService, marketData, cfg)
+	analysisContext := services.NewAnalysisContextBuilder(marketData, historyService, analytics)
+	log.Println("✅ History & analytics services initialized")
+
+	watchlistService := services.NewWatchlistService(dataStore, marketData)
+	portfolioService := services.NewPortfolioService(dataStore, marketData, scorer)
+	log.Println("✅ Watchlist & portfolio services initialized")
+
+	authService := services.NewAuthService(dataStore, cfg)
+	anonymousAccess := authService.AnonymousRole()
+	if anonymousAccess == "" {
+		anonymousAccess = "none"
+	}
+	log.Printf("✅ API key auth initialized (%d keys, anonymous role: %s, rate limits: %d/min per key, %d/min per anonymous IP)",
+		len(authService.List()), anonymousAccess, cfg.APIKeyRateLimit, cfg.AnonymousRateLimit)
+	if !authService.HasAdmin() {
+		log.Println("⚠️  No admin API key - set ADMIN_API_KEY to manage keys via /api/admin/keys")
+	}
+
+	analysisHistory := services.NewAnalysisHistory(dataStore, cfg)
+	backtester := services.NewBacktester(analysisHistory, historyService, dataStore)
+
+	// Background jobs stop when main returns
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go backtester.Run(jobsCtx, cfg.BacktestInterval)
+	log.Printf("✅ Recommendation backtester scheduled (every %v)", cfg.BacktestInterval)
+
+	// Token.BetaToMarket comes from a background beta job, applied to every snapshot
+	marketData.UseBetas(analytics.MarketBeta)
+	if cfg.BetaRefreshInterval > 0 {
+		go analytics.RunBetas(jobsCtx, cfg.BetaRefreshInterval)
+	}
+	log.Printf("✅ Market beta job scheduled (every %v, top %d tokens)", cfg.BetaRefreshInterval, cfg.BetaTokenLimit)
+
+	// Notifications are delivered to webhooks from a persistent outbox
+	notifier := services.NewNotifier(dataStore, cfg)
+	go notifier.Run(jobsCtx)
+	scorer.DepegMonitor().OnEvent(func(event models.DepegEvent) {
+		notifier.Notify(models.EventDepeg, fmt.Sprintf("%s depeg %s: %.1f bps from $%.2f peg (%s → %s)",
+			event.Symbol, event.Band, event.PegDeviationBps, event.PegTarget, event.PreviousBand, event.Band), event)
+	})
+	aggregator.OnSourceStatus(func(event models.SourceStatusEvent) {
+		if event.Up {
+			notifier.Notify(models.EventSourceRecovered, fmt.Sprintf("Data source %s recovered", event.Source), event)
+		} else {
+			notifier.Notify(models.EventSourceDown, fmt.Sprintf("Data source %s is down: %s", event.Source, event.Error), event)
+		}
+	})
+	log.Printf("✅ Webhook notifier started (%d webhooks)", len(notifier.List()))
+
+	// Alert rules are evaluated on every scored snapshot
+	alertEngine := services.NewAlertEngine(dataStore, cfg)
+	scorer.OnSnapshot(func(tokens []models.Token) {
+		for _, event := range alertEngine.Observe(tokens) {
+			notifier.Notify(models.EventAlertFired, event.Message, event)
+		}
+		notifier.ObserveGrades(tokens)
+	})
+
+	// Live stream subscribers receive a diff of every scored snapshot
+	liveStream := services.NewLiveStream(cfg)
+	scorer.OnSnapshot(liveStream.Publish)
+	if cfg.SnapshotRefreshInterval > 0 {
+		go marketData.Run(jobsCtx, cfg.SnapshotRefreshInterval)
+	}
+	log.Printf("✅ Alert engine initialized (%d rules, snapshot refresh: %v)", alertEngine.Count(), cfg.SnapshotRefreshInterval)
+	log.Printf("✅ Live stream ready (heartbeat: %v, resume window: %v)", cfg.StreamHeartbeatInterval, cfg.StreamResumeWindow)
+
+	promptRegistry, err := prompts.Load(cfg.PromptsDir)
+	if err != nil {
+		log.Fatalf("❌ Failed to load prompt templates: %v", err)
+	}
+	log.Printf("✅ Prompt templates loaded (%d, default analysis: %s)", len(promptRegistry.List("")), cfg.AnalysisPrompt)
+
+	// Initialize AI service (may fail if the selected LLM provider is misconfigured)
+	usageMeter := services.NewUsageMeter(dataStore, cfg)
+	log.Printf("✅ AI usage meter initialized (daily budget: %d tokens / %d requests, per client: %d / %d)",
+		cfg.AIDailyTokenBudget, cfg.AIDailyRequestBudget, cfg.AIClientDailyTokenBudget, cfg.AIClientDailyRequestBudget)
+
+	analysisTools := services.NewAnalysisTools(marketData, historyService, analytics, aggregator, scorer)
+	aiService, err := services.NewAIService(cfg, promptRegistry, analysisTools, usageMeter)
 	if err != nil {
 		log.Printf("⚠️  AI service initialization failed: %v", err)
-		log.Println("⚠️  /api/analyze endpoint will not work without Gemini API key")
+		log.Println("⚠️  /api/analyze endpoint will not work until the LLM provider is configured")
 	}
 
 	// Initialize handlers
-	tokenHandler := handlers.NewTokenHandler(aggregator, scorer, cacheManager, cfg)
+	tokenHandler := handlers.NewTokenHandler(aggregator, scorer, marketData)
 	log.Println("✅ Token handler initialized")
 
+	analyticsHandler := handlers.NewAnalyticsHandler(analytics, historyService, marketData, cfg)
+	analysisHistoryHandler := handlers.NewAnalysisHistoryHandler(analysisHistory, marketData)
+	performanceHandler := handlers.NewPerformanceHandler(backtester)
+	usageHandler := handlers.NewUsageHandler(usageMeter)
+	alertHandler := handlers.NewAlertHandler(alertEngine)
+	webhookHandler := handlers.NewWebhookHandler(notifier)
+	streamHandler := handlers.NewStreamHandler(liveStream, marketData, cfg)
+	watchlistHandler := handlers.NewWatchlistHandler(watchlistService)
+	portfolioHandler := handlers.NewPortfolioHandler(portfolioService, analytics)
+	apiKeyHandler := handlers.NewAPIKeyHandler(authService)
+
 	var analyzeHandler *handlers.AnalyzeHandler
+	var briefHandler *handlers.BriefHandler
+	var chatHandler *handlers.ChatHandler
 	if aiService != nil {
-		analyzeHandler = handlers.NewAnalyzeHandler(aiService, cacheManager, cfg)
+		analyzeHandler = handlers.NewAnalyzeHandler(aiService, analysisContext, marketData, analysisHistory, cacheManager, cfg)
 		log.Println("✅ Analyze handler initialized")
 
+		briefService := services.NewBriefService(aiService, aggregator, marketData, scorer, dataStore)
+		briefHandler = handlers.NewBriefHandler(briefService)
+		go briefService.Run(jobsCtx, cfg.BriefHourUTC)
+		log.Printf("✅ Daily market brief scheduled (%02d:00 UTC)", cfg.BriefHourUTC)
+
+		chatService := services.NewChatService(aiService, analysisContext, analysisHistory, cacheManager, cfg)
+		chatHandler = handlers.NewChatHandler(chatService)
+		log.Printf("✅ Chat initialized (session expiry: %v, budget: %d tokens)", cfg.ChatSessionDuration, cfg.ChatTokenBudget)
+
 		// Ensure AI client is closed on shutdown
 		defer aiService.Close()
 	}
 
-	// Create Gin router
-	router := gin.Default()
+	// Create Gin router; the access log masks stream tokens in /ws URLs
+	router := gin.New()
+	router.Use(handlers.RedactedLogger(), gin.Recovery())
 
-	// Configure CORS
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"}, // In production, specify exact origins
-		AllowMethods:     []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-	}))
+	// Anonymous callers are rate limited and charged per client IP, so forwarding headers
+	// are only believed from configured proxies
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("❌ Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// Configure CORS; API keys travel in headers, so no credentials are allowed
+	corsConfig := cors.Config{
+		AllowMethods:  []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:  []string{"Origin", "Content-Type", "Accept", "Authorization", handlers.APIKeyHeader, "Last-Event-ID"},
+		ExposeHeaders: []string{"Content-Length", "Retry-After", handlers.RateLimitHeader, handlers.RateLimitRemainingHeader},
+	}
+	if slices.Contains(cfg.CORSAllowedOrigins, "*") {
+		corsConfig.AllowAllOrigins = true
+	} else {
+		corsConfig.AllowOrigins = cfg.CORSAllowedOrigins
+	}
+	router.Use(cors.New(corsConfig))
+	log.Printf("✅ CORS allowed origins: %v", cfg.CORSAllowedOrigins)
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -76,29 +204,114 @@ func main() {
 			"cache": gin.H{
 				"tokens":   cacheManager.GetTokenCacheItemCount(),
 				"analysis": cacheManager.GetAnalysisCacheItemCount(),
+				"chats":    cacheManager.GetChatSessionCount(),
 			},
 		})
 	})
 
+	// Every other route authenticates its API key, is rate limited and charges AI usage to the caller
+	secured := router.Group("/", handlers.Authenticate(authService), handlers.UsageClient())
+	keyRequired := handlers.RequireAPIKey()
+	analystOnly := handlers.RequireRole(models.RoleAnalyst)
+	adminOnly := handlers.RequireRole(models.RoleAdmin)
+
+	// Live token stream (Server-Sent Events); EventSource authenticates with a stream token
+	router.GET("/ws", handlers.AuthenticateStream(authService), handlers.UsageClient(), streamHandler.Stream)
+
 	// API routes group
-	api := router.Group("/api")
+	api := secured.Group("/api")
 	{
+		// The calling key's role and rate limit
+		api.GET("/auth/me", apiKeyHandler.GetCurrentPrincipal)
+		api.POST("/auth/stream-token", keyRequired, apiKeyHandler.CreateStreamToken)
+
 		// Token endpoints
 		api.GET("/tokens", tokenHandler.GetTokens)
 		api.GET("/market/stats", tokenHandler.GetMarketStats)
 
 		api.GET("/tokens/:id", tokenHandler.GetTokenByID)
+		api.GET("/tokens/:id/beta", analyticsHandler.GetTokenBeta)
+		api.GET("/tokens/:id/indicators", analyticsHandler.GetTokenIndicators)
+		api.GET("/tokens/:id/analyses", analysisHistoryHandler.GetTokenAnalyses)
+
+		// Cross-asset analytics
+		api.GET("/correlations", analyticsHandler.GetCorrelations)
+
+		// Sector benchmarks
+		api.GET("/categories", tokenHandler.GetCategories)
+
+		// Stablecoin endpoints
+		api.GET("/stablecoins", tokenHandler.GetStablecoins)
+		api.GET("/stablecoins/depegs", tokenHandler.GetDepegEvents)
+
+		// Alerts
+		api.POST("/alerts", keyRequired, analystOnly, alertHandler.CreateAlert)
+		api.GET("/alerts", alertHandler.GetAlerts)
+		api.GET("/alerts/history", alertHandler.GetAlertHistory)
+		api.GET("/alerts/:id", alertHandler.GetAlert)
+		api.DELETE("/alerts/:id", keyRequired, analystOnly, alertHandler.DeleteAlert)
+
+		// Webhook notifications (admin: webhooks carry signing secrets and send outbound requests)
+		api.POST("/webhooks", adminOnly, webhookHandler.CreateWebhook)
+		api.GET("/webhooks", adminOnly, webhookHandler.GetWebhooks)
+		api.DELETE("/webhooks/:id", adminOnly, webhookHandler.DeleteWebhook)
+		api.POST("/webhooks/:id/enable", adminOnly, webhookHandler.EnableWebhook)
+		api.POST("/webhooks/:id/test", adminOnly, webhookHandler.TestWebhook)
+		api.GET("/webhooks/:id/deliveries", adminOnly, webhookHandler.GetDeliveries)
+
+		// Watchlists and portfolios of the calling client
+		api.POST("/watchlists", keyRequired, watchlistHandler.CreateWatchlist)
+		api.GET("/watchlists", watchlistHandler.GetWatchlists)
+		api.GET("/watchlists/:id", watchlistHandler.GetWatchlist)
+		api.PUT("/watchlists/:id", keyRequired, watchlistHandler.UpdateWatchlist)
+		api.DELETE("/watchlists/:id", keyRequired, watchlistHandler.DeleteWatchlist)
+		api.POST("/watchlists/:id/tokens", keyRequired, watchlistHandler.AddWatchlistToken)
+		api.DELETE("/watchlists/:id/tokens/:token_id", keyRequired, watchlistHandler.RemoveWatchlistToken)
+
+		api.POST("/portfolios", keyRequired, portfolioHandler.CreatePortfolio)
+		api.GET("/portfolios", portfolioHandler.GetPortfolios)
+		api.GET("/portfolios/:id", portfolioHandler.GetPortfolio)
+		api.GET("/portfolios/:id/risk", portfolioHandler.GetPortfolioRisk)
+		api.PUT("/portfolios/:id", keyRequired, portfolioHandler.UpdatePortfolio)
+		api.DELETE("/portfolios/:id", keyRequired, portfolioHandler.DeletePortfolio)
+		api.POST("/portfolios/:id/holdings", keyRequired, portfolioHandler.AddHolding)
+		api.DELETE("/portfolios/:id/holdings/:token_id", keyRequired, portfolioHandler.RemoveHolding)
+
+		// AI recommendation track record
+		api.GET("/ai/performance", performanceHandler.GetAIPerformance)
+
+		// Admin
+		admin := api.Group("/admin", adminOnly)
+		admin.GET("/ai/usage", usageHandler.GetAIUsage)
+		admin.POST("/keys", apiKeyHandler.CreateAPIKey)
+		admin.GET("/keys", apiKeyHandler.GetAPIKeys)
+		admin.DELETE("/keys/:id", apiKeyHandler.DeleteAPIKey)
 
-		// Analysis endpoint (only if AI service is available)
+		// Analysis endpoint (only if AI service is available); generating AI output needs the analyst role
 		if analyzeHandler != nil {
-			api.POST("/analyze", analyzeHandler.AnalyzeToken)
+			api.POST("/analyze", analystOnly, analyzeHandler.AnalyzeToken)
+			api.GET("/analyze/stream", analystOnly, analyzeHandler.AnalyzeTokenStream)
+			api.POST("/analyze/compare", analystOnly, analyzeHandler.CompareTokens)
+			api.GET("/ai/prompts", analyzeHandler.ListPrompts)
+			api.GET("/briefs/latest", briefHandler.GetLatestBrief)
+			api.GET("/briefs", briefHandler.GetBriefs)
+			api.POST("/chat", analystOnly, chatHandler.Chat)
+			api.GET("/chat/:id", analystOnly, chatHandler.GetSession)
 		} else {
-			api.POST("/analyze", func(c *gin.Context) {
+			aiUnavailable := func(c *gin.Context) {
 				c.JSON(503, gin.H{
 					"status":  "error",
-					"message": "AI service not available - GEMINI_API_KEY not configured",
+					"message": "AI service not available - LLM provider not configured",
 				})
-			})
+			}
+			api.POST("/analyze", analystOnly, aiUnavailable)
+			api.GET("/analyze/stream", analystOnly, aiUnavailable)
+			api.POST("/analyze/compare", analystOnly, aiUnavailable)
+			api.GET("/ai/prompts", aiUnavailable)
+			api.GET("/briefs/latest", aiUnavailable)
+			api.GET("/briefs", aiUnavailable)
+			api.POST("/chat", analystOnly, aiUnavailable)
+			api.GET("/chat/:id", analystOnly, aiUnavailable)
 		}
 	}
 
@@ -106,8 +319,40 @@ func main() {
 	log.Println("✅ Server configured successfully")
 	log.Println("📊 Available endpoints:")
 	log.Println("   - GET  /health                 (Health check)")
+	log.Println("   - GET  /ws                     (Live price & score diffs via SSE)")
+	log.Println("   - GET  /api/auth/me            (Role & rate limit of the calling API key)")
+	log.Println("   - POST /api/auth/stream-token  (Short-lived /ws credential for the calling API key)")
 	log.Println("   - GET  /api/tokens             (List tokens with filtering)")
-	log.Println("   - POST /api/analyze            (AI token analysis)")
+	log.Println("   - GET  /api/tokens/:id/beta    (Beta & correlation vs BTC/ETH/market)")
+	log.Println("   - GET  /api/tokens/:id/indicators (Technical indicator series)")
+	log.Println("   - GET  /api/tokens/:id/analyses (Stored AI analysis history)")
+	log.Println("   - GET  /api/correlations       (Correlation matrix)")
+	log.Println("   - GET  /api/categories         (Sector benchmarks)")
+	log.Println("   - GET  /api/stablecoins        (Stablecoin peg metrics)")
+	log.Println("   - GET  /api/stablecoins/depegs (Depeg events)")
+	log.Println("   - POST /api/alerts             (Create alert rule, analyst)")
+	log.Println("   - GET  /api/alerts             (Alert rules of the calling API key)")
+	log.Println("   - GET  /api/alerts/history     (Fired alerts of the calling API key's rules)")
+	log.Println("   - DEL  /api/alerts/:id         (Delete alert rule, analyst)")
+	log.Println("   - POST /api/webhooks           (Register notification webhook, admin)")
+	log.Println("   - GET  /api/webhooks           (List webhooks, admin)")
+	log.Println("   - POST /api/webhooks/:id/test  (Send a test notification, admin)")
+	log.Println("   - GET  /api/webhooks/:id/deliveries (Webhook delivery log, admin)")
+	log.Println("   - *    /api/watchlists         (Watchlists of the calling API key; writes need a key)")
+	log.Println("   - *    /api/portfolios         (Portfolios of the calling API key; writes need a key)")
+	log.Println("   - GET  /api/portfolios/:id     (Valuation, trust score, concentration & sectors)")
+	log.Println("   - GET  /api/portfolios/:id/risk (VaR, expected shortfall, drawdown, BTC beta, correlations)")
+	log.Println("   - POST /api/analyze            (AI token analysis, analyst)")
+	log.Println("   - GET  /api/analyze/stream     (Streaming AI analysis via SSE, analyst)")
+	log.Println("   - POST /api/analyze/compare    (AI comparison of 2-5 tokens, analyst)")
+	log.Println("   - GET  /api/ai/performance     (AI recommendation backtest stats)")
+	log.Println("   - GET  /api/admin/ai/usage     (AI token usage, cost and budgets, admin)")
+	log.Println("   - *    /api/admin/keys         (Create, list & revoke API keys, admin)")
+	log.Println("   - GET  /api/ai/prompts         (Prompt templates)")
+	log.Println("   - GET  /api/briefs/latest      (Latest daily AI market brief)")
+	log.Println("   - GET  /api/briefs?date=       (Market brief by day, or recent briefs)")
+	log.Println("   - POST /api/chat               (Follow-up chat about a token, analyst)")
+	log.Println("   - GET  /api/chat/:id           (Chat session history, analyst)")
 	log.Println("")
 	log.Printf("🌐 Server starting on http://localhost:%s", cfg.Port)
 
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// MaxAlertExpressionLength bounds the size of an alert rule
+const MaxAlertExpressionLength = 500
+
+// AlertRuleRequest is the request body for POST /api/alerts
+type AlertRuleRequest struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression" binding:"required"` // e.g. "BTC price crosses 100k"
+	Cooldown   string `json:"cooldown"`                      // Go duration, e.g. "30m"; empty = configured default
+}
+
+// AlertRule is a stored alert with its evaluation state
+type AlertRule struct {
+	ID          string               `json:"id"`
+	Owner       string               `json:"owner"` // client that created the rule
+	Name        string               `json:"name"`
+	Expression  string               `json:"expression"`
+	Cooldown    string               `json:"cooldown"`
+	Enabled     bool                 `json:"enabled"`
+	CreatedAt   time.Time            `json:"created_at"`
+	FireCount   int                  `json:"fire_count"`
+	LastFiredAt *time.Time           `json:"last_fired_at,omitempty"`
+	Matching    []string             `json:"matching,omitempty"`   // token IDs matching on the last snapshot
+	LastFired   map[string]time.Time `json:"last_fired,omitempty"` // token ID -> last fire, for cooldowns
+}
+
+// AlertEvent is one fired alert
+type AlertEvent struct {
+	ID         string                 `json:"id"`
+	RuleID     string                 `json:"rule_id"`
+	Owner      string                 `json:"owner"` // of the rule
+	RuleName   string                 `json:"rule_name"`
+	Expression string                 `json:"expression"`
+	TokenID    string                 `json:"token_id"`
+	Symbol     string                 `json:"symbol"`
+	Name       string                 `json:"name"`
+	Message    string                 `json:"message"`
+	Values     map[string]interface{} `json:"values"` // the fields the rule references, at fire time
+	FiredAt    time.Time              `json:"fired_at"`
+}
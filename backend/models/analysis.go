@@ -0,0 +1,194 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Canonical vocabularies for the fixed fields of an AI analysis
+var (
+	AnalysisTrends       = []string{"Uptrend", "Downtrend", "Accumulation", "Distribution"}
+	AnalysisStrengths    = []string{"Very Strong", "Strong", "Weak", "Neutral"}
+	AnalysisRiskLevels   = []string{"Low", "Medium", "High", "Extreme"}
+	AnalysisActions      = []string{"BUY NOW", "BUY ZONE", "HOLD", "SELL", "WATCH"}
+	AnalysisTimeHorizons = []string{"Short-term", "Mid-term", "Long-term"}
+)
+
+// TokenAnalysis is the structured output of an AI token analysis
+type TokenAnalysis struct {
+	Summary             string              `json:"summary"`
+	GrowthPotential     GrowthPotential     `json:"growth_potential"`
+	TechnicalAnalysis   TechnicalAnalysis   `json:"technical_analysis"`
+	RiskAnalysis        RiskAnalysis        `json:"risk_analysis"`
+	FundamentalAnalysis FundamentalAnalysis `json:"fundamental_analysis"`
+	Recommendation      Recommendation      `json:"recommendation"`
+	TradingPlan         TradingPlan         `json:"trading_plan"`
+	Insights            []string            `json:"insights"`
+}
+
+type GrowthPotential struct {
+	Score  float64 `json:"score"` // 0-100
+	Reason string  `json:"reason"`
+}
+
+type TechnicalAnalysis struct {
+	Trend     string `json:"trend"`
+	Strength  string `json:"strength"`
+	KeyLevels string `json:"key_levels"`
+}
+
+type RiskAnalysis struct {
+	Level    string   `json:"level"`
+	Concerns []string `json:"concerns"`
+}
+
+type FundamentalAnalysis struct {
+	Sector       string `json:"sector"`
+	Tokenomics   string `json:"tokenomics"`
+	EconomicMoat string `json:"economic_moat"`
+}
+
+type Recommendation struct {
+	Action    string `json:"action"`
+	EntryZone string `json:"entry_zone"`
+	Target    string `json:"target"`
+}
+
+type TradingPlan struct {
+	BuyStrategy string   `json:"buy_strategy"`
+	SellTargets []string `json:"sell_targets"`
+	StopLoss    string   `json:"stop_loss"`
+	TimeHorizon string   `json:"time_horizon"`
+}
+
+// Validate checks required fields and normalizes enum fields to their canonical spelling
+func (a *TokenAnalysis) Validate() error {
+	return a.ValidateLang(DefaultLang)
+}
+
+// ValidateLang is Validate for output written in lang, mapping localized enum labels to canonical values
+func (a *TokenAnalysis) ValidateLang(lang string) error {
+	var problems []string
+
+	if strings.TrimSpace(a.Summary) == "" {
+		problems = append(problems, "summary is empty")
+	}
+	if a.GrowthPotential.Score < 0 || a.GrowthPotential.Score > 100 {
+		problems = append(problems, fmt.Sprintf("growth_potential.score %.1f is outside 0-100", a.GrowthPotential.Score))
+	}
+	if strings.TrimSpace(a.GrowthPotential.Reason) == "" {
+		problems = append(problems, "growth_potential.reason is empty")
+	}
+	if strings.TrimSpace(a.Recommendation.EntryZone) == "" {
+		problems = append(problems, "recommendation.entry_zone is empty")
+	}
+	if strings.TrimSpace(a.TradingPlan.StopLoss) == "" {
+		problems = append(problems, "trading_plan.stop_loss is empty")
+	}
+	if len(a.TradingPlan.SellTargets) == 0 {
+		problems = append(problems, "trading_plan.sell_targets is empty")
+	}
+	if len(a.Insights) == 0 {
+		problems = append(problems, "insights is empty")
+	}
+
+	enums := []struct {
+		field   string
+		value   *string
+		allowed []string
+	}{
+		{"technical_analysis.trend", &a.TechnicalAnalysis.Trend, AnalysisTrends},
+		{"technical_analysis.strength", &a.TechnicalAnalysis.Strength, AnalysisStrengths},
+		{"risk_analysis.level", &a.RiskAnalysis.Level, AnalysisRiskLevels},
+		{"recommendation.action", &a.Recommendation.Action, AnalysisActions},
+		{"trading_plan.time_horizon", &a.TradingPlan.TimeHorizon, AnalysisTimeHorizons},
+	}
+	for _, enum := range enums {
+		canonical, ok := CanonicalEnumLang(*enum.value, enum.allowed, lang)
+		if !ok {
+			allowed := LocalizedEnumValues(enum.allowed, lang)
+			problems = append(problems, fmt.Sprintf("%s %q must be one of: %s", enum.field, *enum.value, strings.Join(allowed, ", ")))
+			continue
+		}
+		*enum.value = canonical
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid analysis: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// CanonicalEnum matches a value case-insensitively against allowed values
+func CanonicalEnum(value string, allowed []string) (string, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	for _, candidate := range allowed {
+		if strings.ToLower(candidate) == normalized {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// AnalysisContext is the server-assembled input for an AI analysis
+type AnalysisContext struct {
+	Token       Token              `json:"token"` // includes the current score breakdown
+	History     *HistorySummary    `json:"history,omitempty"`
+	Indicators  *IndicatorSnapshot `json:"indicators,omitempty"`
+	AssembledAt time.Time          `json:"assembled_at"`
+}
+
+// HistorySummary condenses the daily price history for the prompt
+type HistorySummary struct {
+	Days          int     `json:"days"`
+	High          float64 `json:"high"`
+	Low           float64 `json:"low"`
+	Change30d     float64 `json:"change_30d"`
+	Change90d     float64 `json:"change_90d"`
+	Volatility30d float64 `json:"volatility_30d"` // std dev of daily returns, %
+	BetaBTC       float64 `json:"beta_btc"`
+	BetaMarket    float64 `json:"beta_market"`
+	CorrelBTC     float64 `json:"correlation_btc"`
+}
+
+// IndicatorSnapshot holds the latest daily indicator values
+type IndicatorSnapshot struct {
+	RSI14         float64 `json:"rsi_14"`
+	MACD          float64 `json:"macd"`
+	MACDSignal    float64 `json:"macd_signal"`
+	MACDHistogram float64 `json:"macd_histogram"`
+	BBUpper       float64 `json:"bb_upper"`
+	BBMiddle      float64 `json:"bb_middle"`
+	BBLower       float64 `json:"bb_lower"`
+	ATR14         float64 `json:"atr_14"`
+	StochK        float64 `json:"stoch_k"`
+	StochD        float64 `json:"stoch_d"`
+}
+
+// AnalysisFingerprint captures the input figures an analysis was generated from
+type AnalysisFingerprint struct {
+	Price      float64 `json:"price"`
+	MarketCap  float64 `json:"market_cap"`
+	Volume24h  float64 `json:"volume_24h"`
+	TrustScore float64 `json:"trust_score"`
+	Change24h  float64 `json:"change_24h"`
+	Change7d   float64 `json:"change_7d"`
+	Hash       string  `json:"hash"` // digest of the rounded figures, for quick comparison
+}
+
+// AnalysisRecord is a stored analysis with its provenance
+type AnalysisRecord struct {
+	ID            string              `json:"id"`
+	TokenID       string              `json:"token_id"`
+	Symbol        string              `json:"symbol"`
+	Provider      string              `json:"provider"`
+	Model         string              `json:"model"`
+	PromptVersion string              `json:"prompt_version"`
+	Lang          string              `json:"lang,omitempty"` // output language; empty on records before localization
+	Fingerprint   AnalysisFingerprint `json:"fingerprint"`
+	Analysis      *TokenAnalysis      `json:"analysis"`
+	ToolCalls     []ToolInvocation    `json:"tool_calls,omitempty"` // data the model fetched itself
+	Guardrails    *GuardrailReport    `json:"guardrails,omitempty"`
+	GeneratedAt   time.Time           `json:"generated_at"`
+}
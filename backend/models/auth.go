@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// API key roles, in increasing order of access
+const (
+	RoleViewer  = "viewer"  // market data, analytics, fired alerts, watchlists and portfolios
+	RoleAnalyst = "analyst" // + alert rules, AI analysis, comparison and chat
+	RoleAdmin   = "admin"   // + API keys, webhooks and AI usage reports
+)
+
+// Roles lists the API key roles, lowest access first
+var Roles = []string{RoleViewer, RoleAnalyst, RoleAdmin}
+
+// RoleRank orders roles by access; an unknown role ranks 0
+func RoleRank(role string) int {
+	for i, candidate := range Roles {
+		if candidate == role {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// APIKeyRequest is the request body for POST /api/admin/keys
+type APIKeyRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Role      string `json:"role" binding:"required"`
+	RateLimit int    `json:"rate_limit" binding:"gte=0"` // requests per minute, 0 = server default
+}
+
+// APIKey is a stored API key. Only the SHA-256 hash of the key is kept.
+type APIKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	Prefix    string    `json:"prefix"` // first characters of the key, to recognize it
+	Hash      string    `json:"hash,omitempty"`
+	RateLimit int       `json:"rate_limit"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Redacted returns the key without its hash
+func (k APIKey) Redacted() APIKey {
+	k.Hash = ""
+	return k
+}
+
+// CreatedAPIKey is returned once, when a key is created; the plaintext key is not stored
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// StreamToken is a short-lived credential for GET /ws?stream_token=, standing in for
+// the caller's API key where EventSource cannot send headers
+type StreamToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Principal is the caller a request is authenticated as
+type Principal struct {
+	KeyID     string `json:"key_id,omitempty"` // empty for anonymous requests
+	Name      string `json:"name,omitempty"`
+	Role      string `json:"role"`
+	RateLimit int    `json:"rate_limit"` // requests per minute, 0 = unlimited
+}
+
+// Anonymous reports whether the request carried no API key
+func (p Principal) Anonymous() bool {
+	return p.KeyID == ""
+}
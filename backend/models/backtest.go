@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Backtest outcomes
+const (
+	BacktestPending     = "pending"     // position open, horizon not reached
+	BacktestNotFilled   = "not_filled"  // entry zone never reached
+	BacktestTargetHit   = "target_hit"  // all take-profit levels hit
+	BacktestStopHit     = "stop_hit"    // stop loss hit
+	BacktestExpired     = "expired"     // horizon ended without stop or final target
+	BacktestNoTrade     = "no_trade"    // WATCH: no position, return tracked only
+	BacktestUnparseable = "unparseable" // price levels could not be read
+)
+
+// ParsedRecommendation is the numeric trading plan extracted from an analysis
+type ParsedRecommendation struct {
+	Action      string    `json:"action"`
+	Direction   string    `json:"direction"` // long, short, none
+	EntryLow    float64   `json:"entry_low"`
+	EntryHigh   float64   `json:"entry_high"`
+	Targets     []float64 `json:"targets"`
+	StopLoss    float64   `json:"stop_loss"`
+	HorizonDays int       `json:"horizon_days"`
+}
+
+// BacktestResult is the replayed outcome of one stored analysis
+type BacktestResult struct {
+	AnalysisID     string               `json:"analysis_id"`
+	TokenID        string               `json:"token_id"`
+	Symbol         string               `json:"symbol"`
+	Model          string               `json:"model"`
+	PromptVersion  string               `json:"prompt_version"`
+	GeneratedAt    time.Time            `json:"generated_at"`
+	ReferencePrice float64              `json:"reference_price"` // market price when generated
+	Plan           ParsedRecommendation `json:"plan"`
+	ParseError     string               `json:"parse_error,omitempty"`
+
+	Status      string     `json:"status"`
+	EntryPrice  float64    `json:"entry_price,omitempty"`
+	EntryAt     *time.Time `json:"entry_at,omitempty"`
+	TargetsHit  int        `json:"targets_hit"`
+	FirstTPAt   *time.Time `json:"first_tp_at,omitempty"`
+	StopHitAt   *time.Time `json:"stop_hit_at,omitempty"`
+	MaxAdverse  float64    `json:"max_adverse_excursion"`   // worst unrealized move against the position, %
+	MaxFavor    float64    `json:"max_favorable_excursion"` // best unrealized move for the position, %
+	ReturnPct   float64    `json:"return_pct"`              // over the horizon (or to date while pending)
+	Complete    bool       `json:"complete"`
+	EvaluatedAt time.Time  `json:"evaluated_at"`
+}
+
+// PerformanceStats aggregates backtest results for one model and prompt version
+type PerformanceStats struct {
+	Model         string                `json:"model"`
+	PromptVersion string                `json:"prompt_version"`
+	Analyses      int                   `json:"analyses"`
+	Trades        int                   `json:"trades"`   // filled long/short positions
+	Resolved      int                   `json:"resolved"` // trades with a final outcome
+	TP1HitRate    float64               `json:"tp1_hit_rate"`
+	TargetHitRate float64               `json:"target_hit_rate"` // all targets hit
+	StopHitRate   float64               `json:"stop_hit_rate"`
+	FillRate      float64               `json:"fill_rate"`
+	AvgReturnPct  float64               `json:"avg_return_pct"`
+	AvgMaxAdverse float64               `json:"avg_max_adverse_excursion"`
+	ByAction      map[string]ActionStat `json:"by_action"`
+}
+
+// ActionStat is the outcome summary for one recommendation action
+type ActionStat struct {
+	Count        int     `json:"count"`
+	TP1HitRate   float64 `json:"tp1_hit_rate"`
+	StopHitRate  float64 `json:"stop_hit_rate"`
+	AvgReturnPct float64 `json:"avg_return_pct"`
+}
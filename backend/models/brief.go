@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BriefDateLayout is the day key of a market brief (UTC)
+const BriefDateLayout = "2006-01-02"
+
+// BriefSentiments is the canonical vocabulary of MarketBrief.Sentiment
+var BriefSentiments = []string{"Bullish", "Bearish", "Neutral"}
+
+// BriefInputs is the server-assembled market snapshot a brief is written from
+type BriefInputs struct {
+	Date        string        `json:"date"`
+	Market      *MarketStats  `json:"market,omitempty"` // nil when global stats are unavailable
+	ScoreMovers []BriefMover  `json:"score_movers"`     // largest trust score changes since the previous brief
+	Gainers     []BriefMover  `json:"gainers"`
+	Losers      []BriefMover  `json:"losers"`
+	Sectors     []BriefSector `json:"sectors"`
+	ScoresSince *time.Time    `json:"scores_since,omitempty"` // snapshot ScoreMovers are measured against
+}
+
+// BriefMover is one token highlighted in a brief
+type BriefMover struct {
+	ID          string  `json:"id"`
+	Symbol      string  `json:"symbol"`
+	Name        string  `json:"name"`
+	Price       float64 `json:"price"`
+	Change24h   float64 `json:"change_24h"`
+	Change7d    float64 `json:"change_7d"`
+	TrustScore  float64 `json:"trust_score"`
+	ScoreChange float64 `json:"score_change,omitempty"`
+	Grade       string  `json:"grade"`
+}
+
+// BriefSector summarizes one sector benchmark for a brief
+type BriefSector struct {
+	Category          string  `json:"category"`
+	TokenCount        int     `json:"token_count"`
+	TotalMarketCap    float64 `json:"total_market_cap"`
+	MedianReturn7d    float64 `json:"median_return_7d"`
+	MedianReturn30d   float64 `json:"median_return_30d"`
+	AverageTrustScore float64 `json:"average_trust_score"`
+}
+
+// MarketBrief is the structured output of the daily brief
+type MarketBrief struct {
+	Headline   string   `json:"headline"`
+	Sentiment  string   `json:"sentiment"`
+	Summary    string   `json:"summary"`
+	Highlights []string `json:"highlights"`
+	Sectors    []string `json:"sectors"`   // sector rotation notes
+	Watchlist  []string `json:"watchlist"` // tokens worth a closer look today
+	Risks      []string `json:"risks"`
+}
+
+// Validate checks required fields and normalizes the sentiment
+func (b *MarketBrief) Validate() error {
+	var problems []string
+
+	if strings.TrimSpace(b.Headline) == "" {
+		problems = append(problems, "headline is empty")
+	}
+	if strings.TrimSpace(b.Summary) == "" {
+		problems = append(problems, "summary is empty")
+	}
+	if len(b.Highlights) == 0 {
+		problems = append(problems, "highlights is empty")
+	}
+	if v, ok := CanonicalEnum(b.Sentiment, BriefSentiments); ok {
+		b.Sentiment = v
+	} else {
+		problems = append(problems, fmt.Sprintf("sentiment %q must be one of %s", b.Sentiment, strings.Join(BriefSentiments, ", ")))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid brief: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// BriefRecord is a stored daily brief with its inputs and provenance
+type BriefRecord struct {
+	Date          string       `json:"date"`
+	Provider      string       `json:"provider"`
+	Model         string       `json:"model"`
+	PromptVersion string       `json:"prompt_version"`
+	Brief         *MarketBrief `json:"brief"`
+	Inputs        *BriefInputs `json:"inputs"`
+	GeneratedAt   time.Time    `json:"generated_at"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Chat roles; these match the LLM provider roles
+const (
+	ChatRoleUser  = "user"
+	ChatRoleModel = "model"
+)
+
+// MaxChatMessageLength caps a single user message (characters)
+const MaxChatMessageLength = 2000
+
+// ChatRequest is the request body for /api/chat. A request without a session ID
+// starts a new session about TokenID.
+type ChatRequest struct {
+	SessionID string `json:"session_id"`
+	TokenID   string `json:"token_id"`
+	Message   string `json:"message" binding:"required"`
+}
+
+// ChatMessage is one turn of a chat session
+type ChatMessage struct {
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	Tokens    int       `json:"tokens,omitempty"` // tokens spent on the turn (model replies only)
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ChatSession is a server-side conversation about one token
+type ChatSession struct {
+	ID            string        `json:"id"`
+	Owner         string        `json:"owner"` // client that started the session
+	TokenID       string        `json:"token_id"`
+	Symbol        string        `json:"symbol"`
+	AnalysisID    string        `json:"analysis_id,omitempty"` // analysis the latest turn was grounded in
+	PromptVersion string        `json:"prompt_version"`
+	Messages      []ChatMessage `json:"messages"`
+	TokensUsed    int           `json:"tokens_used"`
+	TokenBudget   int           `json:"token_budget"`
+	CreatedAt     time.Time     `json:"created_at"`
+	ExpiresAt     time.Time     `json:"expires_at"` // extended by each turn
+}
+
+// ChatContext is the grounding and conversation state for one chat turn
+type ChatContext struct {
+	Analysis     *AnalysisContext
+	LastAnalysis *AnalysisRecord // nil when the token was never analyzed
+	History      []ChatMessage
+	Message      string
+}
+
+// ChatResponse is the response for /api/chat
+type ChatResponse struct {
+	Status          string    `json:"status"`
+	SessionID       string    `json:"session_id"`
+	TokenID         string    `json:"token_id"`
+	Symbol          string    `json:"symbol"`
+	Reply           string    `json:"reply"`
+	Model           string    `json:"model"`
+	TokensUsed      int       `json:"tokens_used"`
+	TokensRemaining int       `json:"tokens_remaining"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	Timestamp       time.Time `json:"timestamp"`
+}
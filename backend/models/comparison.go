@@ -0,0 +1,127 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Comparison size limits for /api/analyze/compare
+const (
+	MinCompareTokens = 2
+	MaxCompareTokens = 5
+)
+
+// CompareRequest is the request body for /api/analyze/compare
+type CompareRequest struct {
+	TokenIDs []string `json:"token_ids" binding:"required"`
+	Prompt   string   `json:"prompt"` // template ID; empty = configured default
+}
+
+// ComparisonContext is the server-assembled input for a comparison
+type ComparisonContext struct {
+	Tokens []*AnalysisContext `json:"tokens"`
+}
+
+// TokenComparison is the structured output of a comparative analysis
+type TokenComparison struct {
+	Summary        string                   `json:"summary"`
+	Ranking        []ComparisonEntry        `json:"ranking"`
+	Recommendation ComparisonRecommendation `json:"recommendation"`
+}
+
+type ComparisonEntry struct {
+	Rank    int      `json:"rank"`
+	Symbol  string   `json:"symbol"`
+	Score   float64  `json:"score"` // 0-100, relative
+	Pros    []string `json:"pros"`
+	Cons    []string `json:"cons"`
+	Verdict string   `json:"verdict"`
+}
+
+type ComparisonRecommendation struct {
+	Preferred   string `json:"preferred"`
+	Action      string `json:"action"`
+	Rationale   string `json:"rationale"`
+	TimeHorizon string `json:"time_horizon"`
+}
+
+// Validate checks that every compared symbol is ranked exactly once, renumbers
+// ranks 1..n in order and normalizes enums and symbol spelling
+func (c *TokenComparison) Validate(symbols []string) error {
+	var problems []string
+
+	canonical := make(map[string]string, len(symbols))
+	for _, symbol := range symbols {
+		canonical[strings.ToUpper(symbol)] = symbol
+	}
+
+	seen := make(map[string]bool)
+	for i := range c.Ranking {
+		entry := &c.Ranking[i]
+		symbol, ok := canonical[strings.ToUpper(strings.TrimSpace(entry.Symbol))]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("ranking contains unknown symbol %q", entry.Symbol))
+			continue
+		}
+		if seen[symbol] {
+			problems = append(problems, fmt.Sprintf("ranking lists %s more than once", symbol))
+		}
+		seen[symbol] = true
+		entry.Symbol = symbol
+		if entry.Score < 0 || entry.Score > 100 {
+			problems = append(problems, fmt.Sprintf("score for %s must be 0-100", symbol))
+		}
+	}
+	for _, symbol := range symbols {
+		if !seen[symbol] {
+			problems = append(problems, fmt.Sprintf("ranking is missing %s", symbol))
+		}
+	}
+
+	if preferred, ok := canonical[strings.ToUpper(strings.TrimSpace(c.Recommendation.Preferred))]; ok {
+		c.Recommendation.Preferred = preferred
+	} else {
+		problems = append(problems, fmt.Sprintf("recommendation.preferred %q is not a compared token", c.Recommendation.Preferred))
+	}
+	if v, ok := CanonicalEnum(c.Recommendation.Action, AnalysisActions); ok {
+		c.Recommendation.Action = v
+	} else {
+		problems = append(problems, fmt.Sprintf("recommendation.action %q must be one of %s", c.Recommendation.Action, strings.Join(AnalysisActions, ", ")))
+	}
+	if v, ok := CanonicalEnum(c.Recommendation.TimeHorizon, AnalysisTimeHorizons); ok {
+		c.Recommendation.TimeHorizon = v
+	} else {
+		problems = append(problems, fmt.Sprintf("recommendation.time_horizon %q must be one of %s", c.Recommendation.TimeHorizon, strings.Join(AnalysisTimeHorizons, ", ")))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid comparison: %s", strings.Join(problems, "; "))
+	}
+
+	// Trust the model's order of ranks, not its numbering
+	sort.SliceStable(c.Ranking, func(i, j int) bool { return c.Ranking[i].Rank < c.Ranking[j].Rank })
+	for i := range c.Ranking {
+		c.Ranking[i].Rank = i + 1
+	}
+	return nil
+}
+
+// ComparisonRecord is a generated comparison with its provenance
+type ComparisonRecord struct {
+	TokenIDs      []string         `json:"token_ids"`
+	Symbols       []string         `json:"symbols"`
+	Provider      string           `json:"provider"`
+	Model         string           `json:"model"`
+	PromptVersion string           `json:"prompt_version"`
+	Comparison    *TokenComparison `json:"comparison"`
+	GeneratedAt   time.Time        `json:"generated_at"`
+}
+
+// ComparisonResponse is the response for /api/analyze/compare
+type ComparisonResponse struct {
+	Status string `json:"status"`
+	Cached bool   `json:"cached"`
+	ComparisonRecord
+}
@@ -0,0 +1,63 @@
+package models
+
+// RiskDisclaimer is attached to every AI analysis response
+const RiskDisclaimer = "This analysis is generated automatically from market data and is not financial advice. " +
+	"Crypto assets are highly volatile; price targets and stop levels are estimates that may be wrong. " +
+	"Do your own research and never risk more than you can afford to lose."
+
+// RiskDisclaimers holds RiskDisclaimer translated per language
+var RiskDisclaimers = map[string]string{
+	"vi": "Phân tích này được tạo tự động từ dữ liệu thị trường và không phải là lời khuyên tài chính. " +
+		"Tài sản crypto biến động rất mạnh; mục tiêu giá và mức cắt lỗ chỉ là ước tính và có thể sai. " +
+		"Hãy tự nghiên cứu và đừng bao giờ mạo hiểm số tiền bạn không thể chấp nhận mất.",
+}
+
+// Disclaimer returns the risk disclaimer in lang, falling back to English
+func Disclaimer(lang string) string {
+	if text, ok := RiskDisclaimers[lang]; ok {
+		return text
+	}
+	return RiskDisclaimer
+}
+
+// Guardrail outcomes
+const (
+	GuardrailPassed   = "passed"
+	GuardrailWarnings = "warnings" // plan kept, with annotations
+	GuardrailRejected = "rejected" // plan inconsistent; action downgraded to WATCH
+)
+
+// Guardrail issue severities
+const (
+	GuardrailError   = "error"
+	GuardrailWarning = "warning"
+)
+
+// Confidence levels derived from guardrail issues and data quality flags
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// GuardrailIssue is one problem found in a trading plan
+type GuardrailIssue struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Field    string `json:"field"`
+	Message  string `json:"message"`
+}
+
+// GuardrailReport is the result of checking an analysis' trading plan against the market price
+type GuardrailReport struct {
+	Status          string           `json:"status"`
+	OriginalAction  string           `json:"original_action,omitempty"` // set when the action was downgraded
+	ReferencePrice  float64          `json:"reference_price"`
+	EntryLow        float64          `json:"entry_low,omitempty"`
+	EntryHigh       float64          `json:"entry_high,omitempty"`
+	Targets         []float64        `json:"targets,omitempty"`
+	StopLoss        float64          `json:"stop_loss,omitempty"`
+	Issues          []GuardrailIssue `json:"issues,omitempty"`
+	Confidence      string           `json:"confidence"`
+	ConfidenceFlags []string         `json:"confidence_flags,omitempty"`
+}
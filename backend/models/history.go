@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// Candle is one OHLCV bar
+type Candle struct {
+	Time   time.Time `json:"time"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume float64   `json:"volume"` // quote volume (USD)
+}
+
+// CryptoCompareHistoResponse represents the histoday endpoint from CryptoCompare
+type CryptoCompareHistoResponse struct {
+	Response string `json:"Response"`
+	Message  string `json:"Message"`
+	Data     struct {
+		Data []struct {
+			Time       int64   `json:"time"`
+			Open       float64 `json:"open"`
+			High       float64 `json:"high"`
+			Low        float64 `json:"low"`
+			Close      float64 `json:"close"`
+			VolumeFrom float64 `json:"volumefrom"`
+			VolumeTo   float64 `json:"volumeto"`
+		} `json:"Data"`
+	} `json:"Data"`
+}
+
+// BetaReport holds beta and correlation of a token against each market benchmark
+type BetaReport struct {
+	Symbol      string                    `json:"symbol"`
+	WindowDays  int                       `json:"window_days"`
+	Benchmarks  map[string]BenchmarkStats `json:"benchmarks"` // BTC, ETH, MARKET
+	GeneratedAt time.Time                 `json:"generated_at"`
+}
+
+// BenchmarkStats is the latest-window beta/correlation plus its rolling series
+type BenchmarkStats struct {
+	Beta         float64       `json:"beta"`
+	Correlation  float64       `json:"correlation"`
+	Observations int           `json:"observations"`
+	Rolling      []RollingStat `json:"rolling"`
+}
+
+// RollingStat is beta/correlation over the window ending on Date
+type RollingStat struct {
+	Date        time.Time `json:"date"`
+	Beta        float64   `json:"beta"`
+	Correlation float64   `json:"correlation"`
+}
+
+// CorrelationMatrix is the pairwise return correlation for a set of symbols
+type CorrelationMatrix struct {
+	Symbols      []string    `json:"symbols"`
+	WindowDays   int         `json:"window_days"`
+	Observations int         `json:"observations"`
+	Matrix       [][]float64 `json:"matrix"`
+	Missing      []string    `json:"missing,omitempty"` // symbols without usable history
+	GeneratedAt  time.Time   `json:"generated_at"`
+}
+
+// IndicatorPoint is one timestamped indicator value
+type IndicatorPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// IndicatorReport is the response for /api/tokens/:id/indicators
+type IndicatorReport struct {
+	Symbol     string                                 `json:"symbol"`
+	Days       int                                    `json:"days"`
+	Candles    []Candle                               `json:"candles,omitempty"`
+	Indicators map[string]map[string][]IndicatorPoint `json:"indicators"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "strings"
+
+// DefaultLang is the language of the canonical analysis vocabulary
+const DefaultLang = "en"
+
+// AnalysisEnumTranslations maps each canonical enum value to its localized label, per language.
+// Localized output is mapped back to the canonical values so filtering and backtesting keep working.
+var AnalysisEnumTranslations = map[string]map[string]string{
+	"vi": {
+		"Uptrend":      "Xu hướng tăng",
+		"Downtrend":    "Xu hướng giảm",
+		"Accumulation": "Tích lũy",
+		"Distribution": "Phân phối",
+		"Very Strong":  "Rất mạnh",
+		"Strong":       "Mạnh",
+		"Weak":         "Yếu",
+		"Neutral":      "Trung lập",
+		"Low":          "Thấp",
+		"Medium":       "Trung bình",
+		"High":         "Cao",
+		"Extreme":      "Cực cao",
+		"BUY NOW":      "MUA NGAY",
+		"BUY ZONE":     "VÙNG MUA",
+		"HOLD":         "NẮM GIỮ",
+		"SELL":         "BÁN",
+		"WATCH":        "THEO DÕI",
+		"Short-term":   "Ngắn hạn",
+		"Mid-term":     "Trung hạn",
+		"Long-term":    "Dài hạn",
+	},
+}
+
+// NormalizeLang reduces a language tag like "vi-VN" to its lowercase base ("vi"); empty means DefaultLang
+func NormalizeLang(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if base, _, found := strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-"); found {
+		lang = base
+	}
+	if lang == "" {
+		return DefaultLang
+	}
+	return lang
+}
+
+// LocalizedEnumValues returns the allowed values plus their labels in lang
+func LocalizedEnumValues(allowed []string, lang string) []string {
+	translations := AnalysisEnumTranslations[lang]
+	if len(translations) == 0 {
+		return allowed
+	}
+	values := append([]string(nil), allowed...)
+	for _, canonical := range allowed {
+		if label, ok := translations[canonical]; ok {
+			values = append(values, label)
+		}
+	}
+	return values
+}
+
+// CanonicalEnumLang is CanonicalEnum that also accepts the localized labels of lang
+func CanonicalEnumLang(value string, allowed []string, lang string) (string, bool) {
+	if canonical, ok := CanonicalEnum(value, allowed); ok {
+		return canonical, true
+	}
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	translations := AnalysisEnumTranslations[lang]
+	for _, canonical := range allowed {
+		if label, ok := translations[canonical]; ok && strings.ToLower(label) == normalized {
+			return canonical, true
+		}
+	}
+	return "", false
+}
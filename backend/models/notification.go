@@ -0,0 +1,112 @@
+package models
+
+import "time"
+
+// Notification event types
+const (
+	EventAlertFired      = "alert.fired"
+	EventDepeg           = "stablecoin.depeg"
+	EventGradeChanged    = "token.grade_changed"
+	EventSourceDown      = "source.down"
+	EventSourceRecovered = "source.recovered"
+	EventWebhookTest     = "webhook.test"
+)
+
+// NotificationEventTypes lists every event a webhook can subscribe to
+var NotificationEventTypes = []string{EventAlertFired, EventDepeg, EventGradeChanged, EventSourceDown, EventSourceRecovered, EventWebhookTest}
+
+// Webhook payload formats
+const (
+	WebhookFormatJSON     = "json" // the NotificationEvent itself
+	WebhookFormatSlack    = "slack"
+	WebhookFormatDiscord  = "discord"
+	WebhookFormatTelegram = "telegram" // URL is the bot's sendMessage endpoint
+)
+
+// WebhookFormats lists the supported payload formats
+var WebhookFormats = []string{WebhookFormatJSON, WebhookFormatSlack, WebhookFormatDiscord, WebhookFormatTelegram}
+
+// Webhook delivery states
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed" // attempts exhausted or webhook disabled
+)
+
+// NotificationEvent is one event sent to subscribed webhooks
+type NotificationEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Summary   string      `json:"summary"` // one-line human-readable description
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// WebhookRequest is the request body for POST /api/webhooks
+type WebhookRequest struct {
+	Name   string   `json:"name"`
+	URL    string   `json:"url" binding:"required"`
+	Format string   `json:"format"`  // json (default), slack, discord, telegram
+	Events []string `json:"events"`  // empty = all events
+	ChatID string   `json:"chat_id"` // required for telegram
+}
+
+// Webhook is a registered notification endpoint
+type Webhook struct {
+	ID                  string     `json:"id"`
+	Name                string     `json:"name"`
+	URL                 string     `json:"url"`
+	Format              string     `json:"format"`
+	Events              []string   `json:"events,omitempty"`
+	ChatID              string     `json:"chat_id,omitempty"`
+	Secret              string     `json:"secret,omitempty"` // HMAC key; only returned when the webhook is created
+	Enabled             bool       `json:"enabled"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// Redacted returns the webhook without its secret
+func (w Webhook) Redacted() Webhook {
+	w.Secret = ""
+	w.Events = append([]string(nil), w.Events...)
+	return w
+}
+
+// WebhookDelivery is an outbox entry: one event for one webhook
+type WebhookDelivery struct {
+	ID             string            `json:"id"`
+	WebhookID      string            `json:"webhook_id"`
+	Event          NotificationEvent `json:"event"`
+	Status         string            `json:"status"`
+	Attempts       int               `json:"attempts"`
+	NextAttemptAt  time.Time         `json:"next_attempt_at"`
+	LastStatusCode int               `json:"last_status_code,omitempty"`
+	LastError      string            `json:"last_error,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	DeliveredAt    *time.Time        `json:"delivered_at,omitempty"`
+}
+
+// GradeChangeEvent is raised when a token's score grade changes between snapshots
+type GradeChangeEvent struct {
+	TokenID       string    `json:"token_id"`
+	Symbol        string    `json:"symbol"`
+	Name          string    `json:"name"`
+	Rank          int       `json:"rank"`
+	Grade         string    `json:"grade"`
+	PreviousGrade string    `json:"previous_grade"`
+	TrustScore    float64   `json:"trust_score"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// SourceStatusEvent is raised when a market data source goes down or recovers
+type SourceStatusEvent struct {
+	Source              string    `json:"source"`
+	Up                  bool      `json:"up"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Error               string    `json:"error,omitempty"`
+	Timestamp           time.Time `json:"timestamp"`
+}
@@ -0,0 +1,177 @@
+package models
+
+import "time"
+
+// Portfolio limits, per owner
+const (
+	MaxPortfoliosPerOwner = 20
+	MaxPortfolioHoldings  = 200
+)
+
+// Concentration levels, from the Herfindahl-Hirschman index of holding weights
+const (
+	ConcentrationLow      = "low"      // HHI < 1500
+	ConcentrationModerate = "moderate" // HHI < 2500
+	ConcentrationHigh     = "high"
+)
+
+// HoldingRequest is one position in a portfolio request
+type HoldingRequest struct {
+	TokenID   string  `json:"token_id" binding:"required"` // ID, symbol or name
+	Quantity  float64 `json:"quantity" binding:"gt=0"`
+	CostBasis float64 `json:"cost_basis" binding:"gte=0"` // total USD paid for the position
+}
+
+// PortfolioRequest is the request body for POST and PUT /api/portfolios
+type PortfolioRequest struct {
+	Name     string           `json:"name" binding:"required"`
+	Holdings []HoldingRequest `json:"holdings" binding:"dive"`
+}
+
+// Holding is a stored position
+type Holding struct {
+	TokenID   string  `json:"token_id"`
+	Quantity  float64 `json:"quantity"`
+	CostBasis float64 `json:"cost_basis"`
+}
+
+// Portfolio is a named set of holdings owned by one client
+type Portfolio struct {
+	ID        string    `json:"id"`
+	Owner     string    `json:"owner"`
+	Name      string    `json:"name"`
+	Holdings  []Holding `json:"holdings"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HoldingValuation is a position valued at the aggregated token price
+type HoldingValuation struct {
+	TokenID       string  `json:"token_id"`
+	Symbol        string  `json:"symbol,omitempty"`
+	Name          string  `json:"name,omitempty"`
+	Category      string  `json:"category,omitempty"`
+	Quantity      float64 `json:"quantity"`
+	CostBasis     float64 `json:"cost_basis"`
+	Priced        bool    `json:"priced"` // false when the token is not in the current snapshot
+	Price         float64 `json:"price"`
+	Value         float64 `json:"value"`
+	Weight        float64 `json:"weight"` // % of portfolio value
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
+	PnLPercent    float64 `json:"pnl_percent"` // 0 without a cost basis
+	Change24h     float64 `json:"change_24h"`
+	TrustScore    float64 `json:"trust_score"`
+	Grade         string  `json:"grade,omitempty"`
+}
+
+// PortfolioScore is the value-weighted average of the holdings' score breakdowns
+type PortfolioScore struct {
+	LiquidityScore        float64 `json:"liquidity_score"`
+	VolumeScore           float64 `json:"volume_score"`
+	TVLScore              float64 `json:"tvl_score"`
+	TrendScore            float64 `json:"trend_score"`
+	MarketHealthScore     float64 `json:"market_health_score"`
+	SocialScore           float64 `json:"social_score"`
+	RiskScore             float64 `json:"risk_score"`
+	RelativeStrengthScore float64 `json:"relative_strength_score"`
+	TotalScore            float64 `json:"total_score"`
+	Grade                 string  `json:"grade"`
+	Confidence            float64 `json:"confidence"`
+}
+
+// Concentration describes how much of the value sits in a few holdings
+type Concentration struct {
+	TopHolding        string  `json:"top_holding,omitempty"`
+	TopWeight         float64 `json:"top_weight"`  // %
+	Top3Weight        float64 `json:"top3_weight"` // %
+	HHI               float64 `json:"hhi"`         // 0-10000
+	EffectiveHoldings float64 `json:"effective_holdings"`
+	Level             string  `json:"level"`
+}
+
+// SectorExposure is the share of portfolio value in one category
+type SectorExposure struct {
+	Category   string  `json:"category"`
+	Value      float64 `json:"value"`
+	Weight     float64 `json:"weight"` // %
+	Holdings   int     `json:"holdings"`
+	TrustScore float64 `json:"trust_score"` // value-weighted within the sector
+}
+
+// PortfolioValuation values a portfolio against the current snapshot
+type PortfolioValuation struct {
+	TotalValue       float64            `json:"total_value"`
+	TotalCostBasis   float64            `json:"total_cost_basis"`
+	UnrealizedPnL    float64            `json:"unrealized_pnl"`
+	PnLPercent       float64            `json:"pnl_percent"`
+	Change24hValue   float64            `json:"change_24h_value"` // USD change over 24h at current quantities
+	Change24hPercent float64            `json:"change_24h_percent"`
+	TrustScore       float64            `json:"trust_score"` // value-weighted total score
+	Score            PortfolioScore     `json:"score"`
+	Concentration    Concentration      `json:"concentration"`
+	Sectors          []SectorExposure   `json:"sectors"`
+	Holdings         []HoldingValuation `json:"holdings"`
+	Unpriced         []string           `json:"unpriced,omitempty"`
+	ValuedAt         time.Time          `json:"valued_at"`
+}
+
+// PortfolioDetail is a portfolio with its valuation
+type PortfolioDetail struct {
+	Portfolio
+	Valuation PortfolioValuation `json:"valuation"`
+}
+
+// VaREstimate is one-day Value-at-Risk and expected shortfall at one confidence level.
+// Losses are positive percentages of the covered value; USD figures scale them by that value.
+type VaREstimate struct {
+	Confidence             float64 `json:"confidence"` // e.g. 0.95
+	HistoricalPct          float64 `json:"historical_pct"`
+	HistoricalUSD          float64 `json:"historical_usd"`
+	ParametricPct          float64 `json:"parametric_pct"` // Gaussian, from mean and volatility
+	ParametricUSD          float64 `json:"parametric_usd"`
+	ExpectedShortfallPct   float64 `json:"expected_shortfall_pct"` // historical: mean loss beyond VaR
+	ExpectedShortfallUSD   float64 `json:"expected_shortfall_usd"`
+	ParametricShortfallPct float64 `json:"parametric_shortfall_pct"`
+}
+
+// DrawdownStats describes the worst peak-to-trough decline over the window
+type DrawdownStats struct {
+	MaxDrawdownPct     float64    `json:"max_drawdown_pct"`
+	PeakDate           time.Time  `json:"peak_date"`
+	TroughDate         time.Time  `json:"trough_date"`
+	RecoveryDate       *time.Time `json:"recovery_date,omitempty"` // first close back at the peak, if any
+	CurrentDrawdownPct float64    `json:"current_drawdown_pct"`
+}
+
+// HoldingRisk is one holding's share of portfolio risk
+type HoldingRisk struct {
+	TokenID                string  `json:"token_id"`
+	Symbol                 string  `json:"symbol"`
+	Weight                 float64 `json:"weight"`                // % of covered value
+	VolatilityAnnualized   float64 `json:"volatility_annualized"` // %
+	BetaToBTC              float64 `json:"beta_to_btc"`
+	CorrelationToPortfolio float64 `json:"correlation_to_portfolio"`
+	RiskContribution       float64 `json:"risk_contribution"` // % of portfolio variance
+}
+
+// PortfolioRisk is the response for GET /api/portfolios/:id/risk. Returns are daily closes
+// over the window, with today's holding weights held constant (daily rebalanced).
+type PortfolioRisk struct {
+	PortfolioID          string             `json:"portfolio_id"`
+	WindowDays           int                `json:"window_days"`
+	Observations         int                `json:"observations"` // overlapping daily returns
+	HorizonDays          int                `json:"horizon_days"`
+	Value                float64            `json:"value"`    // USD value of holdings with usable history
+	Coverage             float64            `json:"coverage"` // % of portfolio value with usable history
+	VaR                  []VaREstimate      `json:"var"`
+	MeanDailyReturn      float64            `json:"mean_daily_return"` // %
+	VolatilityDaily      float64            `json:"volatility_daily"`  // %
+	VolatilityAnnualized float64            `json:"volatility_annualized"`
+	Drawdown             DrawdownStats      `json:"drawdown"`
+	BetaToBTC            float64            `json:"beta_to_btc"`
+	CorrelationToBTC     float64            `json:"correlation_to_btc"`
+	Holdings             []HoldingRisk      `json:"holdings"`
+	Correlations         *CorrelationMatrix `json:"correlations"`
+	Missing              []string           `json:"missing,omitempty"` // holdings without usable history
+	GeneratedAt          time.Time          `json:"generated_at"`
+}
@@ -22,33 +22,27 @@ type TokensResponse struct {
 	FetchTimeMs int64     `json:"fetch_time_ms"`
 }
 
-// AnalysisRequest is the request body for /api/analyze endpoint
+// AnalysisRequest is the request body for /api/analyze endpoint.
+// Only the token is identified; all market figures are assembled server-side.
 type AnalysisRequest struct {
-	Symbol            string  `json:"symbol" binding:"required"`
-	Name              string  `json:"name" binding:"required"`
-	Price             float64 `json:"price"`
-	MarketCap         float64 `json:"market_cap"`
-	Volume24h         float64 `json:"volume_24h"`
-	TVL               float64 `json:"tvl"`
-	TrustScore        float64 `json:"trust_score"`
-	Change24h         float64 `json:"change_24h"`
-	Change7d          float64 `json:"change_7d"`
-	Liquidity         float64 `json:"liquidity"`
-	Rank              int     `json:"rank"`
-	HolderCount       int     `json:"holder_count"`
-	CirculatingSupply float64 `json:"circulating_supply"`
-	MaxSupply         float64 `json:"max_supply"`
-	TotalSupply       float64 `json:"total_supply"`
-	Change30d         float64 `json:"change_30d"`
-	Change90d         float64 `json:"change_90d"`
+	TokenID string `json:"token_id" form:"token_id" binding:"required"`
+	Prompt  string `json:"prompt" form:"prompt"` // template ID; empty = configured default
+	Lang    string `json:"lang" form:"lang"`     // output language, e.g. "vi"; empty = en
 }
 
 // AnalysisResponse is the response for /api/analyze endpoint
 type AnalysisResponse struct {
-	Status      string    `json:"status"`
-	Cached      bool      `json:"cached"`
-	Analysis    string    `json:"analysis"`
-	GeneratedAt time.Time `json:"generated_at"`
+	Status        string           `json:"status"`
+	Cached        bool             `json:"cached"`
+	AnalysisID    string           `json:"analysis_id"`
+	Model         string           `json:"model"`
+	PromptVersion string           `json:"prompt_version"`
+	Lang          string           `json:"lang"`
+	Analysis      *TokenAnalysis   `json:"analysis"`
+	ToolCalls     []ToolInvocation `json:"tool_calls,omitempty"`
+	Guardrails    *GuardrailReport `json:"guardrails,omitempty"`
+	Disclaimer    string           `json:"disclaimer"`
+	GeneratedAt   time.Time        `json:"generated_at"`
 }
 
 // ErrorResponse is the standard error response
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Enhanced scoring breakdown with 6 categories
 type DetailedScoreBreakdown struct {
 	// Main Categories (total 100%)
@@ -11,13 +13,70 @@ type DetailedScoreBreakdown struct {
 	SocialScore       float64 `json:"social_score"`        // NEW: 10%
 	RiskScore         float64 `json:"risk_score"`          // 5%
 
+	// Sector-relative (blended into total when a benchmark exists)
+	RelativeStrengthScore float64 `json:"relative_strength_score"`
+
 	// Overall
+	Model      string  `json:"model"` // standard, stablecoin
 	TotalScore float64 `json:"total_score"`
 	Grade      string  `json:"grade"`      // S, A, B, C, D, F
 	Confidence float64 `json:"confidence"` // 0-100
 
 	// Detailed metrics
-	Details ScoreDetails `json:"details"`
+	Details    ScoreDetails       `json:"details"`
+	Coverage   DataCoverage       `json:"coverage"`
+	Stablecoin *StablecoinMetrics `json:"stablecoin,omitempty"`
+}
+
+// StablecoinMetrics holds the peg-based sub-scores used instead of trend/momentum
+type StablecoinMetrics struct {
+	PegTarget           float64 `json:"peg_target"`
+	PegDeviationBps     float64 `json:"peg_deviation_bps"`    // signed, current price vs peg
+	DeviationVolatility float64 `json:"deviation_volatility"` // std dev of deviation series, bps
+	MarketCapShare      float64 `json:"market_cap_share"`     // % of tracked stablecoin supply
+	DepegBand           string  `json:"depeg_band"`           // normal, warning, alert, critical; unknown without a price
+
+	PegStabilityScore  float64 `json:"peg_stability_score"`
+	PegVolatilityScore float64 `json:"peg_volatility_score"`
+	ConcentrationScore float64 `json:"concentration_score"`
+	LiquidityScore     float64 `json:"liquidity_score"`
+}
+
+// DepegEvent is raised when a stablecoin moves between depeg bands
+type DepegEvent struct {
+	TokenID         string    `json:"token_id"`
+	Symbol          string    `json:"symbol"`
+	Name            string    `json:"name"`
+	Price           float64   `json:"price"`
+	PegTarget       float64   `json:"peg_target"`
+	PegDeviationBps float64   `json:"peg_deviation_bps"`
+	Band            string    `json:"band"`
+	PreviousBand    string    `json:"previous_band"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// DataCoverage explains how the confidence value was derived
+type DataCoverage struct {
+	// Per-field presence (field name -> has usable data)
+	Fields        map[string]bool `json:"fields"`
+	CoverageRatio float64         `json:"coverage_ratio"` // weighted 0-1
+
+	// Source agreement
+	PriceSources          int     `json:"price_sources"`
+	AgreeingPriceSources  int     `json:"agreeing_price_sources"`
+	VolumeSources         int     `json:"volume_sources"`
+	AgreeingVolumeSources int     `json:"agreeing_volume_sources"`
+	PriceDispersion       float64 `json:"price_dispersion"` // max deviation from median, %
+
+	// Freshness & History
+	DataAgeMinutes float64 `json:"data_age_minutes"`
+	HistoryDays    int     `json:"history_days"`
+
+	// Component scores (0-1)
+	CompletenessScore float64 `json:"completeness_score"`
+	AgreementScore    float64 `json:"agreement_score"`
+	FreshnessScore    float64 `json:"freshness_score"`
+	HistoryScore      float64 `json:"history_score"`
 }
 
 // ScoreDetails contains granular metrics for each category
@@ -50,6 +109,14 @@ type ScoreDetails struct {
 	UniqueHolders       int     `json:"unique_holders"`
 	MarketCapRank       int     `json:"market_cap_rank"`
 
+	// Sector-Relative Details
+	Sector               string  `json:"sector,omitempty"`
+	SectorVolumeRatio    float64 `json:"sector_volume_ratio"`     // token vol/mcap ÷ sector median
+	SectorTVLRatio       float64 `json:"sector_tvl_ratio"`        // token TVL/mcap ÷ sector median
+	SectorLiquidityRatio float64 `json:"sector_liquidity_ratio"`  // token liq/mcap ÷ sector median
+	SectorReturn7dDelta  float64 `json:"sector_return_7d_delta"`  // pp above sector median
+	SectorReturn30dDelta float64 `json:"sector_return_30d_delta"` // pp above sector median
+
 	// Risk Indicators
 	RugPullRisk        string `json:"rug_pull_risk"` // Low, Medium, High
 	CentralizationRisk string `json:"centralization_risk"`
@@ -72,3 +139,33 @@ type TVLHistory struct {
 	Last7Days  []float64 `json:"last_7_days"`
 	Last30Days []float64 `json:"last_30_days"`
 }
+
+// CategoryBenchmark holds per-sector medians computed on each scoring pass
+type CategoryBenchmark struct {
+	Category             string    `json:"category"`
+	TokenCount           int       `json:"token_count"`
+	TotalMarketCap       float64   `json:"total_market_cap"`
+	MedianVolumeToMcap   float64   `json:"median_volume_to_mcap"`
+	MedianTVLToMcap      float64   `json:"median_tvl_to_mcap"`
+	TVLSampleSize        int       `json:"tvl_sample_size"`
+	MedianLiquidityRatio float64   `json:"median_liquidity_ratio"`
+	LiquiditySampleSize  int       `json:"liquidity_sample_size"`
+	MedianReturn7d       float64   `json:"median_return_7d"`
+	Return7dSampleSize   int       `json:"return_7d_sample_size"`
+	MedianReturn30d      float64   `json:"median_return_30d"`
+	Return30dSampleSize  int       `json:"return_30d_sample_size"`
+	AverageTrustScore    float64   `json:"average_trust_score"`
+	UpdatedAt            time.Time `json:"updated_at"`
+
+	TopConstituents []CategoryConstituent `json:"top_constituents"`
+}
+
+// CategoryConstituent is a summary of one token inside a sector benchmark
+type CategoryConstituent struct {
+	ID                    string  `json:"id"`
+	Symbol                string  `json:"symbol"`
+	Name                  string  `json:"name"`
+	MarketCap             float64 `json:"market_cap"`
+	TrustScore            float64 `json:"trust_score"`
+	RelativeStrengthScore float64 `json:"relative_strength_score"`
+}
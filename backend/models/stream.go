@@ -0,0 +1,131 @@
+package models
+
+import "time"
+
+// Live stream event names
+const (
+	StreamEventSnapshot  = "snapshot"  // full state of the subscription
+	StreamEventUpdate    = "update"    // diff against the previous event
+	StreamEventHeartbeat = "heartbeat" // keeps idle connections and proxies alive
+)
+
+// Reasons a full snapshot is sent instead of a diff
+const (
+	StreamReasonInitial       = "initial"
+	StreamReasonResumeExpired = "resume_expired" // resume token unknown or older than the backlog
+	StreamReasonLagged        = "lagged"         // client fell further behind than the backlog
+)
+
+// MaxStreamTokenIDs bounds the explicit token list of a subscription
+const MaxStreamTokenIDs = 500
+
+// StreamRequest holds the query parameters of GET /ws
+type StreamRequest struct {
+	IDs    string `form:"ids"`    // comma-separated token IDs or symbols
+	Filter string `form:"filter"` // alert rule expression, e.g. "any in DeFi with score >= 70"
+	Resume string `form:"resume"` // resume token from a previous event; the Last-Event-ID header also works
+}
+
+// TokenTick is the streamed subset of a token
+type TokenTick struct {
+	ID         string  `json:"id"`
+	Symbol     string  `json:"symbol"`
+	Name       string  `json:"name"`
+	Rank       int     `json:"rank"`
+	Price      float64 `json:"price"`
+	Change1h   float64 `json:"change_1h"`
+	Change24h  float64 `json:"change_24h"`
+	Change7d   float64 `json:"change_7d"`
+	Volume24h  float64 `json:"volume_24h"`
+	MarketCap  float64 `json:"market_cap"`
+	TrustScore float64 `json:"trust_score"`
+	Grade      string  `json:"grade"`
+}
+
+// NewTokenTick extracts the streamed fields of a token
+func NewTokenTick(t *Token) TokenTick {
+	return TokenTick{
+		ID:         t.ID,
+		Symbol:     t.Symbol,
+		Name:       t.Name,
+		Rank:       t.Rank,
+		Price:      t.Price,
+		Change1h:   t.Change1h,
+		Change24h:  t.Change24h,
+		Change7d:   t.Change7d,
+		Volume24h:  t.Volume24h,
+		MarketCap:  t.MarketCap,
+		TrustScore: t.TrustScore,
+		Grade:      t.ScoreBreakdown.Grade,
+	}
+}
+
+// TokenDiff carries only the fields of a tick that changed
+type TokenDiff struct {
+	ID         string   `json:"id"`
+	Rank       *int     `json:"rank,omitempty"`
+	Price      *float64 `json:"price,omitempty"`
+	Change1h   *float64 `json:"change_1h,omitempty"`
+	Change24h  *float64 `json:"change_24h,omitempty"`
+	Change7d   *float64 `json:"change_7d,omitempty"`
+	Volume24h  *float64 `json:"volume_24h,omitempty"`
+	MarketCap  *float64 `json:"market_cap,omitempty"`
+	TrustScore *float64 `json:"trust_score,omitempty"`
+	Grade      *string  `json:"grade,omitempty"`
+}
+
+// DiffTokenTicks returns the changes from prev to cur, or false when nothing changed
+func DiffTokenTicks(prev, cur TokenTick) (TokenDiff, bool) {
+	diff := TokenDiff{ID: cur.ID}
+	changed := false
+	setFloat := func(dst **float64, before, after float64) {
+		if before != after {
+			v := after
+			*dst = &v
+			changed = true
+		}
+	}
+	if prev.Rank != cur.Rank {
+		rank := cur.Rank
+		diff.Rank = &rank
+		changed = true
+	}
+	setFloat(&diff.Price, prev.Price, cur.Price)
+	setFloat(&diff.Change1h, prev.Change1h, cur.Change1h)
+	setFloat(&diff.Change24h, prev.Change24h, cur.Change24h)
+	setFloat(&diff.Change7d, prev.Change7d, cur.Change7d)
+	setFloat(&diff.Volume24h, prev.Volume24h, cur.Volume24h)
+	setFloat(&diff.MarketCap, prev.MarketCap, cur.MarketCap)
+	setFloat(&diff.TrustScore, prev.TrustScore, cur.TrustScore)
+	if prev.Grade != cur.Grade {
+		grade := cur.Grade
+		diff.Grade = &grade
+		changed = true
+	}
+	return diff, changed
+}
+
+// StreamSnapshot is the full state of a subscription
+type StreamSnapshot struct {
+	Resume    string      `json:"resume"` // pass back as ?resume= (or Last-Event-ID) after a reconnect
+	Seq       uint64      `json:"seq"`
+	Reason    string      `json:"reason"`
+	Tokens    []TokenTick `json:"tokens"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// StreamUpdate is the diff produced by one market snapshot
+type StreamUpdate struct {
+	Resume    string      `json:"resume"`
+	Seq       uint64      `json:"seq"`
+	Changed   []TokenDiff `json:"changed,omitempty"`
+	Added     []TokenTick `json:"added,omitempty"`   // tokens that entered the subscription
+	Removed   []string    `json:"removed,omitempty"` // token IDs that left it
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// StreamHeartbeat is sent when the stream is otherwise idle
+type StreamHeartbeat struct {
+	Seq       uint64    `json:"seq"` // latest market snapshot sequence
+	Timestamp time.Time `json:"timestamp"`
+}
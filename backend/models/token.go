@@ -1,14 +1,17 @@
 package models
 
+import "time"
+
 // Token represents aggregated cryptocurrency data from multiple sources
 type Token struct {
 	// Basic Info
-	ID       string `json:"id"`
-	Rank     int    `json:"rank"`
-	Symbol   string `json:"symbol"`
-	Name     string `json:"name"`
-	Image    string `json:"image"`
-	Category string `json:"category"`
+	ID       string   `json:"id"`
+	Rank     int      `json:"rank"`
+	Symbol   string   `json:"symbol"`
+	Name     string   `json:"name"`
+	Image    string   `json:"image"`
+	Category string   `json:"category"`
+	Tags     []string `json:"tags,omitempty"`
 
 	// Current Metrics
 	Price     float64 `json:"price"`
@@ -92,11 +95,21 @@ type Token struct {
 	VolumeHistory VolumeHistory `json:"volume_history,omitempty"`
 	TVLHistory    TVLHistory    `json:"tvl_history,omitempty"`
 
+	// Source Provenance
+	Sources     map[string]SourceQuote `json:"sources,omitempty"`
+	LastUpdated time.Time              `json:"last_updated"`
+
 	// Scoring
 	TrustScore     float64                `json:"trust_score"`
 	ScoreBreakdown DetailedScoreBreakdown `json:"score_breakdown"`
 }
 
+// SourceQuote holds the raw price and volume a single source reported for a token
+type SourceQuote struct {
+	Price     float64 `json:"price"`
+	Volume24h float64 `json:"volume_24h,omitempty"`
+}
+
 // External API response structures
 
 // DefiLlamaProtocol represents data from DeFiLlama API
@@ -141,9 +154,16 @@ type DexScreenerResponse struct {
 }
 
 type DexPair struct {
-	BaseToken struct {
+	ChainID     string `json:"chainId"`
+	DexID       string `json:"dexId"`
+	PairAddress string `json:"pairAddress"`
+	URL         string `json:"url"`
+	BaseToken   struct {
 		Symbol string `json:"symbol"`
 	} `json:"baseToken"`
+	QuoteToken struct {
+		Symbol string `json:"symbol"`
+	} `json:"quoteToken"`
 	Liquidity struct {
 		USD float64 `json:"usd"`
 	} `json:"liquidity"`
@@ -182,6 +202,7 @@ type CoinMarketCapCoin struct {
 			MarketCapDominance    float64   `json:"market_cap_dominance"`
 			FullyDilutedMarketCap float64   `json:"fully_diluted_market_cap"`
 			Sparkline             []float64 `json:"sparkline"` // NEW: CMC Sparkline data
+			LastUpdated           string    `json:"last_updated"`
 		} `json:"USD"`
 	} `json:"quote"`
 }
@@ -203,18 +224,19 @@ type MessariAsset struct {
 
 // FilterParams represents query parameters for filtering tokens
 type FilterParams struct {
-	MinMcap   float64
-	MaxMcap   float64
-	Category  string
-	Search    string
-	MinPrice  float64
-	MaxPrice  float64
-	MinScore  float64
-	MaxScore  float64
-	MinChange float64
-	MaxChange float64
-	Limit     int
-	Offset    int
+	MinMcap       float64
+	MaxMcap       float64
+	Category      string
+	Search        string
+	MinPrice      float64
+	MaxPrice      float64
+	MinScore      float64
+	MaxScore      float64
+	MinChange     float64
+	MaxChange     float64
+	MinConfidence float64
+	Limit         int
+	Offset        int
 }
 
 // MarketStats represents global market statistics
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ToolInvocation records one function call the model made while generating
+type ToolInvocation struct {
+	Name       string                 `json:"name"`
+	Args       map[string]interface{} `json:"args"`
+	Result     map[string]interface{} `json:"result,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
+	CalledAt   time.Time              `json:"called_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// UsageTotals accumulates AI usage for one scope (global, a client or a model)
+type UsageTotals struct {
+	Requests     int     `json:"requests"` // generations, including repair retries
+	PromptTokens int     `json:"prompt_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"` // estimate from the configured model prices
+}
+
+// Tokens returns prompt plus output tokens
+func (t *UsageTotals) Tokens() int {
+	return t.PromptTokens + t.OutputTokens
+}
+
+// UsageDay is the AI usage of one UTC day
+type UsageDay struct {
+	Date    string                  `json:"date"`
+	Total   UsageTotals             `json:"total"`
+	Clients map[string]*UsageTotals `json:"clients"`
+	Models  map[string]*UsageTotals `json:"models"`
+}
+
+// UsageLimits are the daily AI budgets; zero means unlimited
+type UsageLimits struct {
+	DailyTokens         int `json:"daily_tokens"`
+	DailyRequests       int `json:"daily_requests"`
+	ClientDailyTokens   int `json:"client_daily_tokens"`
+	ClientDailyRequests int `json:"client_daily_requests"`
+}
+
+// ModelPrice is the estimated USD price per million tokens of a model
+type ModelPrice struct {
+	InputPerMillion  float64 `json:"input_per_million"`
+	OutputPerMillion float64 `json:"output_per_million"`
+}
+
+// UsageReport is the response data of /api/admin/ai/usage
+type UsageReport struct {
+	Limits         UsageLimits           `json:"limits"`
+	Prices         map[string]ModelPrice `json:"prices"`
+	UnpricedModels []string              `json:"unpriced_models,omitempty"` // used models without a price (cost counted as 0)
+	ResetsAt       time.Time             `json:"resets_at"`
+	Days           []UsageDay            `json:"days"` // newest first
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Watchlist limits, per owner
+const (
+	MaxWatchlistsPerOwner = 20
+	MaxWatchlistTokens    = 200
+)
+
+// WatchlistRequest is the request body for POST and PUT /api/watchlists
+type WatchlistRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	TokenIDs []string `json:"token_ids"` // IDs, symbols or names; stored as token IDs
+}
+
+// WatchlistTokenRequest is the request body for POST /api/watchlists/:id/tokens
+type WatchlistTokenRequest struct {
+	TokenID string `json:"token_id" binding:"required"`
+}
+
+// Watchlist is a named list of tokens owned by one client
+type Watchlist struct {
+	ID        string    `json:"id"`
+	Owner     string    `json:"owner"`
+	Name      string    `json:"name"`
+	TokenIDs  []string  `json:"token_ids"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WatchlistDetail is a watchlist with its tokens from the current snapshot
+type WatchlistDetail struct {
+	Watchlist
+	Tokens  []Token  `json:"tokens"`
+	Missing []string `json:"missing,omitempty"` // token IDs no longer in the snapshot
+}
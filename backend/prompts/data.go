@@ -0,0 +1,92 @@
+package prompts
+
+import "backend/models"
+
+// Template kinds
+const (
+	KindAnalysis = "analysis"
+	KindCompare  = "compare"
+	KindBrief    = "brief" // rendered directly from *models.BriefInputs
+	KindChat     = "chat"  // system prompt of a chat session
+)
+
+// AnalysisData is the named-field input of "analysis" templates. Derived ratios are
+// computed here so templates never divide by a missing supply or market cap.
+type AnalysisData struct {
+	Token      models.Token
+	Score      models.DetailedScoreBreakdown
+	History    *models.HistorySummary
+	Indicators *models.IndicatorSnapshot
+
+	FDV             float64 // reported FDV, else price x max (or total) supply; 0 if unknown
+	HasMaxSupply    bool
+	SupplyBasis     string  // "Max Supply", "Total Supply" or "" when neither is known
+	CirculatingPct  float64 // circulating share of SupplyBasis
+	VolumeToMcap    float64
+	LiquidityToMcap float64
+}
+
+// NewAnalysisData prepares template fields from an assembled analysis context
+func NewAnalysisData(actx *models.AnalysisContext) AnalysisData {
+	token := actx.Token
+	data := AnalysisData{
+		Token:        token,
+		Score:        token.ScoreBreakdown,
+		History:      actx.History,
+		Indicators:   actx.Indicators,
+		FDV:          token.FullyDilutedValue,
+		HasMaxSupply: token.MaxSupply > 0,
+	}
+
+	supply := token.MaxSupply
+	data.SupplyBasis = "Max Supply"
+	if supply <= 0 {
+		supply = token.TotalSupply
+		data.SupplyBasis = "Total Supply"
+	}
+	if supply > 0 {
+		if data.FDV <= 0 {
+			data.FDV = token.Price * supply
+		}
+		if token.CirculatingSupply > 0 {
+			data.CirculatingPct = token.CirculatingSupply / supply * 100
+		}
+	}
+	if supply <= 0 || token.CirculatingSupply <= 0 {
+		data.SupplyBasis = ""
+	}
+
+	if token.MarketCap > 0 {
+		data.VolumeToMcap = token.Volume24h / token.MarketCap
+		data.LiquidityToMcap = token.Liquidity / token.MarketCap
+	}
+	return data
+}
+
+// CompareData is the input of "compare" templates: one AnalysisData per token, in request order
+type CompareData struct {
+	Tokens []AnalysisData
+}
+
+// NewCompareData prepares template fields for a comparison
+func NewCompareData(cctx *models.ComparisonContext) CompareData {
+	data := CompareData{Tokens: make([]AnalysisData, 0, len(cctx.Tokens))}
+	for _, actx := range cctx.Tokens {
+		data.Tokens = append(data.Tokens, NewAnalysisData(actx))
+	}
+	return data
+}
+
+// ChatData is the input of "chat" templates: the token's current data and its latest analysis
+type ChatData struct {
+	AnalysisData
+	LastAnalysis *models.AnalysisRecord // nil when the token was never analyzed
+}
+
+// NewChatData prepares template fields for a chat turn
+func NewChatData(cctx *models.ChatContext) ChatData {
+	return ChatData{
+		AnalysisData: NewAnalysisData(cctx.Analysis),
+		LastAnalysis: cctx.LastAnalysis,
+	}
+}
@@ -0,0 +1,219 @@
+package prompts
+
+import (
+	"backend/models"
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Built-in templates; files in the configured prompts directory override or extend them
+//
+//go:embed *.tmpl
+var builtin embed.FS
+
+const templateExt = ".tmpl"
+
+// Template is a versioned prompt with its generation defaults
+type Template struct {
+	ID          string  `json:"id"` // also the prompt version stored with results
+	Kind        string  `json:"kind"`
+	Description string  `json:"description,omitempty"`
+	Temperature float32 `json:"temperature"`
+	TopP        float32 `json:"top_p"`
+	MaxTokens   int32   `json:"max_tokens"`
+	Tools       bool    `json:"tools"`  // model fetches data through function calls
+	Lang        string  `json:"lang"`   // output language (header "lang", default en); locale variants share the ID
+	Source      string  `json:"source"` // builtin or file path
+
+	tmpl *template.Template
+}
+
+// Render executes the template with named fields from data
+func (t *Template) Render(data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("prompt %s: %w", t.ID, err)
+	}
+	return buf.String(), nil
+}
+
+// Registry holds every loaded prompt template by ID and language
+type Registry struct {
+	templates map[string]*Template
+}
+
+// templateKey indexes locale variants next to the default-language template
+func templateKey(id, lang string) string {
+	if lang == models.DefaultLang {
+		return id
+	}
+	return id + "@" + lang
+}
+
+// Load reads the built-in templates, then any *.tmpl files in dir (which may not exist)
+func Load(dir string) (*Registry, error) {
+	r := &Registry{templates: make(map[string]*Template)}
+
+	builtinFiles, err := fs.Glob(builtin, "*"+templateExt)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range builtinFiles {
+		data, err := builtin.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.add(data, "builtin"); err != nil {
+			return nil, fmt.Errorf("builtin %s: %w", name, err)
+		}
+	}
+
+	if dir == "" {
+		return r, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"+templateExt))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.add(data, path); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		log.Printf("✓ Loaded prompt template from %s", path)
+	}
+	return r, nil
+}
+
+// Get returns the default-language template by ID
+func (r *Registry) Get(id string) (*Template, bool) {
+	t, ok := r.templates[id]
+	return t, ok
+}
+
+// GetLocale returns the variant of a template in lang
+func (r *Registry) GetLocale(id, lang string) (*Template, bool) {
+	t, ok := r.templates[templateKey(id, lang)]
+	return t, ok
+}
+
+// Locales returns the languages a template is available in, sorted
+func (r *Registry) Locales(id string) []string {
+	var langs []string
+	for _, t := range r.templates {
+		if t.ID == id {
+			langs = append(langs, t.Lang)
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// List returns the templates of a kind (all kinds if empty), sorted by ID and language
+func (r *Registry) List(kind string) []*Template {
+	var result []*Template
+	for _, t := range r.templates {
+		if kind == "" || t.Kind == kind {
+			result = append(result, t)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ID != result[j].ID {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].Lang < result[j].Lang
+	})
+	return result
+}
+
+// add parses a template file: a "---" delimited key: value header followed by the body
+func (r *Registry) add(data []byte, source string) error {
+	header, body, err := splitFrontMatter(string(data))
+	if err != nil {
+		return err
+	}
+
+	t := &Template{Source: source, Lang: models.DefaultLang}
+	for key, value := range header {
+		switch key {
+		case "id":
+			t.ID = value
+		case "kind":
+			t.Kind = value
+		case "description":
+			t.Description = value
+		case "temperature":
+			t.Temperature, err = parseFloat32(key, value)
+		case "top_p":
+			t.TopP, err = parseFloat32(key, value)
+		case "max_tokens":
+			var n int64
+			n, err = strconv.ParseInt(value, 10, 32)
+			t.MaxTokens = int32(n)
+		case "tools":
+			t.Tools, err = strconv.ParseBool(value)
+		case "lang":
+			t.Lang = strings.ToLower(value)
+		default:
+			err = fmt.Errorf("unknown header field %q", key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if t.ID == "" || t.Kind == "" {
+		return fmt.Errorf("id and kind are required")
+	}
+
+	t.tmpl, err = template.New(t.ID).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return err
+	}
+
+	r.templates[templateKey(t.ID, t.Lang)] = t
+	return nil
+}
+
+func splitFrontMatter(text string) (map[string]string, string, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return nil, "", fmt.Errorf("missing --- header")
+	}
+	rawHeader, body, ok := strings.Cut(text[len("---\n"):], "\n---\n")
+	if !ok {
+		return nil, "", fmt.Errorf("unterminated header")
+	}
+
+	header := make(map[string]string)
+	for _, line := range strings.Split(rawHeader, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, "", fmt.Errorf("invalid header line %q", line)
+		}
+		header[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return header, body, nil
+}
+
+func parseFloat32(key, value string) (float32, error) {
+	f, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return float32(f), nil
+}
@@ -15,17 +15,68 @@ import (
 	"github.com/patrickmn/go-cache"
 )
 
+// sourceOutageThreshold is the number of consecutive failed fetches that marks a source down
+const sourceOutageThreshold = 2
+
 // Aggregator (Enhanced) handles multi-source data aggregation
 type Aggregator struct {
 	config *config.Config
 	cache  *cache.Cache
+
+	// Source health, for outage notifications
+	sourceMu        sync.Mutex
+	sourceFailures  map[string]int
+	sourceDown      map[string]bool
+	sourceObservers []func(event models.SourceStatusEvent)
 }
 
 // NewAggregator creates a new enhanced aggregator
 func NewAggregator(cfg *config.Config) *Aggregator {
 	return &Aggregator{
-		config: cfg,
-		cache:  cache.New(1*time.Minute, 5*time.Minute),
+		config:         cfg,
+		cache:          cache.New(1*time.Minute, 5*time.Minute),
+		sourceFailures: make(map[string]int),
+		sourceDown:     make(map[string]bool),
+	}
+}
+
+// OnSourceStatus registers an observer called when a data source goes down or recovers
+func (a *Aggregator) OnSourceStatus(observer func(event models.SourceStatusEvent)) {
+	a.sourceMu.Lock()
+	defer a.sourceMu.Unlock()
+	a.sourceObservers = append(a.sourceObservers, observer)
+}
+
+// recordSource tracks consecutive failures of a source and reports down/recovered transitions
+func (a *Aggregator) recordSource(name string, err error) {
+	a.sourceMu.Lock()
+	var event *models.SourceStatusEvent
+	if err != nil {
+		a.sourceFailures[name]++
+		if a.sourceFailures[name] >= sourceOutageThreshold && !a.sourceDown[name] {
+			a.sourceDown[name] = true
+			event = &models.SourceStatusEvent{Source: name, ConsecutiveFailures: a.sourceFailures[name], Error: err.Error(), Timestamp: time.Now()}
+		}
+	} else {
+		if a.sourceDown[name] {
+			event = &models.SourceStatusEvent{Source: name, Up: true, Timestamp: time.Now()}
+		}
+		a.sourceFailures[name] = 0
+		a.sourceDown[name] = false
+	}
+	observers := a.sourceObservers
+	a.sourceMu.Unlock()
+
+	if event == nil {
+		return
+	}
+	if event.Up {
+		log.Printf("✅ Data source %s recovered", name)
+	} else {
+		log.Printf("🚨 Data source %s is down after %d failed fetches: %s", name, event.ConsecutiveFailures, event.Error)
+	}
+	for _, observe := range observers {
+		observe(*event)
 	}
 }
 
@@ -80,9 +131,11 @@ func (a *Aggregator) FetchAllTokenData(ctx context.Context) ([]models.Token, err
 	for res := range resultChan {
 		if res.err != nil {
 			log.Printf("✗ %s fetch error: %v", res.name, res.err)
+			a.recordSource(res.name, res.err)
 		} else if res.data != nil {
 			results[res.name] = res.data
 			log.Printf("✓ %s data received", res.name)
+			a.recordSource(res.name, nil)
 		}
 	}
 
@@ -177,6 +230,36 @@ func (a *Aggregator) fetchDexScreenerAsync(ctx context.Context) (interface{}, er
 	return dexData, nil
 }
 
+// FetchTokenPairs searches DexScreener for DEX pairs whose base token is symbol
+func (a *Aggregator) FetchTokenPairs(ctx context.Context, symbol string) ([]models.DexPair, error) {
+	symbol = utils.NormalizeSymbol(symbol)
+	cacheKey := "pairs_" + symbol
+	if cached, found := a.cache.Get(cacheKey); found {
+		return cached.([]models.DexPair), nil
+	}
+
+	url := fmt.Sprintf("%s/dex/search?q=%s", a.config.DexScreenerAPIURL, symbol)
+	data, err := utils.FetchJSON(url)
+	if err != nil {
+		return nil, err
+	}
+	var resp models.DexScreenerResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	// Search matches quote tokens and names too; keep pairs that trade the token itself
+	pairs := make([]models.DexPair, 0, len(resp.Pairs))
+	for _, pair := range resp.Pairs {
+		if strings.EqualFold(pair.BaseToken.Symbol, symbol) {
+			pairs = append(pairs, pair)
+		}
+	}
+
+	a.cache.Set(cacheKey, pairs, 5*time.Minute)
+	return pairs, nil
+}
+
 // fetchMessari fundamentales
 func (a *Aggregator) fetchMessari(ctx context.Context) (interface{}, error) {
 	if a.config.MessariAPIKey == "" {
@@ -3,152 +3,416 @@ package services
 import (
 	"backend/config"
 	"backend/models"
+	"backend/prompts"
+	"backend/store"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
-
-	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
+	"time"
 )
 
-// AIService handles Gemini AI integration
+// ErrUnknownPrompt is returned when a requested prompt template doesn't exist
+var ErrUnknownPrompt = errors.New("unknown prompt template")
+
+// ErrUnsupportedLang is returned when a prompt template has no variant in the requested language
+var ErrUnsupportedLang = errors.New("unsupported language")
+
+// AIService handles AI analysis on top of a pluggable LLM provider
 type AIService struct {
-	client *genai.Client
-	model  *genai.GenerativeModel
-	config *config.Config
+	provider LLMProvider
+	prompts  *prompts.Registry
+	tools    *AnalysisTools
+	meter    *UsageMeter
+	config   *config.Config
 }
 
-// NewAIService creates a new AI service
-func NewAIService(cfg *config.Config) (*AIService, error) {
-	if cfg.GeminiAPIKey == "" {
-		return nil, fmt.Errorf("GEMINI_API_KEY not configured")
+// NewAIService creates a new AI service using the provider selected in config;
+// tools serve templates that enable function calling and meter (optional)
+// records usage and enforces the daily budgets on every provider call
+func NewAIService(cfg *config.Config, registry *prompts.Registry, tools *AnalysisTools, meter *UsageMeter) (*AIService, error) {
+	provider, err := NewLLMProvider(cfg)
+	if err != nil {
+		return nil, err
 	}
+	if meter != nil {
+		provider = newMeteredProvider(provider, meter)
+	}
+
+	log.Printf("✅ AI service initialized (provider: %s, model: %s)", provider.Name(), provider.Model())
+
+	return &AIService{
+		provider: provider,
+		prompts:  registry,
+		tools:    tools,
+		meter:    meter,
+		config:   cfg,
+	}, nil
+}
 
-	ctx := context.Background()
-	client, err := genai.NewClient(ctx, option.WithAPIKey(cfg.GeminiAPIKey))
+// Prompts returns the prompt template registry
+func (s *AIService) Prompts() *prompts.Registry {
+	return s.prompts
+}
+
+// ResolvePrompt returns the template of a kind by ID, or the configured default for an empty ID
+func (s *AIService) ResolvePrompt(kind, id string) (*prompts.Template, error) {
+	if id == "" {
+		switch kind {
+		case prompts.KindAnalysis:
+			id = s.config.AnalysisPrompt
+		case prompts.KindCompare:
+			id = s.config.ComparePrompt
+		case prompts.KindBrief:
+			id = s.config.BriefPrompt
+		case prompts.KindChat:
+			id = s.config.ChatPrompt
+		}
+	}
+	tmpl, ok := s.prompts.Get(id)
+	if !ok || tmpl.Kind != kind {
+		return nil, fmt.Errorf("%w: %q (kind %s)", ErrUnknownPrompt, id, kind)
+	}
+	return tmpl, nil
+}
+
+// ResolvePromptLang is ResolvePrompt for the variant of the template in lang
+func (s *AIService) ResolvePromptLang(kind, id, lang string) (*prompts.Template, error) {
+	tmpl, err := s.ResolvePrompt(kind, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+		return nil, err
 	}
+	lang = models.NormalizeLang(lang)
+	localized, ok := s.prompts.GetLocale(tmpl.ID, lang)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q for prompt %s (available: %s)", ErrUnsupportedLang, lang, tmpl.ID, strings.Join(s.prompts.Locales(tmpl.ID), ", "))
+	}
+	return localized, nil
+}
 
-	// Configure model
-	model := client.GenerativeModel("gemini-3-flash-preview")
-	model.SetTemperature(0.7)
-	model.SetTopP(0.9)
-	model.SetMaxOutputTokens(5000)
+// CheckBudget returns a *BudgetError if the context's client can't spend AI tokens right now
+func (s *AIService) CheckBudget(ctx context.Context) error {
+	if s.meter == nil {
+		return nil
+	}
+	return s.meter.Check(UsageClient(ctx))
+}
 
-	log.Println("✅ Gemini AI service initialized successfully")
+// Provider returns the active LLM provider
+func (s *AIService) Provider() LLMProvider {
+	return s.provider
+}
 
-	return &AIService{
-		client: client,
-		model:  model,
-		config: cfg,
+// Stream event types and progress stages emitted during a streamed analysis
+const (
+	StreamEventProgress = "progress"
+	StreamEventChunk    = "chunk"
+
+	StageGenerating = "generating"
+	StageValidating = "validating"
+	StageRepairing  = "repairing"
+	StageComplete   = "complete"
+)
+
+// StreamEvent is emitted while a streamed analysis is being generated
+type StreamEvent struct {
+	Type  string // progress or chunk
+	Stage string // set on progress events
+	Text  string // partial output on chunk events
+}
+
+// AnalyzeToken generates a validated structured AI analysis for a token,
+// retrying once with a repair prompt if the first output is malformed
+func (s *AIService) AnalyzeToken(ctx context.Context, actx *models.AnalysisContext, tmpl *prompts.Template) (*models.AnalysisRecord, error) {
+	return s.analyze(ctx, actx, tmpl, nil)
+}
+
+// AnalyzeTokenStream is AnalyzeToken with partial output and progress reported through onEvent
+func (s *AIService) AnalyzeTokenStream(ctx context.Context, actx *models.AnalysisContext, tmpl *prompts.Template, onEvent func(StreamEvent)) (*models.AnalysisRecord, error) {
+	return s.analyze(ctx, actx, tmpl, onEvent)
+}
+
+func (s *AIService) analyze(ctx context.Context, actx *models.AnalysisContext, tmpl *prompts.Template, onEvent func(StreamEvent)) (*models.AnalysisRecord, error) {
+	token := actx.Token
+	prompt, err := tmpl.Render(prompts.NewAnalysisData(actx))
+	if err != nil {
+		return nil, err
+	}
+	llmReq := LLMRequest{
+		Prompt:      prompt,
+		Schema:      tokenAnalysisSchema(tmpl.Lang),
+		Temperature: tmpl.Temperature,
+		TopP:        tmpl.TopP,
+		MaxTokens:   tmpl.MaxTokens,
+		Input:       actx,
+	}
+
+	var recorder *ToolRecorder
+	if tmpl.Tools && s.tools != nil {
+		recorder = s.tools.NewRecorder(token.Symbol)
+		llmReq.Tools = s.tools.Definitions()
+		llmReq.CallTool = recorder.Call
+	}
+
+	log.Printf("🤖 Generating AI analysis for %s (%s) with prompt %s (%s)", token.Name, token.Symbol, tmpl.ID, tmpl.Lang)
+
+	var analysis *models.TokenAnalysis
+	resp, err := s.generateValidated(ctx, llmReq, token.Symbol, onEvent, func(text string) error {
+		var parseErr error
+		analysis, parseErr = parseTokenAnalysis(text, tmpl.Lang)
+		return parseErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("✅ AI analysis generated for %s", token.Symbol)
+
+	guardrails := CheckTradingPlan(analysis, actx)
+	if guardrails.Status != models.GuardrailPassed {
+		log.Printf("⚠️  Guardrails %s for %s: %d issue(s), confidence %s", guardrails.Status, token.Symbol, len(guardrails.Issues), guardrails.Confidence)
+	}
+
+	record := &models.AnalysisRecord{
+		ID:            store.NewID(),
+		TokenID:       token.ID,
+		Symbol:        token.Symbol,
+		Provider:      s.provider.Name(),
+		Model:         s.responseModel(resp),
+		PromptVersion: tmpl.ID,
+		Lang:          tmpl.Lang,
+		Fingerprint:   NewAnalysisFingerprint(token),
+		Analysis:      analysis,
+		Guardrails:    guardrails,
+		GeneratedAt:   time.Now(),
+	}
+	if recorder != nil {
+		record.ToolCalls = recorder.Invocations()
+	}
+	return record, nil
+}
+
+// CompareTokens ranks 2-5 tokens side by side and recommends one
+func (s *AIService) CompareTokens(ctx context.Context, cctx *models.ComparisonContext, tmpl *prompts.Template) (*models.ComparisonRecord, error) {
+	prompt, err := tmpl.Render(prompts.NewCompareData(cctx))
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.ComparisonRecord{
+		Provider:      s.provider.Name(),
+		PromptVersion: tmpl.ID,
+	}
+	for _, actx := range cctx.Tokens {
+		record.TokenIDs = append(record.TokenIDs, actx.Token.ID)
+		record.Symbols = append(record.Symbols, actx.Token.Symbol)
+	}
+	label := strings.Join(record.Symbols, " vs ")
+
+	llmReq := LLMRequest{
+		Prompt:      prompt,
+		Schema:      tokenComparisonSchema(),
+		Temperature: tmpl.Temperature,
+		TopP:        tmpl.TopP,
+		MaxTokens:   tmpl.MaxTokens,
+		Input:       cctx,
+	}
+
+	log.Printf("🤖 Generating AI comparison for %s with prompt %s", label, tmpl.ID)
+
+	resp, err := s.generateValidated(ctx, llmReq, label, nil, func(text string) error {
+		var comparison models.TokenComparison
+		if err := json.Unmarshal([]byte(stripJSONFences(text)), &comparison); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		if err := comparison.Validate(record.Symbols); err != nil {
+			return err
+		}
+		record.Comparison = &comparison
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("✅ AI comparison generated for %s", label)
+
+	record.Model = s.responseModel(resp)
+	record.GeneratedAt = time.Now()
+	return record, nil
+}
+
+// GenerateBrief writes the daily market brief from an assembled snapshot
+func (s *AIService) GenerateBrief(ctx context.Context, inputs *models.BriefInputs, tmpl *prompts.Template) (*models.BriefRecord, error) {
+	prompt, err := tmpl.Render(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	llmReq := LLMRequest{
+		Prompt:      prompt,
+		Schema:      marketBriefSchema(),
+		Temperature: tmpl.Temperature,
+		TopP:        tmpl.TopP,
+		MaxTokens:   tmpl.MaxTokens,
+		Input:       inputs,
+	}
+
+	log.Printf("🤖 Generating market brief for %s with prompt %s", inputs.Date, tmpl.ID)
+
+	var brief models.MarketBrief
+	label := "brief " + inputs.Date
+	resp, err := s.generateValidated(ctx, llmReq, label, nil, func(text string) error {
+		brief = models.MarketBrief{}
+		if err := json.Unmarshal([]byte(stripJSONFences(text)), &brief); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		return brief.Validate()
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("✅ Market brief generated for %s", inputs.Date)
+
+	return &models.BriefRecord{
+		Date:          inputs.Date,
+		Provider:      s.provider.Name(),
+		Model:         s.responseModel(resp),
+		PromptVersion: tmpl.ID,
+		Brief:         &brief,
+		Inputs:        inputs,
+		GeneratedAt:   time.Now(),
 	}, nil
 }
 
-// AnalyzeToken generates AI analysis for a token
-func (s *AIService) AnalyzeToken(ctx context.Context, req models.AnalysisRequest) (string, error) {
-	prompt := s.buildAnalysisPrompt(req)
+// Chat answers the next message of a token chat. The system prompt is re-rendered
+// from the context each turn so answers track the current data; maxTokens caps the reply.
+// A failed call still returns the usage it spent, when the provider reported any.
+func (s *AIService) Chat(ctx context.Context, cctx *models.ChatContext, tmpl *prompts.Template, maxTokens int32) (*LLMResponse, error) {
+	system, err := tmpl.Render(prompts.NewChatData(cctx))
+	if err != nil {
+		return nil, err
+	}
+
+	req := LLMChatRequest{
+		System:      system,
+		Message:     cctx.Message,
+		Temperature: tmpl.Temperature,
+		TopP:        tmpl.TopP,
+		MaxTokens:   tmpl.MaxTokens,
+		Input:       cctx,
+	}
+	if maxTokens > 0 && (req.MaxTokens == 0 || maxTokens < req.MaxTokens) {
+		req.MaxTokens = maxTokens
+	}
+	for _, message := range cctx.History {
+		req.History = append(req.History, LLMMessage{Role: message.Role, Text: message.Content})
+	}
+
+	resp, err := s.provider.Chat(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	resp.Model = s.responseModel(resp)
+	return resp, nil
+}
+
+// generateValidated runs a JSON-mode request and decodes the output with decode.
+// A decode failure gets one non-streamed retry with a repair prompt. When onEvent
+// is set the first attempt is streamed and progress stages are reported.
+func (s *AIService) generateValidated(ctx context.Context, llmReq LLMRequest, label string, onEvent func(StreamEvent), decode func(text string) error) (*LLMResponse, error) {
+	progress := func(stage string) {
+		if onEvent != nil {
+			onEvent(StreamEvent{Type: StreamEventProgress, Stage: stage})
+		}
+	}
 
-	log.Printf("🤖 Generating AI analysis for %s (%s)", req.Name, req.Symbol)
+	progress(StageGenerating)
 
-	resp, err := s.model.GenerateContent(ctx, genai.Text(prompt))
+	var resp *LLMResponse
+	var err error
+	if onEvent != nil {
+		resp, err = s.provider.GenerateStream(ctx, llmReq, func(text string) {
+			onEvent(StreamEvent{Type: StreamEventChunk, Text: text})
+		})
+	} else {
+		resp, err = s.provider.Generate(ctx, llmReq)
+	}
 	if err != nil {
-		return "", fmt.Errorf("AI generation failed: %w", err)
-	}
-
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("empty response from AI")
-	}
-
-	analysis := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
-
-	// Clean up markdown code blocks if present
-	analysis = strings.TrimPrefix(analysis, "```json")
-	analysis = strings.TrimPrefix(analysis, "```")
-	analysis = strings.TrimSuffix(analysis, "```")
-	analysis = strings.TrimSpace(analysis)
-
-	log.Printf("✅ AI analysis generated for %s", req.Symbol)
-	return analysis, nil
-}
-
-// buildAnalysisPrompt creates a structured prompt for Gemini to return JSON
-func (s *AIService) buildAnalysisPrompt(req models.AnalysisRequest) string {
-	return fmt.Sprintf(`You are AlphaAgent - An advanced Crypto Market Analysis AI. Your role is to act as a veteran Trader/Analyst to analyze the following token and provide a specific trading strategy.
-
-Based on the provided market data, analyze and return the result strictly in JSON format (Do NOT allow introductory text):
-
-{
-  "summary": "Sharp overview of token status (under 30 words)",
-  "growth_potential": {
-    "score": (number 0-100),
-    "reason": "Core reason for this score"
-  },
-  "technical_analysis": {
-    "trend": "Main Trend (Uptrend/Downtrend/Accumulation/Distribution)",
-    "strength": "Trend Strength (Very Strong/Strong/Weak/Neutral)",
-    "key_levels": "Key Support and Nearest Resistance"
-  },
-  "risk_analysis": {
-    "level": "Risk Level (Low/Medium/High/Extreme)",
-    "concerns": ["Risk 1 (concise)", "Risk 2"]
-  },
-  "fundamental_analysis": {
-    "sector": "Primary Sector/Field (e.g., Layer 1, DeFi, AI, Real World Assets)",
-    "tokenomics": "Tokenomics Assessment (e.g., Deflationary, High Inflation, Fair Launch, VC Heavy)",
-    "economic_moat": "Competitive Advantage/Moat (e.g., Network Effect, Tech Lead, Community)"
-  },
-  "recommendation": {
-    "action": "ACTION (BUY NOW / BUY ZONE / HOLD / SELL / WATCH)",
-    "entry_zone": "Optimal Entry Zone (specific)",
-    "target": "Primary Price Target"
-  },
-  "trading_plan": {
-    "buy_strategy": "Detailed Buy Strategy (e.g., DCA at zone A and B, or Breakout C)",
-    "sell_targets": ["TP1: $Price (Soft Target)", "TP2: $Price", "TP3: $Price (Moonbag)"],
-    "stop_loss": "Stop Loss Price (or Invalidation Condition)",
-    "time_horizon": "Time Horizon (Short-term/Mid-term/Long-term)"
-  },
-  "insights": [
-    "Insight 1: Analysis of Liquidity/Volume vs Mcap (Velocity)",
-    "Insight 2: What 30d/90d price action says about money flow",
-    "Insight 3: Correlation with general market (Beta)"
-  ]
-}
-
-**INPUT DATA:**
-- Token: %s (%s) | Rank: #%d
-- Current Price: $%.6f
-- 24h Change: %.2f%% | 7d Change: %.2f%%
-- Mid-term Trend: 30d: %.2f%% | 90d: %.2f%%
-- Market Cap: $%.2f | Fully Diluted Valuation (FDV): $%.2f
-- Supply: Circulating %.1f%% / Max Supply
-- Volume 24h: $%.2f (Vol/Mcap Ratio: %.4f)
-- Liquidity: $%.2f | TVL: $%.2f
-- Alpha Trust Score: %.1f/100
-
-**IMPORTANT NOTES:**
-1. If Liquidity/Mcap is low (<1%%), warn about high liquidity risk.
-2. If FDV >> Mcap, warn about token inflation/unlocks.
-3. Price targets (TP/SL) must be based on price volatility (Change 7d/30d) and current price, estimate support/resistance reasonably.
-4. Respond entirely in professional Crypto English.`,
-		req.Name, req.Symbol, req.Rank,
-		req.Price,
-		req.Change24h, req.Change7d,
-		req.Change30d, req.Change90d,
-		req.MarketCap, req.Price*req.TotalSupply,
-		(req.CirculatingSupply/req.MaxSupply)*100,
-		req.Volume24h, req.Volume24h/req.MarketCap,
-		req.Liquidity, req.TVL,
-		req.TrustScore,
-	)
-}
-
-// Close closes the AI client
+		return nil, err
+	}
+
+	progress(StageValidating)
+	if parseErr := decode(resp.Text); parseErr != nil {
+		log.Printf("⚠️  Malformed AI output for %s (%v) - retrying with repair prompt", label, parseErr)
+		progress(StageRepairing)
+
+		// The repair only reformats, so it runs in JSON mode without tools
+		original := llmReq.Prompt
+		llmReq.Prompt = buildRepairPrompt(original, resp.Text, parseErr)
+		llmReq.Tools, llmReq.CallTool = nil, nil
+		resp, err = s.provider.Generate(ctx, llmReq)
+		if err != nil {
+			return nil, err
+		}
+		if parseErr = decode(resp.Text); parseErr != nil {
+			return nil, fmt.Errorf("AI output failed validation after repair: %w", parseErr)
+		}
+	}
+
+	progress(StageComplete)
+	return resp, nil
+}
+
+// responseModel is the model that answered, falling back to the configured one
+func (s *AIService) responseModel(resp *LLMResponse) string {
+	if resp.Model != "" {
+		return resp.Model
+	}
+	return s.provider.Model()
+}
+
+// stripJSONFences removes markdown code fences some models add even in JSON mode
+func stripJSONFences(raw string) string {
+	cleaned := strings.TrimSpace(raw)
+	cleaned = strings.TrimPrefix(cleaned, "```json")
+	cleaned = strings.TrimPrefix(cleaned, "```")
+	cleaned = strings.TrimSuffix(cleaned, "```")
+	return strings.TrimSpace(cleaned)
+}
+
+// parseTokenAnalysis decodes and validates model output written in lang
+func parseTokenAnalysis(raw, lang string) (*models.TokenAnalysis, error) {
+	var analysis models.TokenAnalysis
+	if err := json.Unmarshal([]byte(stripJSONFences(raw)), &analysis); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if err := analysis.ValidateLang(lang); err != nil {
+		return nil, err
+	}
+	return &analysis, nil
+}
+
+// buildRepairPrompt asks the model to fix its previous output against the validation error
+func buildRepairPrompt(originalPrompt, badOutput string, problem error) string {
+	return fmt.Sprintf(`%s
+
+**CORRECTION REQUIRED:**
+Your previous response could not be accepted: %s
+
+Previous response:
+%s
+
+Return the corrected analysis as a single JSON object that matches the schema exactly. Do not add any other text.`,
+		originalPrompt, problem.Error(), badOutput)
+}
+
+// Close releases the provider
 func (s *AIService) Close() {
-	if s.client != nil {
-		s.client.Close()
-		log.Println("✅ Gemini AI client closed")
+	if err := s.provider.Close(); err != nil {
+		log.Printf("⚠️  Failed to close %s provider: %v", s.provider.Name(), err)
+		return
 	}
+	log.Printf("✅ %s provider closed", s.provider.Name())
 }
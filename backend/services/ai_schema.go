@@ -0,0 +1,104 @@
+package services
+
+import "backend/models"
+
+// Schema builders shared by the structured output schemas
+func schemaString(description string) *LLMSchema {
+	return &LLMSchema{Type: "string", Description: description}
+}
+
+func schemaEnum(description string, values []string) *LLMSchema {
+	return &LLMSchema{Type: "string", Enum: values, Description: description}
+}
+
+func schemaList(description string) *LLMSchema {
+	return &LLMSchema{Type: "array", Items: &LLMSchema{Type: "string"}, Description: description}
+}
+
+// schemaObject marks every property as required
+func schemaObject(properties map[string]*LLMSchema) *LLMSchema {
+	required := make([]string, 0, len(properties))
+	for name := range properties {
+		required = append(required, name)
+	}
+	return &LLMSchema{Type: "object", Properties: properties, Required: required}
+}
+
+// tokenAnalysisSchema mirrors models.TokenAnalysis so providers return it in JSON mode;
+// enum fields also accept their labels in lang
+func tokenAnalysisSchema(lang string) *LLMSchema {
+	enum := func(description string, values []string) *LLMSchema {
+		return schemaEnum(description, models.LocalizedEnumValues(values, lang))
+	}
+	return schemaObject(map[string]*LLMSchema{
+		"summary": schemaString("Sharp overview of token status (under 30 words)"),
+		"growth_potential": schemaObject(map[string]*LLMSchema{
+			"score":  {Type: "number", Description: "Growth potential 0-100"},
+			"reason": schemaString("Core reason for this score"),
+		}),
+		"technical_analysis": schemaObject(map[string]*LLMSchema{
+			"trend":      enum("Main trend", models.AnalysisTrends),
+			"strength":   enum("Trend strength", models.AnalysisStrengths),
+			"key_levels": schemaString("Key support and nearest resistance"),
+		}),
+		"risk_analysis": schemaObject(map[string]*LLMSchema{
+			"level":    enum("Risk level", models.AnalysisRiskLevels),
+			"concerns": schemaList("Concise risks"),
+		}),
+		"fundamental_analysis": schemaObject(map[string]*LLMSchema{
+			"sector":        schemaString("Primary sector"),
+			"tokenomics":    schemaString("Tokenomics assessment"),
+			"economic_moat": schemaString("Competitive advantage"),
+		}),
+		"recommendation": schemaObject(map[string]*LLMSchema{
+			"action":     enum("Action", models.AnalysisActions),
+			"entry_zone": schemaString("Optimal entry zone (specific)"),
+			"target":     schemaString("Primary price target"),
+		}),
+		"trading_plan": schemaObject(map[string]*LLMSchema{
+			"buy_strategy": schemaString("Detailed buy strategy"),
+			"sell_targets": schemaList("Take-profit levels, e.g. TP1: $Price"),
+			"stop_loss":    schemaString("Stop loss price or invalidation condition"),
+			"time_horizon": enum("Time horizon", models.AnalysisTimeHorizons),
+		}),
+		"insights": schemaList("Key insights"),
+	})
+}
+
+// tokenComparisonSchema mirrors models.TokenComparison
+func tokenComparisonSchema() *LLMSchema {
+	return schemaObject(map[string]*LLMSchema{
+		"summary": schemaString("One-paragraph comparison overview (under 60 words)"),
+		"ranking": {
+			Type:        "array",
+			Description: "Every compared token exactly once, best first",
+			Items: schemaObject(map[string]*LLMSchema{
+				"rank":    {Type: "integer", Description: "1 = most attractive"},
+				"symbol":  schemaString("Token symbol as given in the input"),
+				"score":   {Type: "number", Description: "Relative attractiveness 0-100"},
+				"pros":    schemaList("Advantages versus the other tokens"),
+				"cons":    schemaList("Disadvantages versus the other tokens"),
+				"verdict": schemaString("One-sentence verdict"),
+			}),
+		},
+		"recommendation": schemaObject(map[string]*LLMSchema{
+			"preferred":    schemaString("Symbol of the preferred token"),
+			"action":       schemaEnum("Action for the preferred token", models.AnalysisActions),
+			"rationale":    schemaString("Why it is preferred over the others"),
+			"time_horizon": schemaEnum("Time horizon", models.AnalysisTimeHorizons),
+		}),
+	})
+}
+
+// marketBriefSchema mirrors models.MarketBrief
+func marketBriefSchema() *LLMSchema {
+	return schemaObject(map[string]*LLMSchema{
+		"headline":   schemaString("One-line headline (under 15 words)"),
+		"sentiment":  schemaEnum("Overall market sentiment", models.BriefSentiments),
+		"summary":    schemaString("3-4 sentence market overview"),
+		"highlights": schemaList("Key observations with numbers"),
+		"sectors":    schemaList("Sector rotation notes"),
+		"watchlist":  schemaList("SYMBOL: why it deserves attention today"),
+		"risks":      schemaList("Risks to monitor today"),
+	})
+}
@@ -0,0 +1,374 @@
+package services
+
+import (
+	"backend/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tool limits
+const (
+	toolDefaultHistoryDays = 90
+	toolMaxHistoryDays     = 365
+	toolMaxClosePoints     = 60 // closes returned by get_history, thinned evenly
+	toolMaxPairs           = 10
+)
+
+// AnalysisTools exposes backend data to the model as callable functions
+type AnalysisTools struct {
+	market     *MarketData
+	history    *HistoryService
+	analytics  *MarketAnalytics
+	aggregator *Aggregator
+	scorer     *EnhancedScorer
+}
+
+// NewAnalysisTools creates the tool set
+func NewAnalysisTools(market *MarketData, history *HistoryService, analytics *MarketAnalytics, aggregator *Aggregator, scorer *EnhancedScorer) *AnalysisTools {
+	return &AnalysisTools{
+		market:     market,
+		history:    history,
+		analytics:  analytics,
+		aggregator: aggregator,
+		scorer:     scorer,
+	}
+}
+
+// Definitions describes every tool to the model
+func (t *AnalysisTools) Definitions() []LLMTool {
+	tokenArg := schemaString("Token ID (slug), symbol or name, e.g. bitcoin or BTC")
+	daysArg := &LLMSchema{Type: "integer", Description: fmt.Sprintf("Days of daily history (default %d, max %d)", toolDefaultHistoryDays, toolMaxHistoryDays)}
+
+	return []LLMTool{
+		{
+			Name:        "get_token",
+			Description: "Current market data, supply and Alpha Trust Score breakdown of a token",
+			Parameters:  toolParameters(map[string]*LLMSchema{"token_id": tokenArg}, "token_id"),
+		},
+		{
+			Name:        "get_history",
+			Description: "Daily price history summary (range, 30d/90d change, volatility) and closing prices",
+			Parameters:  toolParameters(map[string]*LLMSchema{"token_id": tokenArg, "days": daysArg}, "token_id"),
+		},
+		{
+			Name:        "get_indicators",
+			Description: "Latest daily RSI, MACD, Bollinger Bands, ATR and Stochastic values",
+			Parameters:  toolParameters(map[string]*LLMSchema{"token_id": tokenArg}, "token_id"),
+		},
+		{
+			Name:        "get_pairs",
+			Description: "Largest DEX trading pairs of a token by liquidity (chain, DEX, liquidity, 24h volume)",
+			Parameters:  toolParameters(map[string]*LLMSchema{"token_id": tokenArg}, "token_id"),
+		},
+		{
+			Name:        "get_sector_benchmark",
+			Description: "Sector benchmark medians (returns, volume/mcap, TVL/mcap, liquidity) and top constituents",
+			Parameters:  toolParameters(map[string]*LLMSchema{"category": schemaString("Sector name, e.g. DeFi or Layer 1")}, "category"),
+		},
+		{
+			Name:        "compare_to_btc",
+			Description: "Beta and correlation to BTC plus the token's returns relative to BTC",
+			Parameters:  toolParameters(map[string]*LLMSchema{"token_id": tokenArg, "days": daysArg}, "token_id"),
+		},
+	}
+}
+
+// NewRecorder returns a caller for one generation that records every invocation
+func (t *AnalysisTools) NewRecorder(label string) *ToolRecorder {
+	return &ToolRecorder{tools: t, label: label}
+}
+
+// ToolRecorder runs tool calls for one generation and keeps the log
+type ToolRecorder struct {
+	tools *AnalysisTools
+	label string
+
+	mu    sync.Mutex
+	calls []models.ToolInvocation
+}
+
+// Call executes a tool; it satisfies LLMToolCaller
+func (r *ToolRecorder) Call(ctx context.Context, name string, args map[string]interface{}) map[string]interface{} {
+	start := time.Now()
+	value, err := r.tools.execute(ctx, name, args)
+
+	invocation := models.ToolInvocation{
+		Name:       name,
+		Args:       args,
+		DurationMs: time.Since(start).Milliseconds(),
+		CalledAt:   start,
+	}
+	var result map[string]interface{}
+	if err == nil {
+		result, err = toToolResult(value)
+	}
+	if err != nil {
+		invocation.Error = err.Error()
+		result = map[string]interface{}{"error": err.Error()}
+		log.Printf("🔧 %s: %s(%v) failed: %v", r.label, name, args, err)
+	} else {
+		invocation.Result = result
+		log.Printf("🔧 %s: %s(%v) in %dms", r.label, name, args, invocation.DurationMs)
+	}
+
+	r.mu.Lock()
+	r.calls = append(r.calls, invocation)
+	r.mu.Unlock()
+	return result
+}
+
+// Invocations returns the recorded calls in order
+func (r *ToolRecorder) Invocations() []models.ToolInvocation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.ToolInvocation(nil), r.calls...)
+}
+
+// execute dispatches a tool call by name
+func (t *AnalysisTools) execute(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	switch name {
+	case "get_token":
+		return t.getToken(ctx, args)
+	case "get_history":
+		return t.getHistory(ctx, args)
+	case "get_indicators":
+		return t.getIndicators(ctx, args)
+	case "get_pairs":
+		return t.getPairs(ctx, args)
+	case "get_sector_benchmark":
+		return t.getSectorBenchmark(args)
+	case "compare_to_btc":
+		return t.compareToBTC(ctx, args)
+	default:
+		return nil, fmt.Errorf("unknown tool %q", name)
+	}
+}
+
+func (t *AnalysisTools) getToken(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	token, err := t.resolveToken(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"id":                 token.ID,
+		"symbol":             token.Symbol,
+		"name":               token.Name,
+		"rank":               token.Rank,
+		"category":           token.Category,
+		"price":              token.Price,
+		"change_24h":         token.Change24h,
+		"change_7d":          token.Change7d,
+		"change_30d":         token.Change30d,
+		"change_90d":         token.Change90d,
+		"market_cap":         token.MarketCap,
+		"fully_diluted":      token.FullyDilutedValue,
+		"volume_24h":         token.Volume24h,
+		"liquidity":          token.Liquidity,
+		"tvl":                token.TVL,
+		"circulating_supply": token.CirculatingSupply,
+		"total_supply":       token.TotalSupply,
+		"max_supply":         token.MaxSupply,
+		"trust_score":        token.TrustScore,
+		"score_breakdown":    token.ScoreBreakdown,
+	}, nil
+}
+
+func (t *AnalysisTools) getHistory(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	token, err := t.resolveToken(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	candles, err := t.history.GetDailyCandles(ctx, token.Symbol, toolDays(args))
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) < 2 {
+		return nil, fmt.Errorf("not enough history for %s", token.Symbol)
+	}
+
+	step := (len(candles) + toolMaxClosePoints - 1) / toolMaxClosePoints
+	closes := make([]map[string]interface{}, 0, toolMaxClosePoints+1)
+	for i := len(candles) - 1; i >= 0; i -= step {
+		closes = append(closes, map[string]interface{}{
+			"date":  candles[i].Time.Format("2006-01-02"),
+			"close": candles[i].Close,
+		})
+	}
+	for i, j := 0, len(closes)-1; i < j; i, j = i+1, j-1 {
+		closes[i], closes[j] = closes[j], closes[i]
+	}
+
+	return map[string]interface{}{
+		"symbol":  token.Symbol,
+		"summary": summarizeHistory(candles),
+		"closes":  closes,
+	}, nil
+}
+
+func (t *AnalysisTools) getIndicators(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	token, err := t.resolveToken(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	candles, err := t.history.GetDailyCandles(ctx, token.Symbol, analysisHistoryDays)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) < 2 {
+		return nil, fmt.Errorf("not enough history for %s", token.Symbol)
+	}
+	return map[string]interface{}{
+		"symbol":     token.Symbol,
+		"indicators": snapshotIndicators(candles),
+	}, nil
+}
+
+func (t *AnalysisTools) getPairs(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	token, err := t.resolveToken(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	pairs, err := t.aggregator.FetchTokenPairs(ctx, token.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Liquidity.USD > pairs[j].Liquidity.USD })
+	if len(pairs) > toolMaxPairs {
+		pairs = pairs[:toolMaxPairs]
+	}
+	result := make([]map[string]interface{}, 0, len(pairs))
+	for _, pair := range pairs {
+		result = append(result, map[string]interface{}{
+			"chain":      pair.ChainID,
+			"dex":        pair.DexID,
+			"pair":       pair.BaseToken.Symbol + "/" + pair.QuoteToken.Symbol,
+			"price_usd":  pair.PriceUsd,
+			"liquidity":  pair.Liquidity.USD,
+			"volume_24h": pair.Volume.H24,
+		})
+	}
+	return map[string]interface{}{"symbol": token.Symbol, "pairs": result}, nil
+}
+
+func (t *AnalysisTools) getSectorBenchmark(args map[string]interface{}) (interface{}, error) {
+	category, _ := args["category"].(string)
+	benchmarks := t.scorer.CategoryBenchmarks()
+
+	available := make([]string, 0, len(benchmarks))
+	for _, benchmark := range benchmarks {
+		if strings.EqualFold(benchmark.Category, strings.TrimSpace(category)) {
+			return benchmark, nil
+		}
+		available = append(available, benchmark.Category)
+	}
+	sort.Strings(available)
+	return nil, fmt.Errorf("unknown category %q; available: %s", category, strings.Join(available, ", "))
+}
+
+func (t *AnalysisTools) compareToBTC(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	token, err := t.resolveToken(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	days := toolDays(args)
+
+	candles, err := t.history.GetDailyCandles(ctx, token.Symbol, days)
+	if err != nil {
+		return nil, err
+	}
+	btcCandles, err := t.history.GetDailyCandles(ctx, BenchmarkBTC, days)
+	if err != nil {
+		return nil, err
+	}
+	closes, btcCloses := closesOf(candles), closesOf(btcCandles)
+	if len(closes) < 2 || len(btcCloses) < 2 {
+		return nil, fmt.Errorf("not enough history to compare %s with BTC", token.Symbol)
+	}
+
+	returns := map[string]interface{}{}
+	for _, period := range []int{7, 30, 90} {
+		if period >= len(closes) || period >= len(btcCloses) {
+			continue
+		}
+		own := periodChange(closes, closes[len(closes)-1], period)
+		btc := periodChange(btcCloses, btcCloses[len(btcCloses)-1], period)
+		returns[fmt.Sprintf("%dd", period)] = map[string]float64{
+			"token":  own,
+			"btc":    btc,
+			"excess": own - btc,
+		}
+	}
+
+	result := map[string]interface{}{
+		"symbol":  token.Symbol,
+		"returns": returns,
+	}
+	if report, err := t.analytics.TokenBeta(ctx, token.Symbol, DefaultBetaWindow); err == nil {
+		if stats, ok := report.Benchmarks[BenchmarkBTC]; ok {
+			result["beta"] = stats.Beta
+			result["correlation"] = stats.Correlation
+			result["window_days"] = report.WindowDays
+		}
+	}
+	return result, nil
+}
+
+// resolveToken finds the token named by the token_id argument
+func (t *AnalysisTools) resolveToken(ctx context.Context, args map[string]interface{}) (models.Token, error) {
+	id, _ := args["token_id"].(string)
+	if strings.TrimSpace(id) == "" {
+		return models.Token{}, fmt.Errorf("token_id is required")
+	}
+	token, found, err := t.market.FindToken(ctx, id)
+	if err != nil {
+		return models.Token{}, err
+	}
+	if !found {
+		return models.Token{}, fmt.Errorf("%w: %s", ErrTokenNotFound, id)
+	}
+	return token, nil
+}
+
+// toolDays reads the optional days argument (JSON numbers arrive as float64)
+func toolDays(args map[string]interface{}) int {
+	days := toolDefaultHistoryDays
+	if value, ok := args["days"].(float64); ok && value >= 2 {
+		days = int(value)
+	}
+	if days > toolMaxHistoryDays {
+		days = toolMaxHistoryDays
+	}
+	return days
+}
+
+func closesOf(candles []models.Candle) []float64 {
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close
+	}
+	return closes
+}
+
+func toolParameters(properties map[string]*LLMSchema, required ...string) *LLMSchema {
+	return &LLMSchema{Type: "object", Properties: properties, Required: required}
+}
+
+// toToolResult converts a result to a plain JSON object, as function responses require
+func toToolResult(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,659 @@
+package services
+
+import (
+	"backend/models"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Alert rule grammar (keywords are case-insensitive):
+//
+//	rule      = [scope] expr
+//	scope     = SYMBOL [with|where] | any [token|tokens] [in CATEGORY] [with|where]
+//	expr      = and {or and}
+//	and       = unary {and unary}
+//	unary     = not unary | "(" expr ")" | condition
+//	condition = FIELD op VALUE | FIELD (above|below) VALUE
+//	          | FIELD crosses [above|below] NUMBER
+//	          | grade (downgraded|upgraded) [below|above GRADE]
+//
+// Numbers accept $, thousands separators and k/m/b suffixes ("100k", "$1.5b").
+// Without a scope the rule applies to every token.
+
+// alertField reads one token field; exactly one of num and str is set
+type alertField struct {
+	num func(t *models.Token) float64
+	str func(t *models.Token) string
+}
+
+var alertFields = map[string]alertField{
+	"price":             {num: func(t *models.Token) float64 { return t.Price }},
+	"market_cap":        {num: func(t *models.Token) float64 { return t.MarketCap }},
+	"volume_24h":        {num: func(t *models.Token) float64 { return t.Volume24h }},
+	"volume_change_24h": {num: func(t *models.Token) float64 { return t.VolumeChange24h }},
+	"liquidity":         {num: func(t *models.Token) float64 { return t.Liquidity }},
+	"tvl":               {num: func(t *models.Token) float64 { return t.TVL }},
+	"fdv":               {num: func(t *models.Token) float64 { return t.FullyDilutedValue }},
+	"rank":              {num: func(t *models.Token) float64 { return float64(t.Rank) }},
+	"change_1h":         {num: func(t *models.Token) float64 { return t.Change1h }},
+	"change_24h":        {num: func(t *models.Token) float64 { return t.Change24h }},
+	"change_7d":         {num: func(t *models.Token) float64 { return t.Change7d }},
+	"change_30d":        {num: func(t *models.Token) float64 { return t.Change30d }},
+	"change_90d":        {num: func(t *models.Token) float64 { return t.Change90d }},
+	"ath_change":        {num: func(t *models.Token) float64 { return t.AthChange }},
+	"rsi_14":            {num: func(t *models.Token) float64 { return t.RSI14 }},
+	"volatility_30d":    {num: func(t *models.Token) float64 { return t.Volatility30d }},
+	"trust_score":       {num: func(t *models.Token) float64 { return t.TrustScore }},
+	"confidence":        {num: func(t *models.Token) float64 { return t.ScoreBreakdown.Confidence }},
+	"peg_deviation_bps": {num: func(t *models.Token) float64 {
+		if t.ScoreBreakdown.Stablecoin == nil {
+			return 0
+		}
+		return t.ScoreBreakdown.Stablecoin.PegDeviationBps
+	}},
+	"symbol":   {str: func(t *models.Token) string { return t.Symbol }},
+	"name":     {str: func(t *models.Token) string { return t.Name }},
+	"category": {str: func(t *models.Token) string { return t.Category }},
+	"grade":    {str: func(t *models.Token) string { return t.ScoreBreakdown.Grade }},
+}
+
+var alertFieldAliases = map[string]string{
+	"mcap":   "market_cap",
+	"volume": "volume_24h",
+	"score":  "trust_score",
+	"rsi":    "rsi_14",
+	"sector": "category",
+}
+
+// alertGrades orders score grades from worst to best
+var alertGrades = []string{"F", "D", "C", "B", "A", "S"}
+
+func gradeRank(grade string) int {
+	for i, g := range alertGrades {
+		if strings.EqualFold(g, grade) {
+			return i
+		}
+	}
+	return -1
+}
+
+// alertCondition is a compiled rule expression; prev is the token on the previous snapshot, if any
+type alertCondition interface {
+	match(cur, prev *models.Token) bool
+}
+
+type alertAnd []alertCondition
+type alertOr []alertCondition
+type alertNot struct{ inner alertCondition }
+
+func (c alertAnd) match(cur, prev *models.Token) bool {
+	for _, inner := range c {
+		if !inner.match(cur, prev) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c alertOr) match(cur, prev *models.Token) bool {
+	for _, inner := range c {
+		if inner.match(cur, prev) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c alertNot) match(cur, prev *models.Token) bool {
+	return !c.inner.match(cur, prev)
+}
+
+// alertCompare is FIELD op VALUE
+type alertCompare struct {
+	field alertField
+	op    string
+	num   float64
+	str   string
+	grade bool // compare str by grade order instead of equality
+}
+
+func (c alertCompare) match(cur, _ *models.Token) bool {
+	if c.field.num != nil {
+		return compareFloat(c.field.num(cur), c.op, c.num)
+	}
+	value := c.field.str(cur)
+	if c.grade {
+		return compareFloat(float64(gradeRank(value)), c.op, float64(gradeRank(c.str)))
+	}
+	switch c.op {
+	case "==":
+		return strings.EqualFold(value, c.str)
+	case "!=":
+		return !strings.EqualFold(value, c.str)
+	}
+	return false
+}
+
+func compareFloat(a float64, op string, b float64) bool {
+	switch op {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	}
+	return false
+}
+
+// alertCross fires on the snapshot where a numeric field moves across a level
+type alertCross struct {
+	field     alertField
+	direction string // above, below or "" for either
+	level     float64
+}
+
+func (c alertCross) match(cur, prev *models.Token) bool {
+	if prev == nil {
+		return false
+	}
+	before, after := c.field.num(prev), c.field.num(cur)
+	up := before <= c.level && after > c.level
+	down := before >= c.level && after < c.level
+	switch c.direction {
+	case "above":
+		return up
+	case "below":
+		return down
+	}
+	return up || down
+}
+
+// alertGradeChange fires on the snapshot where the score grade moves down (or up), optionally across a bound
+type alertGradeChange struct {
+	down  bool
+	bound int // grade rank, -1 for any change
+}
+
+func (c alertGradeChange) match(cur, prev *models.Token) bool {
+	if prev == nil {
+		return false
+	}
+	before, after := gradeRank(prev.ScoreBreakdown.Grade), gradeRank(cur.ScoreBreakdown.Grade)
+	if before < 0 || after < 0 {
+		return false
+	}
+	if c.down {
+		return after < before && (c.bound < 0 || (before >= c.bound && after < c.bound))
+	}
+	return after > before && (c.bound < 0 || (before <= c.bound && after > c.bound))
+}
+
+// alertScope limits a rule to one token or one category
+type alertScope struct {
+	token    string // symbol or ID
+	category string
+}
+
+func (s alertScope) includes(t *models.Token) bool {
+	if s.token != "" {
+		return strings.EqualFold(t.Symbol, s.token) || strings.EqualFold(t.ID, s.token)
+	}
+	if s.category != "" {
+		if strings.EqualFold(t.Category, s.category) {
+			return true
+		}
+		for _, tag := range t.Tags {
+			if strings.EqualFold(tag, s.category) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// compiledAlert is a parsed rule ready for evaluation
+type compiledAlert struct {
+	scope     alertScope
+	condition alertCondition
+	fields    []string // referenced fields, reported with fired events
+}
+
+// values reads the referenced fields of a token
+func (a *compiledAlert) values(t *models.Token) map[string]interface{} {
+	values := make(map[string]interface{}, len(a.fields))
+	for _, name := range a.fields {
+		field := alertFields[name]
+		if field.num != nil {
+			values[name] = field.num(t)
+		} else {
+			values[name] = field.str(t)
+		}
+	}
+	return values
+}
+
+// Lexer
+
+const (
+	lexWord = iota
+	lexNumber
+	lexString
+	lexOp
+	lexLParen
+	lexRParen
+)
+
+type alertLexeme struct {
+	kind int
+	text string
+	num  float64
+}
+
+func lexAlert(input string) ([]alertLexeme, error) {
+	var out []alertLexeme
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			out = append(out, alertLexeme{kind: lexLParen, text: "("})
+			i++
+		case r == ')':
+			out = append(out, alertLexeme{kind: lexRParen, text: ")"})
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i+1)
+			}
+			out = append(out, alertLexeme{kind: lexString, text: string(runes[i+1 : end])})
+			i = end + 1
+		case strings.ContainsRune("<>=!", r):
+			op := string(r)
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				op += "="
+				i++
+			}
+			switch op {
+			case "=":
+				op = "=="
+			case "!":
+				return nil, fmt.Errorf("unexpected '!' at position %d", i)
+			}
+			out = append(out, alertLexeme{kind: lexOp, text: op})
+		case r == '$' || r == '-' || r == '.' || unicode.IsDigit(r):
+			start := i
+			negative := false
+			for i < len(runes) && (runes[i] == '$' || runes[i] == '-') {
+				negative = negative || runes[i] == '-'
+				i++
+			}
+			numStart := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == ',') {
+				i++
+			}
+			value, err := strconv.ParseFloat(strings.ReplaceAll(string(runes[numStart:i]), ",", ""), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number at position %d", start+1)
+			}
+			if negative {
+				value = -value
+			}
+			// Magnitude suffix, only when it ends the word ("100k", not "100kb")
+			if i < len(runes) && strings.ContainsRune("kKmMbB", runes[i]) && (i+1 == len(runes) || !unicode.IsLetter(runes[i+1])) {
+				switch unicode.ToLower(runes[i]) {
+				case 'k':
+					value *= 1e3
+				case 'm':
+					value *= 1e6
+				case 'b':
+					value *= 1e9
+				}
+				i++
+			}
+			if i < len(runes) && runes[i] == '%' {
+				i++
+			}
+			out = append(out, alertLexeme{kind: lexNumber, text: string(runes[start:i]), num: value})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune("_-.+", runes[i])) {
+				i++
+			}
+			out = append(out, alertLexeme{kind: lexWord, text: string(runes[start:i])})
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", r, i+1)
+		}
+	}
+	return out, nil
+}
+
+// Parser
+
+type alertParser struct {
+	lexemes []alertLexeme
+	pos     int
+	fields  map[string]bool
+}
+
+// parseAlertRule compiles a rule expression
+func parseAlertRule(expression string) (*compiledAlert, error) {
+	return compileAlertExpression(expression, false)
+}
+
+// parseTokenFilter compiles a rule expression used as a token filter, where the
+// condition may be omitted ("any in DeFi"); a nil condition matches the whole scope
+func parseTokenFilter(expression string) (*compiledAlert, error) {
+	return compileAlertExpression(expression, true)
+}
+
+// matches reports whether a token is in scope and satisfies the condition
+func (a *compiledAlert) matches(cur, prev *models.Token) bool {
+	return a.scope.includes(cur) && (a.condition == nil || a.condition.match(cur, prev))
+}
+
+func compileAlertExpression(expression string, optionalCondition bool) (*compiledAlert, error) {
+	lexemes, err := lexAlert(expression)
+	if err != nil {
+		return nil, err
+	}
+	if len(lexemes) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	p := &alertParser{lexemes: lexemes, fields: make(map[string]bool)}
+	scope, err := p.parseScope()
+	if err != nil {
+		return nil, err
+	}
+	var condition alertCondition
+	if !optionalCondition || p.pos < len(p.lexemes) {
+		if condition, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.pos < len(p.lexemes) {
+		return nil, fmt.Errorf("unexpected %q", p.lexemes[p.pos].text)
+	}
+
+	compiled := &compiledAlert{scope: scope, condition: condition}
+	for name := range p.fields {
+		compiled.fields = append(compiled.fields, name)
+	}
+	sort.Strings(compiled.fields)
+	return compiled, nil
+}
+
+func (p *alertParser) peek() *alertLexeme {
+	if p.pos >= len(p.lexemes) {
+		return nil
+	}
+	return &p.lexemes[p.pos]
+}
+
+// peekWord reports whether the next lexeme is one of the given keywords
+func (p *alertParser) peekWord(words ...string) bool {
+	next := p.peek()
+	if next == nil || next.kind != lexWord {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(next.text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *alertParser) acceptWord(words ...string) bool {
+	if p.peekWord(words...) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// lookupField resolves a field name or alias
+func lookupField(name string) (string, alertField, bool) {
+	name = strings.ToLower(name)
+	if alias, ok := alertFieldAliases[name]; ok {
+		name = alias
+	}
+	field, ok := alertFields[name]
+	return name, field, ok
+}
+
+func (p *alertParser) peekField() bool {
+	next := p.peek()
+	if next == nil || next.kind != lexWord {
+		return false
+	}
+	_, _, ok := lookupField(next.text)
+	return ok
+}
+
+func (p *alertParser) parseScope() (alertScope, error) {
+	var scope alertScope
+	switch {
+	case p.acceptWord("any", "all", "every"):
+		p.acceptWord("token", "tokens", "coin", "coins")
+		if p.acceptWord("in") {
+			next := p.peek()
+			if next != nil && next.kind == lexString {
+				scope.category = next.text
+				p.pos++
+			} else {
+				// Unquoted categories run until with/where or the first field name
+				var words []string
+				for next := p.peek(); next != nil && next.kind == lexWord && !p.peekWord("with", "where") && !p.peekField(); next = p.peek() {
+					words = append(words, next.text)
+					p.pos++
+				}
+				scope.category = strings.Join(words, " ")
+			}
+			if scope.category == "" {
+				return scope, fmt.Errorf("expected a category after \"in\"")
+			}
+		}
+	case p.peek().kind == lexWord && !p.peekField() && !p.peekWord("not"):
+		scope.token = p.peek().text
+		p.pos++
+	}
+	p.acceptWord("with", "where")
+	return scope, nil
+}
+
+func (p *alertParser) parseOr() (alertCondition, error) {
+	var terms alertOr
+	for {
+		term, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if !p.acceptWord("or") {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *alertParser) parseAnd() (alertCondition, error) {
+	var terms alertAnd
+	for {
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if !p.acceptWord("and") {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *alertParser) parseUnary() (alertCondition, error) {
+	if p.acceptWord("not") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return alertNot{inner: inner}, nil
+	}
+	if next := p.peek(); next != nil && next.kind == lexLParen {
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if next := p.peek(); next == nil || next.kind != lexRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *alertParser) parseCondition() (alertCondition, error) {
+	next := p.peek()
+	if next == nil {
+		return nil, fmt.Errorf("expected a condition, got end of expression")
+	}
+	if next.kind != lexWord {
+		return nil, fmt.Errorf("expected a field name, got %q", next.text)
+	}
+	name, field, ok := lookupField(next.text)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q (fields: %s)", next.text, strings.Join(alertFieldNames(), ", "))
+	}
+	p.pos++
+	p.fields[name] = true
+
+	switch {
+	case p.acceptWord("crosses", "cross", "crossed"):
+		if field.num == nil {
+			return nil, fmt.Errorf("%s is not numeric and can't cross a level", name)
+		}
+		cross := alertCross{field: field}
+		if p.acceptWord("above", "up") {
+			cross.direction = "above"
+		} else if p.acceptWord("below", "down") {
+			cross.direction = "below"
+		}
+		level, err := p.expectNumber(name)
+		if err != nil {
+			return nil, err
+		}
+		cross.level = level
+		return cross, nil
+
+	case p.peekWord("downgraded", "upgraded"):
+		if name != "grade" {
+			return nil, fmt.Errorf("only grade can be upgraded or downgraded")
+		}
+		change := alertGradeChange{down: p.peekWord("downgraded"), bound: -1}
+		p.pos++
+		if p.acceptWord("below", "above") {
+			bound, err := p.expectGrade()
+			if err != nil {
+				return nil, err
+			}
+			change.bound = bound
+		}
+		return change, nil
+	}
+
+	op := ""
+	if next := p.peek(); next != nil && next.kind == lexOp {
+		op = next.text
+		p.pos++
+	} else if p.acceptWord("above") {
+		op = ">"
+	} else if p.acceptWord("below") {
+		op = "<"
+	} else if p.acceptWord("is") {
+		op = "=="
+		if p.acceptWord("not") {
+			op = "!="
+		}
+	} else {
+		return nil, fmt.Errorf("expected an operator after %s", name)
+	}
+
+	if field.num != nil {
+		value, err := p.expectNumber(name)
+		if err != nil {
+			return nil, err
+		}
+		return alertCompare{field: field, op: op, num: value}, nil
+	}
+
+	if name == "grade" {
+		rank, err := p.expectGrade()
+		if err != nil {
+			return nil, err
+		}
+		return alertCompare{field: field, op: op, str: alertGrades[rank], grade: true}, nil
+	}
+	if op != "==" && op != "!=" {
+		return nil, fmt.Errorf("%s only supports ==, != and is", name)
+	}
+	value := p.peek()
+	if value == nil || (value.kind != lexWord && value.kind != lexString) {
+		return nil, fmt.Errorf("expected a value for %s", name)
+	}
+	p.pos++
+	return alertCompare{field: field, op: op, str: value.text}, nil
+}
+
+func (p *alertParser) expectNumber(field string) (float64, error) {
+	next := p.peek()
+	if next == nil || next.kind != lexNumber {
+		return 0, fmt.Errorf("expected a number for %s", field)
+	}
+	p.pos++
+	return next.num, nil
+}
+
+func (p *alertParser) expectGrade() (int, error) {
+	next := p.peek()
+	if next == nil || (next.kind != lexWord && next.kind != lexString) || gradeRank(next.text) < 0 {
+		return 0, fmt.Errorf("expected a grade (%s)", strings.Join(alertGrades, ", "))
+	}
+	p.pos++
+	return gradeRank(next.text), nil
+}
+
+func alertFieldNames() []string {
+	names := make([]string, 0, len(alertFields))
+	for name := range alertFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
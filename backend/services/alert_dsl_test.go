@@ -0,0 +1,202 @@
+package services
+
+import (
+	"backend/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func alertToken(symbol, category string, price, trustScore, change24h float64, grade string) *models.Token {
+	return &models.Token{
+		ID:         strings.ToLower(symbol),
+		Symbol:     symbol,
+		Category:   category,
+		Price:      price,
+		TrustScore: trustScore,
+		Change24h:  change24h,
+		ScoreBreakdown: models.DetailedScoreBreakdown{
+			Grade: grade,
+		},
+	}
+}
+
+func TestLexAlertNumbers(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{"100k", 100e3},
+		{"$1.5b", 1.5e9},
+		{"2M", 2e6},
+		{"1,250", 1250},
+		{"-5", -5},
+		{"10%", 10},
+		{"0.0001", 0.0001},
+	}
+	for _, tt := range tests {
+		lexemes, err := lexAlert(tt.input)
+		if err != nil {
+			t.Fatalf("lexAlert(%q): %v", tt.input, err)
+		}
+		if len(lexemes) != 1 || lexemes[0].kind != lexNumber || lexemes[0].num != tt.want {
+			t.Errorf("lexAlert(%q) = %+v, want one number %v", tt.input, lexemes, tt.want)
+		}
+	}
+}
+
+func TestLexAlertSuffixNeedsWordEnd(t *testing.T) {
+	lexemes, err := lexAlert("100kb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lexemes) != 2 || lexemes[0].num != 100 || lexemes[1].text != "kb" {
+		t.Errorf("lexAlert(\"100kb\") = %+v, want 100 then word kb", lexemes)
+	}
+}
+
+func TestParseAlertRuleScope(t *testing.T) {
+	tests := []struct {
+		expression string
+		scope      alertScope
+		fields     []string
+	}{
+		{"BTC price crosses 100k", alertScope{token: "BTC"}, []string{"price"}},
+		{"any token in DeFi with trust_score >= 80 and change_24h > 10", alertScope{category: "DeFi"}, []string{"change_24h", "trust_score"}},
+		{"any in \"Layer 1\" where score > 50", alertScope{category: "Layer 1"}, []string{"trust_score"}},
+		{"any tokens in Real World Assets with change_24h > 5", alertScope{category: "Real World Assets"}, []string{"change_24h"}},
+		{"grade downgraded below B", alertScope{}, []string{"grade"}},
+		{"not rank <= 100", alertScope{}, []string{"rank"}},
+	}
+	for _, tt := range tests {
+		compiled, err := parseAlertRule(tt.expression)
+		if err != nil {
+			t.Fatalf("parseAlertRule(%q): %v", tt.expression, err)
+		}
+		if compiled.scope != tt.scope {
+			t.Errorf("parseAlertRule(%q) scope = %+v, want %+v", tt.expression, compiled.scope, tt.scope)
+		}
+		if !reflect.DeepEqual(compiled.fields, tt.fields) {
+			t.Errorf("parseAlertRule(%q) fields = %v, want %v", tt.expression, compiled.fields, tt.fields)
+		}
+	}
+}
+
+func TestAlertRuleMatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		cur, prev  *models.Token
+		want       bool
+	}{
+		{"cross up", "BTC price crosses 100k",
+			alertToken("BTC", "Layer 1", 100500, 90, 2, "A"), alertToken("BTC", "Layer 1", 99000, 90, 2, "A"), true},
+		{"cross down counts without direction", "BTC price crosses 100k",
+			alertToken("BTC", "Layer 1", 99000, 90, 2, "A"), alertToken("BTC", "Layer 1", 100500, 90, 2, "A"), true},
+		{"no cross while staying above", "BTC price crosses 100k",
+			alertToken("BTC", "Layer 1", 101000, 90, 2, "A"), alertToken("BTC", "Layer 1", 100500, 90, 2, "A"), false},
+		{"cross needs a previous snapshot", "BTC price crosses 100k",
+			alertToken("BTC", "Layer 1", 100500, 90, 2, "A"), nil, false},
+		{"cross above ignores a fall", "BTC price crosses above 100k",
+			alertToken("BTC", "Layer 1", 99000, 90, 2, "A"), alertToken("BTC", "Layer 1", 100500, 90, 2, "A"), false},
+		{"cross below", "BTC price crosses below 100k",
+			alertToken("BTC", "Layer 1", 99000, 90, 2, "A"), alertToken("BTC", "Layer 1", 100500, 90, 2, "A"), true},
+		{"other symbol out of scope", "BTC price crosses 100k",
+			alertToken("WBTC", "Wrapped", 100500, 90, 2, "A"), alertToken("WBTC", "Wrapped", 99000, 90, 2, "A"), false},
+
+		{"category rule matches", "any token in DeFi with trust_score >= 80 and change_24h > 10",
+			alertToken("UNI", "DeFi", 10, 80, 12, "A"), nil, true},
+		{"category is case-insensitive", "any token in DeFi with trust_score >= 80 and change_24h > 10",
+			alertToken("AAVE", "defi", 10, 85, 11, "A"), nil, true},
+		{"category rule fails one condition", "any token in DeFi with trust_score >= 80 and change_24h > 10",
+			alertToken("UNI", "DeFi", 10, 79.9, 12, "B"), nil, false},
+		{"category rule out of sector", "any token in DeFi with trust_score >= 80 and change_24h > 10",
+			alertToken("SOL", "Layer 1", 10, 90, 15, "A"), nil, false},
+
+		{"downgrade across bound", "grade downgraded below B",
+			alertToken("ETH", "Layer 1", 1, 70, 0, "C"), alertToken("ETH", "Layer 1", 1, 80, 0, "B"), true},
+		{"downgrade above bound", "grade downgraded below B",
+			alertToken("ETH", "Layer 1", 1, 80, 0, "B"), alertToken("ETH", "Layer 1", 1, 90, 0, "A"), false},
+		{"already below bound", "grade downgraded below B",
+			alertToken("ETH", "Layer 1", 1, 50, 0, "D"), alertToken("ETH", "Layer 1", 1, 70, 0, "C"), false},
+		{"upgrade is not a downgrade", "grade downgraded below B",
+			alertToken("ETH", "Layer 1", 1, 80, 0, "B"), alertToken("ETH", "Layer 1", 1, 70, 0, "C"), false},
+		{"any downgrade", "grade downgraded",
+			alertToken("ETH", "Layer 1", 1, 50, 0, "D"), alertToken("ETH", "Layer 1", 1, 70, 0, "C"), true},
+		{"upgrade above bound", "grade upgraded above B",
+			alertToken("ETH", "Layer 1", 1, 90, 0, "A"), alertToken("ETH", "Layer 1", 1, 80, 0, "B"), true},
+
+		{"grade comparison by order", "grade >= B", alertToken("ETH", "Layer 1", 1, 90, 0, "A"), nil, true},
+		{"string equality", "category is defi", alertToken("UNI", "DeFi", 1, 90, 0, "A"), nil, true},
+		{"string inequality", "category is not defi", alertToken("UNI", "DeFi", 1, 90, 0, "A"), nil, false},
+		{"or with parentheses", "(change_24h < -10 or change_24h > 10) and score > 50",
+			alertToken("X", "", 1, 60, -12, "C"), nil, true},
+		{"not", "not change_24h > 0", alertToken("X", "", 1, 60, -1, "C"), nil, true},
+		{"words as operators", "price below 1", alertToken("X", "", 0.5, 60, 0, "C"), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiled, err := parseAlertRule(tt.expression)
+			if err != nil {
+				t.Fatalf("parseAlertRule(%q): %v", tt.expression, err)
+			}
+			if got := compiled.matches(tt.cur, tt.prev); got != tt.want {
+				t.Errorf("%q matches = %v, want %v", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAlertRuleErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{"", "empty expression"},
+		{"BTC", "expected a condition"},
+		{"BTC foo > 1", "unknown field \"foo\""},
+		{"price > ", "expected a number for price"},
+		{"price 5", "expected an operator after price"},
+		{"symbol crosses 5", "symbol is not numeric"},
+		{"price downgraded", "only grade can be upgraded or downgraded"},
+		{"grade downgraded below Z", "expected a grade"},
+		{"grade >= Q", "expected a grade"},
+		{"category > defi", "category only supports ==, != and is"},
+		{"(price > 1", "missing closing parenthesis"},
+		{"price > 1 2", "unexpected \"2\""},
+		{"any in with price > 1", "expected a category after \"in\""},
+		{"name is \"unterminated", "unterminated string"},
+		{"price ! 1", "unexpected '!'"},
+		{"price > 1 # 2", "unexpected '#'"},
+	}
+	for _, tt := range tests {
+		_, err := parseAlertRule(tt.expression)
+		if err == nil {
+			t.Errorf("parseAlertRule(%q) succeeded, want error containing %q", tt.expression, tt.wantErr)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseAlertRule(%q) error = %q, want it to contain %q", tt.expression, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseTokenFilterScopeOnly(t *testing.T) {
+	filter, err := parseTokenFilter("any in DeFi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.condition != nil {
+		t.Errorf("scope-only filter has a condition")
+	}
+	if !filter.matches(alertToken("UNI", "DeFi", 1, 50, 0, "C"), nil) {
+		t.Errorf("scope-only filter should match a token in scope")
+	}
+	if filter.matches(alertToken("SOL", "Layer 1", 1, 50, 0, "C"), nil) {
+		t.Errorf("scope-only filter should not match a token out of scope")
+	}
+
+	if _, err := parseAlertRule("any in DeFi"); err == nil {
+		t.Errorf("alert rules require a condition")
+	}
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/store"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	alertRuleCollection  = "alerts"
+	alertEventCollection = "alert_events"
+
+	maxAlertRules  = 200
+	maxAlertEvents = 1000
+)
+
+var (
+	// ErrInvalidAlertRule wraps parse and validation errors in a submitted rule
+	ErrInvalidAlertRule = errors.New("invalid alert rule")
+	// ErrAlertRuleNotFound is returned for an unknown rule ID or another owner's rule
+	ErrAlertRuleNotFound = errors.New("alert rule not found")
+	// ErrAlertRuleLimit is returned when the maximum number of rules is stored
+	ErrAlertRuleLimit = errors.New("alert rule limit reached")
+)
+
+// alertProgram is a stored rule compiled for evaluation
+type alertProgram struct {
+	*compiledAlert
+	cooldown time.Duration
+}
+
+// AlertEngine evaluates the stored alert rules against every scored snapshot.
+// A rule fires for a token when the token starts matching (so a condition that
+// stays true fires once), and not again for that token within the rule's cooldown.
+type AlertEngine struct {
+	store           *store.Store
+	defaultCooldown time.Duration
+
+	mu       sync.Mutex
+	rules    []models.AlertRule // creation order
+	programs map[string]*alertProgram
+	events   []models.AlertEvent     // oldest first
+	previous map[string]models.Token // token ID -> token on the previous snapshot
+}
+
+// NewAlertEngine loads stored rules and fired-alert history
+func NewAlertEngine(st *store.Store, cfg *config.Config) *AlertEngine {
+	e := &AlertEngine{
+		store:           st,
+		defaultCooldown: cfg.AlertDefaultCooldown,
+		programs:        make(map[string]*alertProgram),
+		previous:        make(map[string]models.Token),
+	}
+
+	if _, err := st.Load(alertRuleCollection, &e.rules); err != nil {
+		log.Printf("⚠️  Failed to load alert rules, starting empty: %v", err)
+		e.rules = nil
+	}
+	if _, err := st.Load(alertEventCollection, &e.events); err != nil {
+		log.Printf("⚠️  Failed to load alert history, starting empty: %v", err)
+		e.events = nil
+	}
+
+	unowned := 0
+	for _, rule := range e.rules {
+		if rule.Owner == "" {
+			unowned++
+		}
+		program, err := compileAlertRule(rule.Expression, rule.Cooldown)
+		if err != nil {
+			log.Printf("⚠️  Skipping alert rule %s: %v", rule.ID, err)
+			continue
+		}
+		e.programs[rule.ID] = program
+	}
+	if unowned > 0 {
+		log.Printf("⚠️  %d alert rules predate rule owners; they keep firing but no client can list or delete them", unowned)
+	}
+	return e
+}
+
+func compileAlertRule(expression, cooldown string) (*alertProgram, error) {
+	compiled, err := parseAlertRule(expression)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := time.ParseDuration(cooldown)
+	if err != nil || duration < 0 {
+		return nil, fmt.Errorf("invalid cooldown %q", cooldown)
+	}
+	return &alertProgram{compiledAlert: compiled, cooldown: duration}, nil
+}
+
+// Create validates and stores a new rule for an owner
+func (e *AlertEngine) Create(owner string, req models.AlertRuleRequest) (*models.AlertRule, error) {
+	expression := strings.TrimSpace(req.Expression)
+	if len(expression) > models.MaxAlertExpressionLength {
+		return nil, fmt.Errorf("%w: expression is longer than %d characters", ErrInvalidAlertRule, models.MaxAlertExpressionLength)
+	}
+	cooldown := e.defaultCooldown.String()
+	if req.Cooldown != "" {
+		cooldown = req.Cooldown
+	}
+	program, err := compileAlertRule(expression, cooldown)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAlertRule, err)
+	}
+
+	rule := models.AlertRule{
+		ID:         store.NewID(),
+		Owner:      owner,
+		Name:       strings.TrimSpace(req.Name),
+		Expression: expression,
+		Cooldown:   program.cooldown.String(),
+		Enabled:    true,
+		CreatedAt:  time.Now(),
+		LastFired:  make(map[string]time.Time),
+	}
+	if rule.Name == "" {
+		rule.Name = expression
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.rules) >= maxAlertRules {
+		return nil, fmt.Errorf("%w (%d)", ErrAlertRuleLimit, maxAlertRules)
+	}
+	e.rules = append(e.rules, rule)
+	e.programs[rule.ID] = program
+	if err := e.store.Save(alertRuleCollection, e.rules); err != nil {
+		e.rules = e.rules[:len(e.rules)-1]
+		delete(e.programs, rule.ID)
+		return nil, err
+	}
+
+	log.Printf("🔔 Alert rule %s created: %s", rule.ID, rule.Expression)
+	return &rule, nil
+}
+
+// Count returns the number of stored rules, of every owner
+func (e *AlertEngine) Count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.rules)
+}
+
+// List returns an owner's rules in creation order
+func (e *AlertEngine) List(owner string) []models.AlertRule {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rules := make([]models.AlertRule, 0)
+	for _, rule := range e.rules {
+		if rule.Owner == owner {
+			rules = append(rules, copyAlertRule(rule))
+		}
+	}
+	return rules
+}
+
+// Get returns one of an owner's rules
+func (e *AlertEngine) Get(owner, id string) (models.AlertRule, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	i := e.index(owner, id)
+	if i < 0 {
+		return models.AlertRule{}, false
+	}
+	return copyAlertRule(e.rules[i]), true
+}
+
+// Delete removes one of an owner's rules; its fired alerts stay in the history
+func (e *AlertEngine) Delete(owner, id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	i := e.index(owner, id)
+	if i < 0 {
+		return ErrAlertRuleNotFound
+	}
+	remaining := append(e.rules[:i:i], e.rules[i+1:]...)
+	if err := e.store.Save(alertRuleCollection, remaining); err != nil {
+		return err
+	}
+	e.rules = remaining
+	delete(e.programs, id)
+	log.Printf("🔕 Alert rule %s deleted", id)
+	return nil
+}
+
+// History returns up to limit of an owner's fired alerts, newest first, optionally for one rule
+func (e *AlertEngine) History(owner, ruleID string, limit int) []models.AlertEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	result := make([]models.AlertEvent, 0)
+	for i := len(e.events) - 1; i >= 0; i-- {
+		if e.events[i].Owner != owner || (ruleID != "" && e.events[i].RuleID != ruleID) {
+			continue
+		}
+		result = append(result, e.events[i])
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+// Observe evaluates every enabled rule against a new scored snapshot and returns the alerts fired
+func (e *AlertEngine) Observe(tokens []models.Token) []models.AlertEvent {
+	now := time.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var fired []models.AlertEvent
+	rulesChanged := false
+	for i := range e.rules {
+		rule := &e.rules[i]
+		program, ok := e.programs[rule.ID]
+		if !rule.Enabled || !ok {
+			continue
+		}
+
+		wasMatching := make(map[string]bool, len(rule.Matching))
+		for _, id := range rule.Matching {
+			wasMatching[id] = true
+		}
+
+		var matching []string
+		for j := range tokens {
+			token := &tokens[j]
+			if !program.scope.includes(token) {
+				continue
+			}
+			var prev *models.Token
+			if previous, seen := e.previous[token.ID]; seen {
+				prev = &previous
+			}
+			if !program.condition.match(token, prev) {
+				continue
+			}
+			matching = append(matching, token.ID)
+
+			// Still matching since the last snapshot: already alerted
+			if wasMatching[token.ID] {
+				continue
+			}
+			if last, ok := rule.LastFired[token.ID]; ok && now.Sub(last) < program.cooldown {
+				log.Printf("🔕 Alert %s for %s suppressed by cooldown (%s left)", rule.ID, token.Symbol, (program.cooldown - now.Sub(last)).Round(time.Second))
+				continue
+			}
+
+			event := models.AlertEvent{
+				ID:         store.NewID(),
+				RuleID:     rule.ID,
+				Owner:      rule.Owner,
+				RuleName:   rule.Name,
+				Expression: rule.Expression,
+				TokenID:    token.ID,
+				Symbol:     token.Symbol,
+				Name:       token.Name,
+				Values:     program.values(token),
+				FiredAt:    now,
+			}
+			event.Message = alertMessage(rule, token, program)
+			fired = append(fired, event)
+
+			if rule.LastFired == nil {
+				rule.LastFired = make(map[string]time.Time)
+			}
+			rule.LastFired[token.ID] = now
+			rule.LastFiredAt = &now
+			rule.FireCount++
+			log.Printf("🔔 Alert fired: %s", event.Message)
+		}
+
+		// Cooldowns that have run out no longer need their timestamps
+		for id, last := range rule.LastFired {
+			if now.Sub(last) >= program.cooldown {
+				delete(rule.LastFired, id)
+			}
+		}
+
+		if !sameIDs(matching, rule.Matching) {
+			rulesChanged = true
+		}
+		rule.Matching = matching
+	}
+
+	e.previous = make(map[string]models.Token, len(tokens))
+	for _, token := range tokens {
+		e.previous[token.ID] = token
+	}
+
+	if len(fired) > 0 {
+		rulesChanged = true
+		e.events = append(e.events, fired...)
+		if len(e.events) > maxAlertEvents {
+			e.events = e.events[len(e.events)-maxAlertEvents:]
+		}
+		if err := e.store.Save(alertEventCollection, e.events); err != nil {
+			log.Printf("⚠️  Failed to store alert history: %v", err)
+		}
+	}
+	if rulesChanged {
+		if err := e.store.Save(alertRuleCollection, e.rules); err != nil {
+			log.Printf("⚠️  Failed to store alert rules: %v", err)
+		}
+	}
+	return fired
+}
+
+// alertMessage describes a fired alert with the values that triggered it
+func alertMessage(rule *models.AlertRule, token *models.Token, program *alertProgram) string {
+	var parts []string
+	for _, name := range program.fields {
+		field := alertFields[name]
+		if field.num != nil {
+			parts = append(parts, fmt.Sprintf("%s=%.6g", name, field.num(token)))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s=%s", name, field.str(token)))
+		}
+	}
+	return fmt.Sprintf("%s (%s) matched %q [%s]", token.Symbol, token.Name, rule.Name, strings.Join(parts, ", "))
+}
+
+// copyAlertRule detaches a rule from the evaluation state Observe keeps mutating
+// index finds an owner's rule; callers hold e.mu
+func (e *AlertEngine) index(owner, id string) int {
+	for i, rule := range e.rules {
+		if rule.ID == id && rule.Owner == owner {
+			return i
+		}
+	}
+	return -1
+}
+
+func copyAlertRule(rule models.AlertRule) models.AlertRule {
+	rule.Matching = append([]string(nil), rule.Matching...)
+	lastFired := make(map[string]time.Time, len(rule.LastFired))
+	for id, at := range rule.LastFired {
+		lastFired[id] = at
+	}
+	rule.LastFired = lastFired
+	return rule
+}
+
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"backend/indicators"
+	"backend/models"
+	"backend/utils"
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// analysisHistoryDays covers 90d change plus indicator warm-up
+const analysisHistoryDays = 120
+
+// ErrTokenNotFound is returned when a token ID is not in the current snapshot
+var ErrTokenNotFound = errors.New("token not found")
+
+// AnalysisContextBuilder assembles AI analysis input from server-side data only
+type AnalysisContextBuilder struct {
+	market    *MarketData
+	history   *HistoryService
+	analytics *MarketAnalytics
+}
+
+// NewAnalysisContextBuilder creates a new analysis context builder
+func NewAnalysisContextBuilder(market *MarketData, history *HistoryService, analytics *MarketAnalytics) *AnalysisContextBuilder {
+	return &AnalysisContextBuilder{
+		market:    market,
+		history:   history,
+		analytics: analytics,
+	}
+}
+
+// Build looks up the token in the current snapshot and attaches history and
+// indicators. History is best-effort: the context is still usable without it.
+func (b *AnalysisContextBuilder) Build(ctx context.Context, tokenID string) (*models.AnalysisContext, error) {
+	token, found, err := b.market.FindToken(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrTokenNotFound
+	}
+	return b.BuildForToken(ctx, token), nil
+}
+
+// BuildForToken assembles the context for an already resolved token
+func (b *AnalysisContextBuilder) BuildForToken(ctx context.Context, token models.Token) *models.AnalysisContext {
+	// Heavy series are not useful to the model
+	token.PriceHistory = models.PriceHistory{}
+	token.VolumeHistory = models.VolumeHistory{}
+	token.TVLHistory = models.TVLHistory{}
+
+	actx := &models.AnalysisContext{
+		Token:       token,
+		AssembledAt: time.Now(),
+	}
+
+	candles, err := b.history.GetDailyCandles(ctx, token.Symbol, analysisHistoryDays)
+	if err != nil {
+		log.Printf("✗ History unavailable for %s analysis: %v", token.Symbol, err)
+		return actx
+	}
+	if len(candles) < 2 {
+		return actx
+	}
+
+	actx.History = summarizeHistory(candles)
+	actx.Indicators = snapshotIndicators(candles)
+
+	if report, err := b.analytics.TokenBeta(ctx, token.Symbol, DefaultBetaWindow); err == nil {
+		actx.History.BetaBTC = report.Benchmarks[BenchmarkBTC].Beta
+		actx.History.CorrelBTC = report.Benchmarks[BenchmarkBTC].Correlation
+		actx.History.BetaMarket = report.Benchmarks[BenchmarkMarket].Beta
+	} else {
+		log.Printf("✗ Beta unavailable for %s analysis: %v", token.Symbol, err)
+	}
+
+	return actx
+}
+
+// summarizeHistory reduces daily candles to range, period changes and volatility
+func summarizeHistory(candles []models.Candle) *models.HistorySummary {
+	closes := indicators.Closes(candles)
+	last := closes[len(closes)-1]
+
+	summary := &models.HistorySummary{
+		Days: len(candles),
+		High: candles[0].High,
+		Low:  candles[0].Low,
+	}
+	for _, candle := range candles {
+		if candle.High > summary.High {
+			summary.High = candle.High
+		}
+		if candle.Low < summary.Low {
+			summary.Low = candle.Low
+		}
+	}
+
+	summary.Change30d = periodChange(closes, last, 30)
+	summary.Change90d = periodChange(closes, last, 90)
+
+	recent := closes
+	if len(recent) > 31 {
+		recent = recent[len(recent)-31:]
+	}
+	returns := utils.CalculateReturns(recent)
+	summary.Volatility30d = utils.CalculateStdDev(returns, utils.CalculateMean(returns)) * 100
+
+	return summary
+}
+
+// periodChange returns the % change over the last `days` closes, or 0 if too short
+func periodChange(closes []float64, last float64, days int) float64 {
+	if len(closes) <= days {
+		return 0
+	}
+	base := closes[len(closes)-1-days]
+	if base == 0 {
+		return 0
+	}
+	return (last - base) / base * 100
+}
+
+// snapshotIndicators takes the latest value of each standard daily indicator
+func snapshotIndicators(candles []models.Candle) *models.IndicatorSnapshot {
+	closes := indicators.Closes(candles)
+	macd, signal, histogram := indicators.MACD(closes, 12, 26, 9)
+	upper, middle, lower := indicators.BollingerBands(closes, 20, 2)
+	k, d := indicators.Stochastic(candles, 14, 3)
+
+	return &models.IndicatorSnapshot{
+		RSI14:         indicators.Latest(indicators.RSI(closes, 14)),
+		MACD:          indicators.Latest(macd),
+		MACDSignal:    indicators.Latest(signal),
+		MACDHistogram: indicators.Latest(histogram),
+		BBUpper:       indicators.Latest(upper),
+		BBMiddle:      indicators.Latest(middle),
+		BBLower:       indicators.Latest(lower),
+		ATR14:         indicators.Latest(indicators.ATR(candles, 14)),
+		StochK:        indicators.Latest(k),
+		StochD:        indicators.Latest(d),
+	}
+}
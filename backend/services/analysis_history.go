@@ -0,0 +1,178 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/store"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	analysisHistoryCollection = "analyses"
+	maxAnalysesPerToken       = 100
+)
+
+// Fingerprint tolerances: an analysis is reused only while the inputs stay within these
+const (
+	reusePriceTolerance      = 0.02 // relative
+	reuseVolumeTolerance     = 0.30 // relative
+	reuseTrustScoreTolerance = 3.0  // points
+	reuseChange24hTolerance  = 5.0  // percentage points
+	reuseChange7dTolerance   = 7.5  // percentage points
+)
+
+// AnalysisHistory persists every generated analysis and decides when one can be reused
+type AnalysisHistory struct {
+	store  *store.Store
+	maxAge time.Duration
+
+	mu      sync.RWMutex
+	records map[string][]models.AnalysisRecord // token ID -> records, oldest first
+}
+
+// NewAnalysisHistory loads stored analyses; reuse is limited to the analysis cache duration
+func NewAnalysisHistory(st *store.Store, cfg *config.Config) *AnalysisHistory {
+	h := &AnalysisHistory{
+		store:   st,
+		maxAge:  cfg.AnalysisCacheDuration,
+		records: make(map[string][]models.AnalysisRecord),
+	}
+
+	if _, err := st.Load(analysisHistoryCollection, &h.records); err != nil {
+		log.Printf("⚠️  Failed to load analysis history, starting empty: %v", err)
+		h.records = make(map[string][]models.AnalysisRecord)
+	}
+	return h
+}
+
+// Add stores a new analysis record
+func (h *AnalysisHistory) Add(record *models.AnalysisRecord) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	records := append(h.records[record.TokenID], *record)
+	if len(records) > maxAnalysesPerToken {
+		records = records[len(records)-maxAnalysesPerToken:]
+	}
+	h.records[record.TokenID] = records
+
+	return h.store.Save(analysisHistoryCollection, h.records)
+}
+
+// Reusable returns the latest analysis for a token, provider, model, prompt version and language
+// if it is recent enough and was generated from inputs close to the current fingerprint. Matching
+// the provider keeps a template fallback analysis from being served once an LLM is configured.
+func (h *AnalysisHistory) Reusable(tokenID, provider, model, promptVersion, lang string, current models.AnalysisFingerprint) (*models.AnalysisRecord, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	records := h.records[tokenID]
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if time.Since(record.GeneratedAt) > h.maxAge {
+			break // older records are older still
+		}
+		if !sameModel(record, provider, model) || record.PromptVersion != promptVersion || models.NormalizeLang(record.Lang) != lang {
+			continue
+		}
+		if !fingerprintsClose(record.Fingerprint, current) {
+			return nil, false
+		}
+		return &record, true
+	}
+	return nil, false
+}
+
+// sameModel reports whether a record came from the provider and model. A response may name a
+// dated snapshot of the configured model (gpt-4o-2024-08-06 for gpt-4o), but not a variant
+// (gpt-4o for gpt-4, gemini-2.5-pro-exp for gemini-2.5-pro).
+func sameModel(record models.AnalysisRecord, provider, model string) bool {
+	if record.Provider != provider {
+		return false
+	}
+	if record.Model == model {
+		return true
+	}
+	version, ok := strings.CutPrefix(record.Model, model+"-")
+	return ok && version != "" && strings.Trim(version, "0123456789-") == ""
+}
+
+// ForToken returns up to limit records for a token, newest first
+func (h *AnalysisHistory) ForToken(tokenID string, limit int) []models.AnalysisRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	records := h.records[tokenID]
+	result := make([]models.AnalysisRecord, 0, len(records))
+	for i := len(records) - 1; i >= 0; i-- {
+		result = append(result, records[i])
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+// All returns every stored record ordered by generation time
+func (h *AnalysisHistory) All() []models.AnalysisRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var result []models.AnalysisRecord
+	for _, records := range h.records {
+		result = append(result, records...)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GeneratedAt.Before(result[j].GeneratedAt)
+	})
+	return result
+}
+
+// NewAnalysisFingerprint captures the token figures that drive an analysis
+func NewAnalysisFingerprint(token models.Token) models.AnalysisFingerprint {
+	fp := models.AnalysisFingerprint{
+		Price:      token.Price,
+		MarketCap:  token.MarketCap,
+		Volume24h:  token.Volume24h,
+		TrustScore: token.TrustScore,
+		Change24h:  token.Change24h,
+		Change7d:   token.Change7d,
+	}
+
+	// Rounded to 4 significant digits so float noise doesn't change the hash
+	digest := sha256.Sum256([]byte(fmt.Sprintf("%.4g|%.4g|%.4g|%.1f|%.1f|%.1f",
+		fp.Price, fp.MarketCap, fp.Volume24h, fp.TrustScore, fp.Change24h, fp.Change7d)))
+	fp.Hash = hex.EncodeToString(digest[:8])
+	return fp
+}
+
+// fingerprintsClose reports whether two fingerprints are within the reuse tolerances
+func fingerprintsClose(a, b models.AnalysisFingerprint) bool {
+	if a.Hash == b.Hash {
+		return true
+	}
+	return relativeDiff(a.Price, b.Price) <= reusePriceTolerance &&
+		relativeDiff(a.Volume24h, b.Volume24h) <= reuseVolumeTolerance &&
+		math.Abs(a.TrustScore-b.TrustScore) <= reuseTrustScoreTolerance &&
+		math.Abs(a.Change24h-b.Change24h) <= reuseChange24hTolerance &&
+		math.Abs(a.Change7d-b.Change7d) <= reuseChange7dTolerance
+}
+
+// relativeDiff is |a-b| relative to b; 0 when both are zero
+func relativeDiff(a, b float64) float64 {
+	if b == 0 {
+		if a == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return math.Abs(a-b) / math.Abs(b)
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"backend/models"
+	"testing"
+)
+
+func TestSameModel(t *testing.T) {
+	tests := []struct {
+		recordProvider, recordModel string
+		provider, model             string
+		want                        bool
+	}{
+		{"openai", "gpt-4o", "openai", "gpt-4o", true},
+		{"openai", "gpt-4o-2024-08-06", "openai", "gpt-4o", true},
+		{"openai", "gpt-4-0613", "openai", "gpt-4", true},
+		{"openai", "gpt-4o", "openai", "gpt-4", false},
+		{"openai", "gpt-4o-mini", "openai", "gpt-4o", false},
+		{"openai", "gpt-4o-mini-2024-07-18", "openai", "gpt-4o", false},
+		{"gemini", "gemini-2.5-pro-exp", "gemini", "gemini-2.5-pro", false},
+		{"gemini", "gemini-2.5-pro", "gemini", "gemini-2.5-pro-exp", false},
+		{"openai", "gpt-4o-", "openai", "gpt-4o", false},
+		{"gemini", "gpt-4o", "openai", "gpt-4o", false},
+	}
+	for _, tt := range tests {
+		record := models.AnalysisRecord{Provider: tt.recordProvider, Model: tt.recordModel}
+		if got := sameModel(record, tt.provider, tt.model); got != tt.want {
+			t.Errorf("sameModel(%s/%s, %s/%s) = %v, want %v",
+				tt.recordProvider, tt.recordModel, tt.provider, tt.model, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/utils"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// Benchmark names used in beta reports
+const (
+	BenchmarkBTC    = "BTC"
+	BenchmarkETH    = "ETH"
+	BenchmarkMarket = "MARKET"
+)
+
+// DefaultBetaWindow is the window used for Token.BetaToMarket
+const DefaultBetaWindow = 30
+
+// MarketAnalytics computes cross-asset statistics from daily price history
+type MarketAnalytics struct {
+	history *HistoryService
+	market  *MarketData
+	config  *config.Config
+	cache   *cache.Cache
+
+	betasMu sync.RWMutex
+	betas   map[string]float64 // normalized symbol -> market-index beta over DefaultBetaWindow
+}
+
+// NewMarketAnalytics creates a new analytics service
+func NewMarketAnalytics(history *HistoryService, market *MarketData, cfg *config.Config) *MarketAnalytics {
+	return &MarketAnalytics{
+		history: history,
+		market:  market,
+		config:  cfg,
+		cache:   cache.New(30*time.Minute, 60*time.Minute),
+		betas:   make(map[string]float64),
+	}
+}
+
+// returnSeries is a daily return series keyed by bar date
+type returnSeries map[int64]float64
+
+// dailyReturns converts candles into returns keyed by the date of the later bar
+func dailyReturns(candles []models.Candle) returnSeries {
+	series := make(returnSeries, len(candles))
+	for i := 1; i < len(candles); i++ {
+		if candles[i-1].Close == 0 {
+			continue
+		}
+		series[candles[i].Time.Unix()] = (candles[i].Close - candles[i-1].Close) / candles[i-1].Close
+	}
+	return series
+}
+
+// alignReturns intersects several return series on common dates, oldest first
+func alignReturns(series ...returnSeries) ([]int64, [][]float64) {
+	if len(series) == 0 {
+		return nil, nil
+	}
+
+	var dates []int64
+	for date := range series[0] {
+		common := true
+		for _, other := range series[1:] {
+			if _, ok := other[date]; !ok {
+				common = false
+				break
+			}
+		}
+		if common {
+			dates = append(dates, date)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i] < dates[j] })
+
+	aligned := make([][]float64, len(series))
+	for i, s := range series {
+		aligned[i] = make([]float64, len(dates))
+		for j, date := range dates {
+			aligned[i][j] = s[date]
+		}
+	}
+	return dates, aligned
+}
+
+// TokenBeta computes beta and correlation against BTC, ETH and the market index
+func (a *MarketAnalytics) TokenBeta(ctx context.Context, symbol string, window int) (*models.BetaReport, error) {
+	symbol = utils.NormalizeSymbol(symbol)
+	cacheKey := fmt.Sprintf("beta_%s_%d", symbol, window)
+	if cached, found := a.cache.Get(cacheKey); found {
+		return cached.(*models.BetaReport), nil
+	}
+
+	// Twice the window gives a rolling series as long as the window itself
+	days := window*2 + 1
+
+	candles, err := a.history.GetDailyCandles(ctx, symbol, days)
+	if err != nil {
+		return nil, err
+	}
+	assetReturns := dailyReturns(candles)
+
+	benchmarks := map[string]returnSeries{}
+	for _, benchmark := range []string{BenchmarkBTC, BenchmarkETH} {
+		benchCandles, err := a.history.GetDailyCandles(ctx, benchmark, days)
+		if err != nil {
+			log.Printf("✗ %s history unavailable for beta: %v", benchmark, err)
+			continue
+		}
+		benchmarks[benchmark] = dailyReturns(benchCandles)
+	}
+	if index, err := a.MarketIndexReturns(ctx, days); err == nil {
+		benchmarks[BenchmarkMarket] = index
+	} else {
+		log.Printf("✗ Market index unavailable for beta: %v", err)
+	}
+
+	report := &models.BetaReport{
+		Symbol:      symbol,
+		WindowDays:  window,
+		Benchmarks:  make(map[string]models.BenchmarkStats),
+		GeneratedAt: time.Now(),
+	}
+	for name, benchReturns := range benchmarks {
+		dates, aligned := alignReturns(assetReturns, benchReturns)
+		if len(dates) < 2 {
+			continue
+		}
+		report.Benchmarks[name] = rollingBeta(dates, aligned[0], aligned[1], window)
+	}
+
+	if len(report.Benchmarks) == 0 {
+		return nil, fmt.Errorf("no overlapping benchmark history for %s", symbol)
+	}
+
+	a.cache.Set(cacheKey, report, cache.DefaultExpiration)
+	if stats, ok := report.Benchmarks[BenchmarkMarket]; ok && window == DefaultBetaWindow {
+		a.betasMu.Lock()
+		a.betas[symbol] = stats.Beta
+		a.betasMu.Unlock()
+	}
+	return report, nil
+}
+
+// rollingBeta computes the latest-window stats and a rolling series over the aligned returns
+func rollingBeta(dates []int64, asset, benchmark []float64, window int) models.BenchmarkStats {
+	start := len(asset) - window
+	if start < 0 {
+		start = 0
+	}
+
+	stats := models.BenchmarkStats{
+		Beta:         utils.CalculateBeta(asset[start:], benchmark[start:]),
+		Correlation:  utils.CalculateCorrelation(asset[start:], benchmark[start:]),
+		Observations: len(asset) - start,
+	}
+
+	for end := window; end <= len(asset); end++ {
+		a, b := asset[end-window:end], benchmark[end-window:end]
+		stats.Rolling = append(stats.Rolling, models.RollingStat{
+			Date:        time.Unix(dates[end-1], 0).UTC(),
+			Beta:        utils.CalculateBeta(a, b),
+			Correlation: utils.CalculateCorrelation(a, b),
+		})
+	}
+	return stats
+}
+
+// MarketIndexReturns builds daily returns of a cap-weighted index of the largest non-stablecoins
+func (a *MarketAnalytics) MarketIndexReturns(ctx context.Context, days int) (returnSeries, error) {
+	cacheKey := fmt.Sprintf("market_index_%d", days)
+	if cached, found := a.cache.Get(cacheKey); found {
+		return cached.(returnSeries), nil
+	}
+
+	tokens, err := a.market.Tokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	constituents := make([]models.Token, 0, len(tokens))
+	for _, token := range tokens {
+		if token.MarketCap > 0 && token.ScoreBreakdown.Stablecoin == nil {
+			constituents = append(constituents, token)
+		}
+	}
+	sort.Slice(constituents, func(i, j int) bool {
+		return constituents[i].MarketCap > constituents[j].MarketCap
+	})
+	if len(constituents) > a.config.MarketIndexSize {
+		constituents = constituents[:a.config.MarketIndexSize]
+	}
+
+	weighted := make(map[int64]float64)
+	weights := make(map[int64]float64)
+	for _, token := range constituents {
+		candles, err := a.history.GetDailyCandles(ctx, token.Symbol, days)
+		if err != nil {
+			continue
+		}
+		for date, ret := range dailyReturns(candles) {
+			weighted[date] += ret * token.MarketCap
+			weights[date] += token.MarketCap
+		}
+	}
+
+	// Renormalize per date so a constituent missing a day doesn't drag the index
+	index := make(returnSeries, len(weighted))
+	for date, sum := range weighted {
+		index[date] = sum / weights[date]
+	}
+	if len(index) == 0 {
+		return nil, fmt.Errorf("no constituent history for market index")
+	}
+
+	a.cache.Set(cacheKey, index, cache.DefaultExpiration)
+	return index, nil
+}
+
+// CorrelationMatrix computes pairwise return correlations over the window
+func (a *MarketAnalytics) CorrelationMatrix(ctx context.Context, symbols []string, window int) (*models.CorrelationMatrix, error) {
+	result := &models.CorrelationMatrix{
+		WindowDays:  window,
+		GeneratedAt: time.Now(),
+	}
+
+	var series []returnSeries
+	for _, symbol := range symbols {
+		symbol = utils.NormalizeSymbol(symbol)
+		candles, err := a.history.GetDailyCandles(ctx, symbol, window+1)
+		if err != nil {
+			result.Missing = append(result.Missing, symbol)
+			continue
+		}
+		result.Symbols = append(result.Symbols, symbol)
+		series = append(series, dailyReturns(candles))
+	}
+
+	if len(series) < 2 {
+		return nil, fmt.Errorf("need history for at least two symbols")
+	}
+
+	dates, aligned := alignReturns(series...)
+	if len(dates) < 2 {
+		return nil, fmt.Errorf("symbols share no overlapping history")
+	}
+	result.Observations = len(dates)
+
+	result.Matrix = make([][]float64, len(aligned))
+	for i := range aligned {
+		result.Matrix[i] = make([]float64, len(aligned))
+		for j := range aligned {
+			if i == j {
+				result.Matrix[i][j] = 1
+				continue
+			}
+			result.Matrix[i][j] = utils.CalculateCorrelation(aligned[i], aligned[j])
+		}
+	}
+
+	return result, nil
+}
+
+// MarketBeta returns the latest market-index beta computed for a symbol, or 0
+func (a *MarketAnalytics) MarketBeta(symbol string) float64 {
+	a.betasMu.RLock()
+	defer a.betasMu.RUnlock()
+	return a.betas[utils.NormalizeSymbol(symbol)]
+}
+
+// RunBetas computes the market beta of the largest non-stablecoin tokens on every tick;
+// MarketData fills Token.BetaToMarket from them when it builds a snapshot
+func (a *MarketAnalytics) RunBetas(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		a.refreshBetas(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *MarketAnalytics) refreshBetas(ctx context.Context) {
+	tokens, err := a.market.Tokens(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("⚠️  Beta refresh skipped, no snapshot: %v", err)
+		}
+		return
+	}
+
+	candidates := make([]models.Token, 0, len(tokens))
+	for _, token := range tokens {
+		if token.MarketCap > 0 && token.ScoreBreakdown.Stablecoin == nil {
+			candidates = append(candidates, token)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].MarketCap > candidates[j].MarketCap
+	})
+	if len(candidates) > a.config.BetaTokenLimit {
+		candidates = candidates[:a.config.BetaTokenLimit]
+	}
+
+	computed := 0
+	for _, token := range candidates {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := a.TokenBeta(ctx, token.Symbol, DefaultBetaWindow); err == nil {
+			computed++
+		}
+	}
+	log.Printf("📐 Market betas refreshed (%d/%d tokens)", computed, len(candidates))
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/store"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apiKeyCollection = "api_keys"
+
+	maxAPIKeys        = 1000
+	apiKeyPrefix      = "aak_"
+	apiKeyShownChars  = 12 // characters of a key kept as its recognizable prefix
+	streamTokenPrefix = "ast_"
+
+	// bootstrapKeyID identifies the admin key configured through ADMIN_API_KEY
+	bootstrapKeyID = "bootstrap"
+	// anonymousRoleNone disables access without an API key
+	anonymousRoleNone = "none"
+)
+
+var (
+	// ErrInvalidAPIKey wraps validation errors in a submitted API key
+	ErrInvalidAPIKey = errors.New("invalid API key request")
+	// ErrAPIKeyNotFound is returned for an unknown API key ID
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrUnauthenticated is returned for a missing or unknown API key
+	ErrUnauthenticated = errors.New("a valid API key is required")
+)
+
+// AuthService authenticates API keys, stored as SHA-256 hashes, and rate limits each
+// key, or each client IP for anonymous requests
+type AuthService struct {
+	store              *store.Store
+	limiter            *RateLimiter
+	defaultRateLimit   int
+	anonymousRole      string // empty = a key is required
+	anonymousRateLimit int
+	streamTokenTTL     time.Duration
+
+	mu           sync.RWMutex
+	keys         []models.APIKey
+	byHash       map[string]models.APIKey
+	bootstrap    *models.APIKey
+	streamTokens map[string]issuedStreamToken // token hash -> issue
+}
+
+// issuedStreamToken is the principal a stream token authenticates as, until it expires
+type issuedStreamToken struct {
+	principal models.Principal
+	expiresAt time.Time
+}
+
+// NewAuthService loads stored API keys and registers the ADMIN_API_KEY, if configured
+func NewAuthService(st *store.Store, cfg *config.Config) *AuthService {
+	a := &AuthService{
+		store:              st,
+		limiter:            NewRateLimiter(),
+		defaultRateLimit:   cfg.APIKeyRateLimit,
+		anonymousRateLimit: cfg.AnonymousRateLimit,
+		streamTokenTTL:     cfg.StreamTokenTTL,
+		streamTokens:       make(map[string]issuedStreamToken),
+	}
+
+	role := strings.ToLower(strings.TrimSpace(cfg.AnonymousRole))
+	switch {
+	case role == anonymousRoleNone:
+	case models.RoleRank(role) > 0:
+		a.anonymousRole = role
+	default:
+		log.Printf("⚠️  Unknown AUTH_ANONYMOUS_ROLE %q, requiring an API key for every request", cfg.AnonymousRole)
+	}
+
+	if cfg.AdminAPIKey != "" {
+		a.bootstrap = &models.APIKey{
+			ID:        bootstrapKeyID,
+			Name:      "ADMIN_API_KEY",
+			Role:      models.RoleAdmin,
+			Hash:      hashAPIKey(cfg.AdminAPIKey),
+			RateLimit: cfg.APIKeyRateLimit,
+		}
+	}
+
+	if _, err := st.Load(apiKeyCollection, &a.keys); err != nil {
+		log.Printf("⚠️  Failed to load API keys, starting empty: %v", err)
+		a.keys = nil
+	}
+	a.index()
+	return a
+}
+
+// AnonymousRole returns the role of requests without a key, or "" if a key is required
+func (a *AuthService) AnonymousRole() string {
+	return a.anonymousRole
+}
+
+// HasAdmin reports whether any key can manage API keys
+func (a *AuthService) HasAdmin() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.bootstrap != nil {
+		return true
+	}
+	for _, key := range a.keys {
+		if key.Role == models.RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticate resolves a presented API key; an empty key is anonymous, if allowed
+func (a *AuthService) Authenticate(key string) (models.Principal, error) {
+	if key == "" {
+		if a.anonymousRole == "" {
+			return models.Principal{}, ErrUnauthenticated
+		}
+		return models.Principal{Role: a.anonymousRole, RateLimit: a.anonymousRateLimit}, nil
+	}
+
+	a.mu.RLock()
+	stored, ok := a.byHash[hashAPIKey(key)]
+	a.mu.RUnlock()
+	if !ok {
+		return models.Principal{}, ErrUnauthenticated
+	}
+	principal := models.Principal{KeyID: stored.ID, Name: stored.Name, Role: stored.Role, RateLimit: stored.RateLimit}
+	if principal.RateLimit == 0 {
+		principal.RateLimit = a.defaultRateLimit
+	}
+	return principal, nil
+}
+
+// IssueStreamToken creates a short-lived credential for the live stream, authenticating as
+// the given key. It stays valid until it expires, so EventSource can reconnect with it.
+func (a *AuthService) IssueStreamToken(principal models.Principal) (models.StreamToken, error) {
+	if principal.Anonymous() {
+		return models.StreamToken{}, ErrUnauthenticated
+	}
+	plaintext := store.NewSecret(streamTokenPrefix)
+	now := time.Now()
+	expiresAt := now.Add(a.streamTokenTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for hash, issued := range a.streamTokens {
+		if now.After(issued.expiresAt) {
+			delete(a.streamTokens, hash)
+		}
+	}
+	a.streamTokens[hashAPIKey(plaintext)] = issuedStreamToken{principal: principal, expiresAt: expiresAt}
+	return models.StreamToken{Token: plaintext, ExpiresAt: expiresAt}, nil
+}
+
+// AuthenticateStreamToken resolves a stream token issued by IssueStreamToken
+func (a *AuthService) AuthenticateStreamToken(token string) (models.Principal, error) {
+	a.mu.RLock()
+	issued, ok := a.streamTokens[hashAPIKey(token)]
+	a.mu.RUnlock()
+	if !ok || time.Now().After(issued.expiresAt) {
+		return models.Principal{}, ErrUnauthenticated
+	}
+	return issued.principal, nil
+}
+
+// Allow spends one request of the principal's rate limit; anonymous requests are
+// limited per client IP
+func (a *AuthService) Allow(principal models.Principal, clientIP string) RateDecision {
+	caller := "key:" + principal.KeyID
+	if principal.Anonymous() {
+		caller = "ip:" + clientIP
+	}
+	return a.limiter.Allow(caller, principal.RateLimit, time.Now())
+}
+
+// Create validates and stores a new key; the plaintext key is only in the returned value
+func (a *AuthService) Create(req models.APIKeyRequest) (*models.CreatedAPIKey, error) {
+	role := strings.ToLower(strings.TrimSpace(req.Role))
+	if models.RoleRank(role) == 0 {
+		return nil, fmt.Errorf("%w: role must be one of: %s", ErrInvalidAPIKey, strings.Join(models.Roles, ", "))
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidAPIKey)
+	}
+
+	plaintext := store.NewSecret(apiKeyPrefix)
+	key := models.APIKey{
+		ID:        store.NewID(),
+		Name:      name,
+		Role:      role,
+		Prefix:    plaintext[:apiKeyShownChars],
+		Hash:      hashAPIKey(plaintext),
+		RateLimit: req.RateLimit,
+		CreatedAt: time.Now(),
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.keys) >= maxAPIKeys {
+		return nil, fmt.Errorf("%w: at most %d API keys", ErrInvalidAPIKey, maxAPIKeys)
+	}
+	a.keys = append(a.keys, key)
+	if err := a.store.Save(apiKeyCollection, a.keys); err != nil {
+		a.keys = a.keys[:len(a.keys)-1]
+		return nil, err
+	}
+	a.indexLocked()
+
+	log.Printf("🔑 API key %s created (%s, %s)", key.ID, key.Role, key.Name)
+	return &models.CreatedAPIKey{APIKey: key.Redacted(), Key: plaintext}, nil
+}
+
+// List returns the stored keys without their hashes
+func (a *AuthService) List() []models.APIKey {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	keys := make([]models.APIKey, len(a.keys))
+	for i, key := range a.keys {
+		keys[i] = key.Redacted()
+	}
+	return keys
+}
+
+// Delete revokes a key
+func (a *AuthService) Delete(id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, key := range a.keys {
+		if key.ID != id {
+			continue
+		}
+		remaining := append(append([]models.APIKey(nil), a.keys[:i]...), a.keys[i+1:]...)
+		if err := a.store.Save(apiKeyCollection, remaining); err != nil {
+			return err
+		}
+		a.keys = remaining
+		a.indexLocked()
+		for hash, issued := range a.streamTokens {
+			if issued.principal.KeyID == id {
+				delete(a.streamTokens, hash)
+			}
+		}
+		log.Printf("🔑 API key %s revoked (%s)", key.ID, key.Name)
+		return nil
+	}
+	return ErrAPIKeyNotFound
+}
+
+func (a *AuthService) index() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.indexLocked()
+}
+
+func (a *AuthService) indexLocked() {
+	a.byHash = make(map[string]models.APIKey, len(a.keys)+1)
+	for _, key := range a.keys {
+		a.byHash[key.Hash] = key
+	}
+	if a.bootstrap != nil {
+		a.byHash[a.bootstrap.Hash] = *a.bootstrap
+	}
+}
+
+// hashAPIKey is the stored form of a key or stream token. Both are random, so an unsalted hash suffices.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"backend/models"
+	"backend/store"
+	"context"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const backtestCollection = "backtests"
+
+// Backtester replays stored AI recommendations against subsequent daily prices
+type Backtester struct {
+	analyses *AnalysisHistory
+	history  *HistoryService
+	store    *store.Store
+
+	mu      sync.RWMutex
+	results map[string]models.BacktestResult // analysis ID -> result
+}
+
+// NewBacktester creates a backtester and loads previously stored results
+func NewBacktester(analyses *AnalysisHistory, history *HistoryService, st *store.Store) *Backtester {
+	b := &Backtester{
+		analyses: analyses,
+		history:  history,
+		store:    st,
+		results:  make(map[string]models.BacktestResult),
+	}
+
+	if _, err := st.Load(backtestCollection, &b.results); err != nil {
+		log.Printf("⚠️  Failed to load backtest results, starting empty: %v", err)
+		b.results = make(map[string]models.BacktestResult)
+	}
+	return b
+}
+
+// Run evaluates immediately and then on every interval until ctx is cancelled
+func (b *Backtester) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if updated, err := b.Evaluate(ctx); err != nil {
+			log.Printf("⚠️  Backtest evaluation failed: %v", err)
+		} else if updated > 0 {
+			log.Printf("✅ Backtest evaluated %d recommendations", updated)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate parses new analyses and replays every incomplete result, returning how many were updated
+func (b *Backtester) Evaluate(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	// Collect incomplete results grouped by symbol so each history is fetched once
+	pending := make(map[string][]models.BacktestResult)
+	oldest := make(map[string]time.Time)
+
+	b.mu.RLock()
+	for _, record := range b.analyses.All() {
+		result, exists := b.results[record.ID]
+		if exists && result.Complete {
+			continue
+		}
+		if !exists {
+			result = newBacktestResult(record)
+		}
+		pending[result.Symbol] = append(pending[result.Symbol], result)
+		if first, ok := oldest[result.Symbol]; !ok || result.GeneratedAt.Before(first) {
+			oldest[result.Symbol] = result.GeneratedAt
+		}
+	}
+	b.mu.RUnlock()
+
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	var updated []models.BacktestResult
+	for symbol, results := range pending {
+		days := int(now.Sub(oldest[symbol]).Hours()/24) + 2
+		if days > maxHistoryDays {
+			days = maxHistoryDays
+		}
+
+		candles, err := b.history.GetDailyCandles(ctx, symbol, days)
+		if err != nil {
+			log.Printf("✗ Backtest history unavailable for %s: %v", symbol, err)
+			continue
+		}
+
+		for _, result := range results {
+			replayRecommendation(&result, candles, now)
+			updated = append(updated, result)
+		}
+	}
+
+	b.mu.Lock()
+	for _, result := range updated {
+		b.results[result.AnalysisID] = result
+	}
+	err := b.store.Save(backtestCollection, b.results)
+	b.mu.Unlock()
+
+	return len(updated), err
+}
+
+// newBacktestResult parses the plan of a stored analysis
+func newBacktestResult(record models.AnalysisRecord) models.BacktestResult {
+	result := models.BacktestResult{
+		AnalysisID:     record.ID,
+		TokenID:        record.TokenID,
+		Symbol:         record.Symbol,
+		Model:          record.Model,
+		PromptVersion:  record.PromptVersion,
+		GeneratedAt:    record.GeneratedAt,
+		ReferencePrice: record.Fingerprint.Price,
+		Status:         models.BacktestPending,
+	}
+
+	plan, err := ParseRecommendation(record.Analysis)
+	result.Plan = plan
+	if err != nil {
+		result.ParseError = err.Error()
+		result.Status = models.BacktestUnparseable
+		result.Complete = true
+	}
+	return result
+}
+
+// replayRecommendation walks the daily candles after generation and updates the outcome.
+// Within one candle the stop is checked before targets, so ambiguous days count as losses.
+func replayRecommendation(result *models.BacktestResult, candles []models.Candle, now time.Time) {
+	result.EvaluatedAt = now
+	if result.Status == models.BacktestUnparseable || result.ReferencePrice <= 0 {
+		return
+	}
+
+	plan := result.Plan
+	horizonEnd := result.GeneratedAt.AddDate(0, 0, plan.HorizonDays)
+	horizonOver := now.After(horizonEnd)
+
+	// Reset and replay from scratch: candles may have been revised since the last run
+	result.Status = models.BacktestPending
+	result.EntryPrice, result.EntryAt = 0, nil
+	result.TargetsHit, result.FirstTPAt, result.StopHitAt = 0, nil, nil
+	result.MaxAdverse, result.MaxFavor, result.ReturnPct = 0, 0, 0
+
+	// The generation day's candle includes prices from before the analysis
+	start := result.GeneratedAt.Truncate(24 * time.Hour)
+	var window []models.Candle
+	for _, candle := range candles {
+		if candle.Time.After(start) && !candle.Time.After(horizonEnd) {
+			window = append(window, candle)
+		}
+	}
+
+	lastClose := result.ReferencePrice
+	if len(window) > 0 {
+		lastClose = window[len(window)-1].Close
+	}
+
+	if plan.Direction == "none" {
+		result.Status = models.BacktestNoTrade
+		result.ReturnPct = (lastClose - result.ReferencePrice) / result.ReferencePrice * 100
+		result.Complete = horizonOver
+		return
+	}
+
+	sign := 1.0 // +1 long, -1 short
+	if plan.Direction == "short" {
+		sign = -1
+	}
+	move := func(price float64) float64 {
+		return sign * (price - result.EntryPrice) / result.EntryPrice * 100
+	}
+
+	// Market orders fill at the reference price; a zone entry waits for price to reach it
+	if plan.Action != "BUY ZONE" || plan.EntryHigh == 0 || result.ReferencePrice <= plan.EntryHigh {
+		result.EntryPrice = result.ReferencePrice
+		generated := result.GeneratedAt
+		result.EntryAt = &generated
+	}
+
+	for i := range window {
+		candle := window[i]
+
+		if result.EntryPrice == 0 {
+			if candle.Low > plan.EntryHigh {
+				continue
+			}
+			result.EntryPrice = math.Min(candle.Open, plan.EntryHigh)
+			result.EntryAt = &window[i].Time
+		}
+
+		adverse, favorable := candle.Low, candle.High
+		if sign < 0 {
+			adverse, favorable = candle.High, candle.Low
+		}
+		result.MaxAdverse = math.Min(result.MaxAdverse, move(adverse))
+		result.MaxFavor = math.Max(result.MaxFavor, move(favorable))
+
+		if sign*(adverse-plan.StopLoss) <= 0 {
+			result.Status = models.BacktestStopHit
+			result.StopHitAt = &window[i].Time
+			result.ReturnPct = move(plan.StopLoss)
+			result.Complete = true
+			return
+		}
+
+		for result.TargetsHit < len(plan.Targets) && sign*(favorable-plan.Targets[result.TargetsHit]) >= 0 {
+			result.TargetsHit++
+			if result.TargetsHit == 1 {
+				result.FirstTPAt = &window[i].Time
+			}
+		}
+		if result.TargetsHit == len(plan.Targets) {
+			// Equal-size scale-out across all targets
+			var total float64
+			for _, target := range plan.Targets {
+				total += move(target)
+			}
+			result.Status = models.BacktestTargetHit
+			result.ReturnPct = total / float64(len(plan.Targets))
+			result.Complete = true
+			return
+		}
+	}
+
+	if result.EntryPrice == 0 {
+		if horizonOver {
+			result.Status = models.BacktestNotFilled
+			result.Complete = true
+		}
+		return
+	}
+
+	result.ReturnPct = move(lastClose)
+	if horizonOver {
+		result.Status = models.BacktestExpired
+		result.Complete = true
+	}
+}
+
+// Performance aggregates results per model and prompt version, optionally filtered
+func (b *Backtester) Performance(model, promptVersion string) []models.PerformanceStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	type groupKey struct{ model, prompt string }
+	type accumulator struct {
+		stats                               models.PerformanceStats
+		tp1, fullTP, stops, notFilled       int
+		returns, adverse                    float64
+		actionCount, actionTP1, actionStops map[string]int
+		actionReturns                       map[string]float64
+	}
+
+	groups := make(map[groupKey]*accumulator)
+	for _, result := range b.results {
+		if model != "" && result.Model != model {
+			continue
+		}
+		if promptVersion != "" && result.PromptVersion != promptVersion {
+			continue
+		}
+
+		key := groupKey{result.Model, result.PromptVersion}
+		acc, ok := groups[key]
+		if !ok {
+			acc = &accumulator{
+				stats:         models.PerformanceStats{Model: result.Model, PromptVersion: result.PromptVersion},
+				actionCount:   make(map[string]int),
+				actionTP1:     make(map[string]int),
+				actionStops:   make(map[string]int),
+				actionReturns: make(map[string]float64),
+			}
+			groups[key] = acc
+		}
+
+		acc.stats.Analyses++
+		if result.Status == models.BacktestNotFilled {
+			acc.notFilled++
+		}
+		if result.EntryPrice == 0 {
+			continue
+		}
+		acc.stats.Trades++
+
+		if !result.Complete {
+			continue
+		}
+		acc.stats.Resolved++
+		acc.returns += result.ReturnPct
+		acc.adverse += result.MaxAdverse
+
+		action := result.Plan.Action
+		acc.actionCount[action]++
+		acc.actionReturns[action] += result.ReturnPct
+		if result.TargetsHit > 0 {
+			acc.tp1++
+			acc.actionTP1[action]++
+		}
+		switch result.Status {
+		case models.BacktestTargetHit:
+			acc.fullTP++
+		case models.BacktestStopHit:
+			acc.stops++
+			acc.actionStops[action]++
+		}
+	}
+
+	report := make([]models.PerformanceStats, 0, len(groups))
+	for _, acc := range groups {
+		stats := acc.stats
+		stats.ByAction = make(map[string]models.ActionStat)
+		if filledOrNot := stats.Trades + acc.notFilled; filledOrNot > 0 {
+			stats.FillRate = float64(stats.Trades) / float64(filledOrNot)
+		}
+		if stats.Resolved > 0 {
+			resolved := float64(stats.Resolved)
+			stats.TP1HitRate = float64(acc.tp1) / resolved
+			stats.TargetHitRate = float64(acc.fullTP) / resolved
+			stats.StopHitRate = float64(acc.stops) / resolved
+			stats.AvgReturnPct = acc.returns / resolved
+			stats.AvgMaxAdverse = acc.adverse / resolved
+		}
+		for action, count := range acc.actionCount {
+			stats.ByAction[action] = models.ActionStat{
+				Count:        count,
+				TP1HitRate:   float64(acc.actionTP1[action]) / float64(count),
+				StopHitRate:  float64(acc.actionStops[action]) / float64(count),
+				AvgReturnPct: acc.actionReturns[action] / float64(count),
+			}
+		}
+		report = append(report, stats)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Model != report[j].Model {
+			return report[i].Model < report[j].Model
+		}
+		return report[i].PromptVersion < report[j].PromptVersion
+	})
+	return report
+}
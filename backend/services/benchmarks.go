@@ -0,0 +1,185 @@
+package services
+
+import (
+	"backend/models"
+	"backend/utils"
+	"math"
+	"sort"
+	"time"
+)
+
+// Sector benchmarking parameters
+const (
+	minBenchmarkSize      = 3    // fewer constituents than this isn't a meaningful sector
+	relativeStrengthBlend = 0.10 // share of the total score taken by relative strength
+	topConstituentCount   = 10
+)
+
+// buildCategoryBenchmarks computes per-category medians from the raw (unscored) token list
+func buildCategoryBenchmarks(tokens []models.Token) map[string]*models.CategoryBenchmark {
+	type samples struct {
+		volumeRatios    []float64
+		tvlRatios       []float64
+		liquidityRatios []float64
+		returns7d       []float64
+		returns30d      []float64
+		marketCap       float64
+		count           int
+	}
+
+	byCategory := make(map[string]*samples)
+	for i := range tokens {
+		token := &tokens[i]
+		if token.Category == "" || token.MarketCap <= 0 {
+			continue
+		}
+
+		sample, exists := byCategory[token.Category]
+		if !exists {
+			sample = &samples{}
+			byCategory[token.Category] = sample
+		}
+
+		sample.count++
+		sample.marketCap += token.MarketCap
+		// A zero change means the source had no value for the period; it would pull the median to 0
+		if token.Change7d != 0 {
+			sample.returns7d = append(sample.returns7d, token.Change7d)
+		}
+		if token.Change30d != 0 {
+			sample.returns30d = append(sample.returns30d, token.Change30d)
+		}
+		if token.Volume24h > 0 {
+			sample.volumeRatios = append(sample.volumeRatios, token.Volume24h/token.MarketCap)
+		}
+		if token.TVL > 0 {
+			sample.tvlRatios = append(sample.tvlRatios, token.TVL/token.MarketCap)
+		}
+		if token.Liquidity > 0 {
+			sample.liquidityRatios = append(sample.liquidityRatios, token.Liquidity/token.MarketCap)
+		}
+	}
+
+	now := time.Now()
+	benchmarks := make(map[string]*models.CategoryBenchmark)
+	for category, sample := range byCategory {
+		if sample.count < minBenchmarkSize {
+			continue
+		}
+
+		benchmarks[category] = &models.CategoryBenchmark{
+			Category:             category,
+			TokenCount:           sample.count,
+			TotalMarketCap:       sample.marketCap,
+			MedianVolumeToMcap:   utils.CalculateMedian(sample.volumeRatios),
+			MedianTVLToMcap:      utils.CalculateMedian(sample.tvlRatios),
+			TVLSampleSize:        len(sample.tvlRatios),
+			MedianLiquidityRatio: utils.CalculateMedian(sample.liquidityRatios),
+			LiquiditySampleSize:  len(sample.liquidityRatios),
+			MedianReturn7d:       utils.CalculateMedian(sample.returns7d),
+			Return7dSampleSize:   len(sample.returns7d),
+			MedianReturn30d:      utils.CalculateMedian(sample.returns30d),
+			Return30dSampleSize:  len(sample.returns30d),
+			UpdatedAt:            now,
+		}
+	}
+
+	return benchmarks
+}
+
+// calculateRelativeStrength compares a token to its sector medians (50 = in line with sector)
+func (s *EnhancedScorer) calculateRelativeStrength(token *models.Token, bench *models.CategoryBenchmark, details *models.ScoreDetails) float64 {
+	details.Sector = bench.Category
+
+	var components []float64
+
+	if bench.MedianVolumeToMcap > 0 && token.Volume24h > 0 && token.MarketCap > 0 {
+		details.SectorVolumeRatio = (token.Volume24h / token.MarketCap) / bench.MedianVolumeToMcap
+		components = append(components, ratioToStrength(details.SectorVolumeRatio))
+	}
+	if bench.TVLSampleSize >= minBenchmarkSize && token.TVL > 0 && token.MarketCap > 0 {
+		details.SectorTVLRatio = (token.TVL / token.MarketCap) / bench.MedianTVLToMcap
+		components = append(components, ratioToStrength(details.SectorTVLRatio))
+	}
+	if bench.LiquiditySampleSize >= minBenchmarkSize && token.Liquidity > 0 && token.MarketCap > 0 {
+		details.SectorLiquidityRatio = (token.Liquidity / token.MarketCap) / bench.MedianLiquidityRatio
+		components = append(components, ratioToStrength(details.SectorLiquidityRatio))
+	}
+
+	// Outperformance: ±20pp over 7d / ±40pp over 30d spans the full range; only compared
+	// when the token has a return for the period and enough of its sector does too
+	if bench.Return7dSampleSize >= minBenchmarkSize && token.Change7d != 0 {
+		details.SectorReturn7dDelta = token.Change7d - bench.MedianReturn7d
+		components = append(components, clampUnit(0.5+details.SectorReturn7dDelta/40))
+	}
+	if bench.Return30dSampleSize >= minBenchmarkSize && token.Change30d != 0 {
+		details.SectorReturn30dDelta = token.Change30d - bench.MedianReturn30d
+		components = append(components, clampUnit(0.5+details.SectorReturn30dDelta/80))
+	}
+	if len(components) == 0 {
+		return 50 // nothing to compare: in line with the sector
+	}
+
+	return utils.CalculateMean(components) * 100
+}
+
+// ratioToStrength maps a token/sector-median ratio onto 0-1 (1x = 0.5, 4x = 1.0, 0.25x = 0)
+func ratioToStrength(ratio float64) float64 {
+	if ratio <= 0 {
+		return 0
+	}
+	return clampUnit(0.5 + 0.25*math.Log2(ratio))
+}
+
+func clampUnit(value float64) float64 {
+	return math.Min(math.Max(value, 0), 1)
+}
+
+// finalizeBenchmarks attaches trust-score aggregates and top constituents once tokens are scored
+func finalizeBenchmarks(benchmarks map[string]*models.CategoryBenchmark, tokens []models.Token) []models.CategoryBenchmark {
+	members := make(map[string][]*models.Token)
+	for i := range tokens {
+		if _, ok := benchmarks[tokens[i].Category]; ok {
+			members[tokens[i].Category] = append(members[tokens[i].Category], &tokens[i])
+		}
+	}
+
+	result := make([]models.CategoryBenchmark, 0, len(benchmarks))
+	for category, bench := range benchmarks {
+		constituents := members[category]
+		sort.Slice(constituents, func(i, j int) bool {
+			return constituents[i].TrustScore > constituents[j].TrustScore
+		})
+
+		var scoreSum float64
+		for _, token := range constituents {
+			scoreSum += token.TrustScore
+		}
+		if len(constituents) > 0 {
+			bench.AverageTrustScore = scoreSum / float64(len(constituents))
+		}
+
+		bench.TopConstituents = make([]models.CategoryConstituent, 0, topConstituentCount)
+		for _, token := range constituents {
+			if len(bench.TopConstituents) >= topConstituentCount {
+				break
+			}
+			bench.TopConstituents = append(bench.TopConstituents, models.CategoryConstituent{
+				ID:                    token.ID,
+				Symbol:                token.Symbol,
+				Name:                  token.Name,
+				MarketCap:             token.MarketCap,
+				TrustScore:            token.TrustScore,
+				RelativeStrengthScore: token.ScoreBreakdown.RelativeStrengthScore,
+			})
+		}
+
+		result = append(result, *bench)
+	}
+
+	// Largest sectors first
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TotalMarketCap > result[j].TotalMarketCap
+	})
+	return result
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"backend/models"
+	"backend/prompts"
+	"backend/store"
+	"context"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	briefCollection      = "briefs"
+	briefScoreCollection = "brief_scores"
+
+	briefUniverseSize = 250 // movers are picked from the top tokens by market cap
+	briefMoverCount   = 5
+	briefSectorCount  = 8
+	maxStoredBriefs   = 90
+
+	briefRetryBase = 5 * time.Minute // first retry after a failed generation, doubled per failure
+	briefRetryMax  = time.Hour
+)
+
+// briefScoreSnapshot is the trust score of every token when the last brief was generated
+type briefScoreSnapshot struct {
+	TakenAt time.Time          `json:"taken_at"`
+	Scores  map[string]float64 `json:"scores"` // token ID -> trust score
+}
+
+// BriefService assembles the daily market snapshot and has the AI write a brief from it
+type BriefService struct {
+	ai         *AIService
+	aggregator *Aggregator
+	market     *MarketData
+	scorer     *EnhancedScorer
+	store      *store.Store
+
+	mu     sync.RWMutex
+	briefs map[string]models.BriefRecord // date -> brief
+	scores briefScoreSnapshot
+}
+
+// NewBriefService loads stored briefs and the last score snapshot
+func NewBriefService(ai *AIService, aggregator *Aggregator, market *MarketData, scorer *EnhancedScorer, st *store.Store) *BriefService {
+	s := &BriefService{
+		ai:         ai,
+		aggregator: aggregator,
+		market:     market,
+		scorer:     scorer,
+		store:      st,
+		briefs:     make(map[string]models.BriefRecord),
+	}
+
+	if _, err := st.Load(briefCollection, &s.briefs); err != nil {
+		log.Printf("⚠️  Failed to load market briefs, starting empty: %v", err)
+		s.briefs = make(map[string]models.BriefRecord)
+	}
+	if _, err := st.Load(briefScoreCollection, &s.scores); err != nil {
+		log.Printf("⚠️  Failed to load brief score snapshot: %v", err)
+		s.scores = briefScoreSnapshot{}
+	}
+	return s
+}
+
+// Run generates today's brief once hourUTC has passed, then once a day until ctx is cancelled.
+// A failed generation (budget, provider outage) is retried with backoff until today's brief exists.
+func (s *BriefService) Run(ctx context.Context, hourUTC int) {
+	retry := briefRetryBase
+	for {
+		now := time.Now().UTC()
+		today := now.Format(models.BriefDateLayout)
+		failed := false
+		if _, exists := s.ForDate(today); !exists && now.Hour() >= hourUTC {
+			if _, err := s.Generate(ctx); err != nil {
+				log.Printf("⚠️  Market brief generation failed, retrying in %v: %v", retry, err)
+				failed = true
+			}
+		}
+
+		next := time.Date(now.Year(), now.Month(), now.Day(), hourUTC, 0, 0, 0, time.UTC)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		if failed {
+			if retryAt := now.Add(retry); retryAt.Before(next) {
+				next = retryAt
+			}
+			retry = min(retry*2, briefRetryMax)
+		} else {
+			retry = briefRetryBase
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Generate writes and stores the brief for the current UTC day, replacing any existing one
+func (s *BriefService) Generate(ctx context.Context) (*models.BriefRecord, error) {
+	tmpl, err := s.ai.ResolvePrompt(prompts.KindBrief, "")
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.market.Tokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := s.assembleInputs(ctx, tokens)
+	record, err := s.ai.GenerateBrief(ctx, inputs, tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.briefs[record.Date] = *record
+	s.pruneLocked()
+	if err := s.store.Save(briefCollection, s.briefs); err != nil {
+		return nil, err
+	}
+
+	// Next brief measures score changes against today's scores
+	s.scores = newBriefScoreSnapshot(tokens)
+	if err := s.store.Save(briefScoreCollection, s.scores); err != nil {
+		log.Printf("⚠️  Failed to save brief score snapshot: %v", err)
+	}
+	return record, nil
+}
+
+// Latest returns the most recent brief
+func (s *BriefService) Latest() (*models.BriefRecord, bool) {
+	recent := s.Recent(1)
+	if len(recent) == 0 {
+		return nil, false
+	}
+	return &recent[0], true
+}
+
+// ForDate returns the brief for a YYYY-MM-DD day
+func (s *BriefService) ForDate(date string) (*models.BriefRecord, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.briefs[date]
+	if !ok {
+		return nil, false
+	}
+	return &record, true
+}
+
+// Recent returns up to limit briefs, newest first
+func (s *BriefService) Recent(limit int) []models.BriefRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.BriefRecord, 0, len(s.briefs))
+	for _, record := range s.briefs {
+		result = append(result, record)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date > result[j].Date })
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// assembleInputs builds the brief snapshot; global stats are best effort
+func (s *BriefService) assembleInputs(ctx context.Context, tokens []models.Token) *models.BriefInputs {
+	inputs := &models.BriefInputs{
+		Date: time.Now().UTC().Format(models.BriefDateLayout),
+	}
+
+	if stats, err := s.aggregator.FetchGlobalMarketStats(ctx); err != nil {
+		log.Printf("⚠️  Market brief: global stats unavailable: %v", err)
+	} else {
+		inputs.Market = stats
+	}
+
+	// Gainers and losers among the largest tokens only, so illiquid outliers don't dominate
+	universe := make([]models.Token, len(tokens))
+	copy(universe, tokens)
+	sort.Slice(universe, func(i, j int) bool { return universe[i].MarketCap > universe[j].MarketCap })
+	if len(universe) > briefUniverseSize {
+		universe = universe[:briefUniverseSize]
+	}
+	sort.SliceStable(universe, func(i, j int) bool { return universe[i].Change24h > universe[j].Change24h })
+	for i := 0; i < len(universe) && len(inputs.Gainers) < briefMoverCount; i++ {
+		if universe[i].Change24h <= 0 {
+			break
+		}
+		inputs.Gainers = append(inputs.Gainers, newBriefMover(universe[i], 0))
+	}
+	for i := len(universe) - 1; i >= 0 && len(inputs.Losers) < briefMoverCount; i-- {
+		if universe[i].Change24h >= 0 {
+			break
+		}
+		inputs.Losers = append(inputs.Losers, newBriefMover(universe[i], 0))
+	}
+
+	s.mu.RLock()
+	previous := s.scores
+	s.mu.RUnlock()
+	if len(previous.Scores) > 0 {
+		since := previous.TakenAt
+		inputs.ScoresSince = &since
+		inputs.ScoreMovers = scoreMovers(tokens, previous.Scores)
+	}
+
+	benchmarks := s.scorer.CategoryBenchmarks()
+	sorted := make([]models.CategoryBenchmark, len(benchmarks))
+	copy(sorted, benchmarks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].TotalMarketCap > sorted[j].TotalMarketCap })
+	for i := 0; i < len(sorted) && i < briefSectorCount; i++ {
+		b := sorted[i]
+		inputs.Sectors = append(inputs.Sectors, models.BriefSector{
+			Category:          b.Category,
+			TokenCount:        b.TokenCount,
+			TotalMarketCap:    b.TotalMarketCap,
+			MedianReturn7d:    b.MedianReturn7d,
+			MedianReturn30d:   b.MedianReturn30d,
+			AverageTrustScore: b.AverageTrustScore,
+		})
+	}
+	return inputs
+}
+
+// pruneLocked drops the oldest briefs beyond the retention limit; callers hold mu
+func (s *BriefService) pruneLocked() {
+	if len(s.briefs) <= maxStoredBriefs {
+		return
+	}
+	dates := make([]string, 0, len(s.briefs))
+	for date := range s.briefs {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	for _, date := range dates[:len(dates)-maxStoredBriefs] {
+		delete(s.briefs, date)
+	}
+}
+
+// scoreMovers returns the tokens whose trust score changed most since the snapshot
+func scoreMovers(tokens []models.Token, previous map[string]float64) []models.BriefMover {
+	var movers []models.BriefMover
+	for _, token := range tokens {
+		before, ok := previous[token.ID]
+		if !ok {
+			continue
+		}
+		if change := token.TrustScore - before; change != 0 {
+			movers = append(movers, newBriefMover(token, change))
+		}
+	}
+	sort.Slice(movers, func(i, j int) bool {
+		return math.Abs(movers[i].ScoreChange) > math.Abs(movers[j].ScoreChange)
+	})
+	if len(movers) > briefMoverCount {
+		movers = movers[:briefMoverCount]
+	}
+	return movers
+}
+
+func newBriefMover(token models.Token, scoreChange float64) models.BriefMover {
+	return models.BriefMover{
+		ID:          token.ID,
+		Symbol:      token.Symbol,
+		Name:        token.Name,
+		Price:       token.Price,
+		Change24h:   token.Change24h,
+		Change7d:    token.Change7d,
+		TrustScore:  token.TrustScore,
+		ScoreChange: scoreChange,
+		Grade:       token.ScoreBreakdown.Grade,
+	}
+}
+
+func newBriefScoreSnapshot(tokens []models.Token) briefScoreSnapshot {
+	snapshot := briefScoreSnapshot{TakenAt: time.Now(), Scores: make(map[string]float64, len(tokens))}
+	for _, token := range tokens {
+		snapshot.Scores[token.ID] = token.TrustScore
+	}
+	return snapshot
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"backend/cache"
+	"backend/config"
+	"backend/models"
+	"backend/prompts"
+	"backend/store"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxChatHistoryTurns caps how many earlier messages are replayed to the model
+const maxChatHistoryTurns = 20
+
+// Chat errors surfaced to the handler
+var (
+	ErrChatSessionNotFound = errors.New("chat session not found or expired")
+	ErrChatTokenRequired   = errors.New("token_id is required to start a chat session")
+	ErrChatTokenMismatch   = errors.New("session is about a different token")
+	ErrChatBudgetExhausted = errors.New("chat session token budget exhausted; start a new session to continue")
+)
+
+// chatSession guards one cached session; turns on the same session are serialized
+type chatSession struct {
+	mu   sync.Mutex
+	data models.ChatSession
+}
+
+// ChatService runs follow-up conversations about a token. Sessions live in the
+// cache layer, which expires them after the configured idle time.
+type ChatService struct {
+	ai       *AIService
+	contexts *AnalysisContextBuilder
+	analyses *AnalysisHistory
+	cache    *cache.CacheManager
+	budget   int
+}
+
+// NewChatService creates a new chat service
+func NewChatService(ai *AIService, contexts *AnalysisContextBuilder, analyses *AnalysisHistory, cacheManager *cache.CacheManager, cfg *config.Config) *ChatService {
+	return &ChatService{
+		ai:       ai,
+		contexts: contexts,
+		analyses: analyses,
+		cache:    cacheManager,
+		budget:   cfg.ChatTokenBudget,
+	}
+}
+
+// Send answers a message for owner, starting a new session when req.SessionID is empty
+func (s *ChatService) Send(ctx context.Context, owner string, req models.ChatRequest) (*models.ChatResponse, error) {
+	session, actx, err := s.session(ctx, owner, req)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	data := &session.data
+	remaining := data.TokenBudget - data.TokensUsed
+	if remaining <= 0 {
+		return nil, ErrChatBudgetExhausted
+	}
+
+	tmpl, err := s.ai.ResolvePrompt(prompts.KindChat, data.PromptVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-ground every turn in the current data and the newest analysis
+	if actx == nil {
+		if actx, err = s.contexts.Build(ctx, data.TokenID); err != nil {
+			return nil, err
+		}
+	}
+	cctx := &models.ChatContext{
+		Analysis: actx,
+		History:  data.Messages,
+		Message:  req.Message,
+	}
+	if len(cctx.History) > maxChatHistoryTurns {
+		cctx.History = cctx.History[len(cctx.History)-maxChatHistoryTurns:]
+	}
+	if latest := s.analyses.ForToken(data.TokenID, 1); len(latest) > 0 {
+		cctx.LastAnalysis = &latest[0]
+		data.AnalysisID = latest[0].ID
+	}
+
+	log.Printf("💬 Chat %s (%s): turn %d", data.ID, data.Symbol, len(data.Messages)/2+1)
+
+	resp, err := s.ai.Chat(ctx, cctx, tmpl, int32(remaining))
+	if err != nil {
+		// Tokens spent on a failed turn still count against the session
+		if resp != nil && resp.Usage.Total() > 0 {
+			data.TokensUsed += resp.Usage.Total()
+			s.cache.SetChatSession(data.ID, session)
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	spent := resp.Usage.Total()
+	data.Messages = append(data.Messages,
+		models.ChatMessage{Role: models.ChatRoleUser, Content: req.Message, CreatedAt: now},
+		models.ChatMessage{Role: models.ChatRoleModel, Content: resp.Text, Tokens: spent, CreatedAt: now},
+	)
+	data.TokensUsed += spent
+	data.ExpiresAt = now.Add(s.cache.ChatSessionDuration())
+	s.cache.SetChatSession(data.ID, session)
+
+	remaining = data.TokenBudget - data.TokensUsed
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &models.ChatResponse{
+		Status:          "success",
+		SessionID:       data.ID,
+		TokenID:         data.TokenID,
+		Symbol:          data.Symbol,
+		Reply:           resp.Text,
+		Model:           resp.Model,
+		TokensUsed:      data.TokensUsed,
+		TokensRemaining: remaining,
+		ExpiresAt:       data.ExpiresAt,
+		Timestamp:       now,
+	}, nil
+}
+
+// Session returns a copy of the state and history of a live session started by owner
+func (s *ChatService) Session(owner, id string) (*models.ChatSession, bool) {
+	cached, found := s.cache.GetChatSession(id)
+	if !found {
+		return nil, false
+	}
+	session := cached.(*chatSession)
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.data.Owner != owner {
+		return nil, false
+	}
+	data := session.data
+	data.Messages = append([]models.ChatMessage(nil), session.data.Messages...)
+	return &data, true
+}
+
+// session looks up owner's requested session or starts a new one about req.TokenID;
+// the analysis context built for a new session is returned for reuse. Another
+// client's session is reported as not found.
+func (s *ChatService) session(ctx context.Context, owner string, req models.ChatRequest) (*chatSession, *models.AnalysisContext, error) {
+	if req.SessionID != "" {
+		cached, found := s.cache.GetChatSession(req.SessionID)
+		if !found {
+			return nil, nil, ErrChatSessionNotFound
+		}
+		session := cached.(*chatSession)
+		if session.data.Owner != owner {
+			return nil, nil, ErrChatSessionNotFound
+		}
+		if req.TokenID != "" && !strings.EqualFold(req.TokenID, session.data.TokenID) && !strings.EqualFold(req.TokenID, session.data.Symbol) {
+			return nil, nil, ErrChatTokenMismatch
+		}
+		return session, nil, nil
+	}
+
+	if req.TokenID == "" {
+		return nil, nil, ErrChatTokenRequired
+	}
+	actx, err := s.contexts.Build(ctx, req.TokenID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpl, err := s.ai.ResolvePrompt(prompts.KindChat, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	session := &chatSession{data: models.ChatSession{
+		ID:            store.NewID(),
+		Owner:         owner,
+		TokenID:       actx.Token.ID,
+		Symbol:        actx.Token.Symbol,
+		PromptVersion: tmpl.ID,
+		TokenBudget:   s.budget,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(s.cache.ChatSessionDuration()),
+	}}
+	s.cache.SetChatSession(session.data.ID, session)
+	log.Printf("💬 Chat session %s started for %s", session.data.ID, actx.Token.Symbol)
+	return session, actx, nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"backend/models"
+	"backend/utils"
+	"math"
+	"time"
+)
+
+// Confidence component weights (total = 100)
+const (
+	completenessWeight = 40.0
+	agreementWeight    = 25.0
+	freshnessWeight    = 20.0
+	historyWeight      = 15.0
+)
+
+// Tolerances for two sources to be considered in agreement
+const (
+	priceAgreementTolerance  = 0.02 // 2% from the cross-source median
+	volumeAgreementTolerance = 0.25 // venues count volume differently, so allow 25%
+)
+
+// coverageField describes one input the scorer relies on and how much it matters
+type coverageField struct {
+	name    string
+	weight  float64
+	present func(token *models.Token) bool
+}
+
+var coverageFields = []coverageField{
+	{"price", 3, func(t *models.Token) bool { return t.Price > 0 }},
+	{"market_cap", 3, func(t *models.Token) bool { return t.MarketCap > 0 }},
+	{"volume_24h", 3, func(t *models.Token) bool { return t.Volume24h > 0 }},
+	{"liquidity", 2, func(t *models.Token) bool { return t.Liquidity > 0 }},
+	{"tvl", 1, func(t *models.Token) bool { return t.TVL > 0 }},
+	{"circulating_supply", 1, func(t *models.Token) bool { return t.CirculatingSupply > 0 }},
+	{"total_supply", 1, func(t *models.Token) bool { return t.TotalSupply > 0 || t.MaxSupply > 0 }},
+	{"change_7d", 1, func(t *models.Token) bool { return t.Change7d != 0 }},
+	{"change_30d", 1, func(t *models.Token) bool { return t.Change30d != 0 }},
+	{"change_90d", 1, func(t *models.Token) bool { return t.Change90d != 0 }},
+	{"sparkline", 1, func(t *models.Token) bool { return len(t.Sparkline) > 0 }},
+	{"holder_count", 1, func(t *models.Token) bool { return t.HolderCount > 0 }},
+	{"top10_holders_ratio", 1, func(t *models.Token) bool { return t.Top10HoldersRatio > 0 }},
+	{"audit_status", 1, func(t *models.Token) bool { return t.AuditStatus != "" }},
+	{"contract_age", 1, func(t *models.Token) bool { return t.ContractAge > 0 }},
+	{"price_history", 2, func(t *models.Token) bool { return len(t.PriceHistory.Last30Days) > 0 }},
+	{"volume_history", 1, func(t *models.Token) bool { return len(t.VolumeHistory.Last7Days) > 0 }},
+}
+
+// calculateConfidence derives confidence (0-100) from field coverage, cross-source
+// agreement, data freshness and history length, filling in the coverage report
+func (s *EnhancedScorer) calculateConfidence(token *models.Token, coverage *models.DataCoverage) float64 {
+	coverage.CompletenessScore = s.assessCompleteness(token, coverage)
+	coverage.AgreementScore = s.assessSourceAgreement(token, coverage)
+	coverage.FreshnessScore = s.assessFreshness(token, coverage)
+	coverage.HistoryScore = s.assessHistoryDepth(token, coverage)
+
+	confidence := coverage.CompletenessScore*completenessWeight +
+		coverage.AgreementScore*agreementWeight +
+		coverage.FreshnessScore*freshnessWeight +
+		coverage.HistoryScore*historyWeight
+
+	return math.Min(math.Max(confidence, 0), 100)
+}
+
+func (s *EnhancedScorer) assessCompleteness(token *models.Token, coverage *models.DataCoverage) float64 {
+	coverage.Fields = make(map[string]bool, len(coverageFields))
+
+	var covered, total float64
+	for _, field := range coverageFields {
+		present := field.present(token)
+		coverage.Fields[field.name] = present
+		total += field.weight
+		if present {
+			covered += field.weight
+		}
+	}
+
+	coverage.CoverageRatio = covered / total
+	return coverage.CoverageRatio
+}
+
+func (s *EnhancedScorer) assessSourceAgreement(token *models.Token, coverage *models.DataCoverage) float64 {
+	var prices, volumes []float64
+	for _, quote := range token.Sources {
+		if quote.Price > 0 {
+			prices = append(prices, quote.Price)
+		}
+		if quote.Volume24h > 0 {
+			volumes = append(volumes, quote.Volume24h)
+		}
+	}
+
+	coverage.PriceSources = len(prices)
+	coverage.VolumeSources = len(volumes)
+
+	var dispersion float64
+	coverage.AgreeingPriceSources, dispersion = countAgreeing(prices, priceAgreementTolerance)
+	coverage.PriceDispersion = dispersion * 100
+	coverage.AgreeingVolumeSources, _ = countAgreeing(volumes, volumeAgreementTolerance)
+
+	// Price agreement matters more than volume (70/30)
+	return agreementScore(coverage.PriceSources, coverage.AgreeingPriceSources)*0.7 +
+		agreementScore(coverage.VolumeSources, coverage.AgreeingVolumeSources)*0.3
+}
+
+// countAgreeing returns how many values sit within tolerance of the median and
+// the largest relative deviation from it
+func countAgreeing(values []float64, tolerance float64) (int, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	median := utils.CalculateMedian(values)
+	agreeing := 0
+	maxDeviation := 0.0
+	for _, v := range values {
+		deviation := math.Abs(v-median) / median
+		maxDeviation = math.Max(maxDeviation, deviation)
+		if deviation <= tolerance {
+			agreeing++
+		}
+	}
+	return agreeing, maxDeviation
+}
+
+func agreementScore(sources, agreeing int) float64 {
+	switch {
+	case sources == 0:
+		return 0
+	case sources == 1:
+		return 0.5 // Single source - nothing to cross-check against
+	case agreeing >= 3:
+		return 1.0
+	case agreeing == 2:
+		return 0.85
+	default:
+		return 0.2 // Sources disagree
+	}
+}
+
+func (s *EnhancedScorer) assessFreshness(token *models.Token, coverage *models.DataCoverage) float64 {
+	if token.LastUpdated.IsZero() {
+		coverage.DataAgeMinutes = -1
+		return 0.3 // Unknown age
+	}
+
+	age := time.Since(token.LastUpdated)
+	coverage.DataAgeMinutes = math.Round(age.Minutes()*10) / 10
+
+	switch {
+	case age <= 10*time.Minute:
+		return 1.0
+	case age <= time.Hour:
+		return 0.8
+	case age <= 6*time.Hour:
+		return 0.5
+	case age <= 24*time.Hour:
+		return 0.3
+	default:
+		return 0.1
+	}
+}
+
+func (s *EnhancedScorer) assessHistoryDepth(token *models.Token, coverage *models.DataCoverage) float64 {
+	days := 0
+	for _, series := range [][]float64{
+		token.PriceHistory.Last7Days,
+		token.PriceHistory.Last30Days,
+		token.PriceHistory.Last90Days,
+	} {
+		if len(series) > days {
+			days = len(series)
+		}
+	}
+	// The sparkline is intraday but spans a week
+	if len(token.Sparkline) > 0 && days < 7 {
+		days = 7
+	}
+	coverage.HistoryDays = days
+
+	// 90 daily points is considered a full history
+	return math.Min(float64(days)/90.0, 1.0)
+}
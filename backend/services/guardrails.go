@@ -0,0 +1,166 @@
+package services
+
+import (
+	"backend/models"
+	"fmt"
+)
+
+// Guardrail thresholds
+const (
+	guardrailMaxLevelFactor   = 10.0 // levels more than 10x away from the price are rejected
+	guardrailEntryWarnPct     = 50.0 // entry zones further than this from the price are flagged
+	guardrailLowTrustScore    = 40.0
+	guardrailThinLiquidity    = 0.01 // liquidity / market cap
+	guardrailMinHistoryDays   = 30
+	guardrailHighVolatility   = 8.0 // 30d daily volatility, %
+	guardrailLowConfidenceMin = 2   // data flags that make confidence low on their own
+)
+
+// Confidence flags
+const (
+	FlagLowTrustScore   = "low_trust_score"
+	FlagThinLiquidity   = "thin_liquidity"
+	FlagLimitedHistory  = "limited_history"
+	FlagNoIndicators    = "no_indicators"
+	FlagHighVolatility  = "high_volatility"
+	FlagPlanAnnotations = "plan_annotations"
+)
+
+// CheckTradingPlan validates the price levels of an analysis against the token's
+// current price. A plan with errors is rejected: its action is downgraded to WATCH
+// so it is neither presented nor backtested as a trade.
+func CheckTradingPlan(analysis *models.TokenAnalysis, actx *models.AnalysisContext) *models.GuardrailReport {
+	price := actx.Token.Price
+	report := &models.GuardrailReport{ReferencePrice: price}
+	issue := func(severity, code, field, format string, args ...interface{}) {
+		report.Issues = append(report.Issues, models.GuardrailIssue{
+			Code:     code,
+			Severity: severity,
+			Field:    field,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	// The levels as written; the backtester's ParseRecommendation later drops targets on the
+	// losing side of the stop, which must be reported here rather than hidden
+	plan := parsePlanLevels(analysis)
+	direction := plan.Direction
+	report.EntryLow, report.EntryHigh = plan.EntryLow, plan.EntryHigh
+	report.Targets = plan.Targets
+	report.StopLoss = plan.StopLoss
+
+	var entries []float64
+	if plan.EntryLow > 0 {
+		entries = []float64{plan.EntryLow, plan.EntryHigh}
+	}
+
+	// Every level must be within a sane distance of the current price
+	if price > 0 {
+		levels := []struct {
+			field  string
+			values []float64
+		}{
+			{"recommendation.entry_zone", entries},
+			{"trading_plan.sell_targets", report.Targets},
+			{"trading_plan.stop_loss", nonZero(report.StopLoss)},
+		}
+		for _, level := range levels {
+			for _, value := range level.values {
+				if value > price*guardrailMaxLevelFactor || value < price/guardrailMaxLevelFactor {
+					issue(models.GuardrailError, "level_out_of_range", level.field,
+						"$%.6g is more than %.0fx away from the current price $%.6g", value, guardrailMaxLevelFactor, price)
+				}
+			}
+		}
+	}
+
+	if direction != "none" {
+		// Levels are compared with the entry zone, or the current price without one
+		entryLow, entryHigh := report.EntryLow, report.EntryHigh
+		if entryLow == 0 {
+			entryLow, entryHigh = price, price
+			if analysis.Recommendation.Action != "HOLD" {
+				issue(models.GuardrailWarning, "missing_entry", "recommendation.entry_zone", "no numeric entry level; checked against the current price")
+			}
+		} else if price > 0 && (entryLow > price*(1+guardrailEntryWarnPct/100) || entryHigh < price*(1-guardrailEntryWarnPct/100)) {
+			issue(models.GuardrailWarning, "entry_far_from_price", "recommendation.entry_zone",
+				"entry zone $%.6g-$%.6g is more than %.0f%% away from the current price $%.6g", entryLow, entryHigh, guardrailEntryWarnPct, price)
+		}
+
+		switch {
+		case report.StopLoss == 0:
+			issue(models.GuardrailError, "missing_stop_loss", "trading_plan.stop_loss", "no numeric stop loss")
+		case direction == "long" && report.StopLoss >= entryLow:
+			issue(models.GuardrailError, "stop_loss_wrong_side", "trading_plan.stop_loss",
+				"stop loss $%.6g must be below the entry $%.6g for a %s", report.StopLoss, entryLow, analysis.Recommendation.Action)
+		case direction == "short" && report.StopLoss <= entryHigh:
+			issue(models.GuardrailError, "stop_loss_wrong_side", "trading_plan.stop_loss",
+				"stop loss $%.6g must be above the entry $%.6g for a SELL", report.StopLoss, entryHigh)
+		}
+
+		if len(report.Targets) == 0 {
+			issue(models.GuardrailError, "missing_target", "trading_plan.sell_targets", "no numeric take-profit level")
+		}
+		for _, target := range report.Targets {
+			if (direction == "long" && target <= entryHigh) || (direction == "short" && target >= entryLow) {
+				issue(models.GuardrailError, "target_wrong_side", "trading_plan.sell_targets",
+					"target $%.6g is on the wrong side of the entry for a %s", target, analysis.Recommendation.Action)
+			}
+		}
+	}
+
+	report.Status = models.GuardrailPassed
+	for _, found := range report.Issues {
+		if found.Severity == models.GuardrailError {
+			report.Status = models.GuardrailRejected
+			break
+		}
+		report.Status = models.GuardrailWarnings
+	}
+	if report.Status == models.GuardrailRejected {
+		report.OriginalAction = analysis.Recommendation.Action
+		analysis.Recommendation.Action = "WATCH"
+	}
+
+	report.ConfidenceFlags = confidenceFlags(actx)
+	switch {
+	case report.Status == models.GuardrailRejected || len(report.ConfidenceFlags) >= guardrailLowConfidenceMin:
+		report.Confidence = models.ConfidenceLow
+	case report.Status == models.GuardrailWarnings || len(report.ConfidenceFlags) > 0:
+		report.Confidence = models.ConfidenceMedium
+	default:
+		report.Confidence = models.ConfidenceHigh
+	}
+	if report.Status == models.GuardrailWarnings {
+		report.ConfidenceFlags = append(report.ConfidenceFlags, FlagPlanAnnotations)
+	}
+	return report
+}
+
+// confidenceFlags lists data-quality weaknesses the analysis was generated under
+func confidenceFlags(actx *models.AnalysisContext) []string {
+	var flags []string
+	token := actx.Token
+	if token.TrustScore < guardrailLowTrustScore {
+		flags = append(flags, FlagLowTrustScore)
+	}
+	if token.MarketCap > 0 && token.Liquidity > 0 && token.Liquidity/token.MarketCap < guardrailThinLiquidity {
+		flags = append(flags, FlagThinLiquidity)
+	}
+	if actx.History == nil || actx.History.Days < guardrailMinHistoryDays {
+		flags = append(flags, FlagLimitedHistory)
+	} else if actx.History.Volatility30d > guardrailHighVolatility {
+		flags = append(flags, FlagHighVolatility)
+	}
+	if actx.Indicators == nil {
+		flags = append(flags, FlagNoIndicators)
+	}
+	return flags
+}
+
+func nonZero(value float64) []float64 {
+	if value == 0 {
+		return nil
+	}
+	return []float64{value}
+}
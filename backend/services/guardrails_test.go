@@ -0,0 +1,71 @@
+package services
+
+import (
+	"backend/models"
+	"reflect"
+	"testing"
+)
+
+func planAnalysis(action, entry, stop string, targets ...string) *models.TokenAnalysis {
+	return &models.TokenAnalysis{
+		Recommendation: models.Recommendation{Action: action, EntryZone: entry},
+		TradingPlan:    models.TradingPlan{SellTargets: targets, StopLoss: stop},
+	}
+}
+
+func issueCodes(report *models.GuardrailReport) map[string]bool {
+	codes := make(map[string]bool)
+	for _, issue := range report.Issues {
+		codes[issue.Code] = true
+	}
+	return codes
+}
+
+func TestCheckTradingPlanSeesEveryLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis *models.TokenAnalysis
+		wantCode string
+		targets  []float64
+	}{
+		{"target below the stop of a long", planAnalysis("BUY ZONE", "$58 - $62", "$50", "TP1: $70", "TP2: $40"),
+			"target_wrong_side", []float64{70, 40}},
+		{"target 100x below the price", planAnalysis("BUY ZONE", "$58 - $62", "$50", "TP1: $70", "TP2: $0.6"),
+			"level_out_of_range", []float64{70, 0.6}},
+		{"target above the stop of a short", planAnalysis("SELL", "$58 - $62", "$70", "TP1: $50", "TP2: $80"),
+			"target_wrong_side", []float64{50, 80}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actx := &models.AnalysisContext{Token: models.Token{Price: 60}}
+			report := CheckTradingPlan(tt.analysis, actx)
+			if !issueCodes(report)[tt.wantCode] {
+				t.Errorf("issues = %+v, want %s", report.Issues, tt.wantCode)
+			}
+			if report.Status != models.GuardrailRejected || tt.analysis.Recommendation.Action != "WATCH" {
+				t.Errorf("status = %s, action = %s, want rejected and WATCH", report.Status, tt.analysis.Recommendation.Action)
+			}
+			if !reflect.DeepEqual(report.Targets, tt.targets) {
+				t.Errorf("report targets = %v, want %v", report.Targets, tt.targets)
+			}
+		})
+	}
+}
+
+func TestParseRecommendationDropsTargetsBeyondStop(t *testing.T) {
+	plan, err := ParseRecommendation(planAnalysis("BUY ZONE", "$58 - $62", "$50", "TP2: $80", "TP1: $70", "TP3: $40"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{70, 80}; !reflect.DeepEqual(plan.Targets, want) {
+		t.Errorf("targets = %v, want %v", plan.Targets, want)
+	}
+}
+
+func TestCheckTradingPlanPassesSanePlan(t *testing.T) {
+	analysis := planAnalysis("BUY ZONE", "$58 - $62", "$50", "TP1: $70", "TP2: $80")
+	report := CheckTradingPlan(analysis, &models.AnalysisContext{Token: models.Token{Price: 60}})
+	if report.Status == models.GuardrailRejected {
+		t.Errorf("sane plan rejected: %+v", report.Issues)
+	}
+}
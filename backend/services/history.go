@@ -0,0 +1,118 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// maxHistoryDays is the longest daily history CryptoCompare returns in one call
+const maxHistoryDays = 2000
+
+// HistoryService fetches and caches daily OHLCV history
+type HistoryService struct {
+	config *config.Config
+	cache  *cache.Cache
+}
+
+// NewHistoryService creates a new history service
+func NewHistoryService(cfg *config.Config) *HistoryService {
+	return &HistoryService{
+		config: cfg,
+		cache:  cache.New(30*time.Minute, 60*time.Minute),
+	}
+}
+
+// GetDailyCandles returns up to `days` daily candles for a symbol, oldest first
+func (h *HistoryService) GetDailyCandles(ctx context.Context, symbol string, days int) ([]models.Candle, error) {
+	symbol = utils.NormalizeSymbol(symbol)
+	if days <= 0 || days > maxHistoryDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", maxHistoryDays)
+	}
+
+	// A longer cached series can serve any shorter request
+	if cached, found := h.cache.Get("daily_" + symbol); found {
+		candles := cached.([]models.Candle)
+		if len(candles) >= days {
+			return candles[len(candles)-days:], nil
+		}
+	}
+
+	candles, err := h.fetchCryptoCompareDaily(ctx, symbol, days)
+	if err != nil {
+		return nil, err
+	}
+
+	h.cache.Set("daily_"+symbol, candles, cache.DefaultExpiration)
+	if len(candles) > days {
+		candles = candles[len(candles)-days:]
+	}
+	return candles, nil
+}
+
+// GetDailyCloses is a convenience wrapper returning only closing prices
+func (h *HistoryService) GetDailyCloses(ctx context.Context, symbol string, days int) ([]models.Candle, []float64, error) {
+	candles, err := h.GetDailyCandles(ctx, symbol, days)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close
+	}
+	return candles, closes, nil
+}
+
+// fetchCryptoCompareDaily fetches daily bars from CryptoCompare's histoday endpoint
+func (h *HistoryService) fetchCryptoCompareDaily(ctx context.Context, symbol string, days int) ([]models.Candle, error) {
+	// histoday returns limit+1 bars
+	endpoint := fmt.Sprintf("%s/data/v2/histoday?fsym=%s&tsym=USD&limit=%d", h.config.CryptoCompareAPIURL, url.QueryEscape(symbol), days-1)
+
+	headers := map[string]string{}
+	if h.config.CryptoCompareAPIKey != "" {
+		headers["authorization"] = "Apikey " + h.config.CryptoCompareAPIKey
+	}
+
+	data, err := utils.FetchJSONWithHeaders(endpoint, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp models.CryptoCompareHistoResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(resp.Response, "Success") {
+		return nil, fmt.Errorf("CryptoCompare history for %s: %s", symbol, resp.Message)
+	}
+
+	candles := make([]models.Candle, 0, len(resp.Data.Data))
+	for _, bar := range resp.Data.Data {
+		// Leading bars before listing come back as zeros
+		if bar.Close == 0 {
+			continue
+		}
+		candles = append(candles, models.Candle{
+			Time:   time.Unix(bar.Time, 0).UTC(),
+			Open:   bar.Open,
+			High:   bar.High,
+			Low:    bar.Low,
+			Close:  bar.Close,
+			Volume: bar.VolumeTo,
+		})
+	}
+
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no price history available for %s", symbol)
+	}
+	return candles, nil
+}
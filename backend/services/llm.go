@@ -0,0 +1,168 @@
+package services
+
+import (
+	"backend/config"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Supported LLM provider names (config LLM_PROVIDER)
+const (
+	ProviderGemini   = "gemini"
+	ProviderOpenAI   = "openai"
+	ProviderTemplate = "template"
+)
+
+// LLMProvider is a text-generation backend used by the AI service. A failed call may
+// still return a response holding the usage, and any partial text, spent before the failure.
+type LLMProvider interface {
+	// Name identifies the provider (gemini, openai, template)
+	Name() string
+	// Model is the model identifier requests are sent to
+	Model() string
+	// Generate produces a single completion for the request
+	Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error)
+	// GenerateStream produces a completion, calling onChunk with each partial
+	// piece of text as it arrives; the returned response holds the full text
+	GenerateStream(ctx context.Context, req LLMRequest, onChunk func(text string)) (*LLMResponse, error)
+	// Chat answers the next user message of a multi-turn conversation
+	Chat(ctx context.Context, req LLMChatRequest) (*LLMResponse, error)
+	// Close releases any underlying client resources
+	Close() error
+}
+
+// LLMRequest is a provider-neutral generation request
+type LLMRequest struct {
+	Prompt      string
+	Schema      *LLMSchema // optional: constrain output to JSON matching this schema
+	Temperature float32
+	TopP        float32
+	MaxTokens   int32
+
+	// Tools the model may call while generating; CallTool executes them.
+	// Tool calling and JSON mode are exclusive, so Schema is ignored when set.
+	Tools    []LLMTool
+	CallTool LLMToolCaller
+	// CheckBudget, if set, runs before every tool round after the first with the usage of
+	// the rounds so far; an error stops generation
+	CheckBudget func(spent LLMUsage) error
+
+	// Input is the structured data the prompt was rendered from; offline
+	// providers that can't read prose use it to build their answer
+	Input interface{}
+}
+
+// maxToolRounds bounds model/tool round trips; the last round must answer without tools
+const maxToolRounds = 6
+
+// errToolRoundsExceeded is returned when the model still calls tools after the last round
+var errToolRoundsExceeded = fmt.Errorf("AI generation failed: model still calling tools after %d rounds", maxToolRounds)
+
+// beforeToolRound checks the budget ahead of every round after the first
+func beforeToolRound(req LLMRequest, round int, spent LLMUsage) error {
+	if round > 1 && req.CheckBudget != nil {
+		return req.CheckBudget(spent)
+	}
+	return nil
+}
+
+// LLMTool is a function the model may call during generation
+type LLMTool struct {
+	Name        string
+	Description string
+	Parameters  *LLMSchema // object schema of the arguments
+}
+
+// LLMToolCaller runs a tool call and returns its JSON-object result; failures
+// are reported to the model inside the result rather than aborting generation
+type LLMToolCaller func(ctx context.Context, name string, args map[string]interface{}) map[string]interface{}
+
+// Conversation roles in LLMMessage
+const (
+	LLMRoleUser  = "user"
+	LLMRoleModel = "model"
+)
+
+// LLMMessage is one turn of a conversation
+type LLMMessage struct {
+	Role string // LLMRoleUser or LLMRoleModel
+	Text string
+}
+
+// LLMChatRequest is a provider-neutral multi-turn chat request
+type LLMChatRequest struct {
+	System      string       // grounding instructions, sent as the system prompt
+	History     []LLMMessage // earlier turns, oldest first
+	Message     string       // the new user message
+	Temperature float32
+	TopP        float32
+	MaxTokens   int32
+
+	// Input is the structured conversation state, for offline providers
+	Input interface{}
+}
+
+// LLMResponse is a provider-neutral completion
+type LLMResponse struct {
+	Text  string
+	Model string
+	Usage LLMUsage
+}
+
+// LLMUsage is the token accounting reported by the provider (zero when unreported)
+type LLMUsage struct {
+	PromptTokens int
+	OutputTokens int
+}
+
+// Total returns prompt plus output tokens
+func (u LLMUsage) Total() int {
+	return u.PromptTokens + u.OutputTokens
+}
+
+// Add accumulates the usage of another call
+func (u *LLMUsage) Add(other LLMUsage) {
+	u.PromptTokens += other.PromptTokens
+	u.OutputTokens += other.OutputTokens
+}
+
+// estimateTokens approximates a token count for providers that don't report usage (~4 chars per token)
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// LLMSchema is a provider-neutral subset of JSON Schema for structured output
+type LLMSchema struct {
+	Type        string // object, array, string, number, integer, boolean
+	Description string
+	Enum        []string
+	Items       *LLMSchema
+	Properties  map[string]*LLMSchema
+	Required    []string
+}
+
+// NewLLMProvider builds the provider selected in config. With no explicit
+// selection it uses Gemini when a key is present and the offline template otherwise.
+func NewLLMProvider(cfg *config.Config) (LLMProvider, error) {
+	provider := strings.ToLower(cfg.LLMProvider)
+	if provider == "" {
+		provider = ProviderGemini
+		if cfg.GeminiAPIKey == "" {
+			log.Println("⚠️  No LLM provider configured and GEMINI_API_KEY unset - using offline template provider")
+			provider = ProviderTemplate
+		}
+	}
+
+	switch provider {
+	case ProviderGemini:
+		return NewGeminiProvider(cfg)
+	case ProviderOpenAI:
+		return NewOpenAIProvider(cfg)
+	case ProviderTemplate:
+		return NewTemplateProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q (use gemini, openai or template)", cfg.LLMProvider)
+	}
+}
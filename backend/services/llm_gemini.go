@@ -0,0 +1,274 @@
+package services
+
+import (
+	"backend/config"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// DefaultGeminiModel is used when LLM_MODEL isn't set
+const DefaultGeminiModel = "gemini-3-flash-preview"
+
+// GeminiProvider generates content through Google Gemini
+type GeminiProvider struct {
+	client    *genai.Client
+	modelName string
+}
+
+// NewGeminiProvider creates a Gemini-backed provider
+func NewGeminiProvider(cfg *config.Config) (*GeminiProvider, error) {
+	if cfg.GeminiAPIKey == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEY not configured")
+	}
+
+	client, err := genai.NewClient(context.Background(), option.WithAPIKey(cfg.GeminiAPIKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+
+	modelName := cfg.LLMModel
+	if modelName == "" {
+		modelName = DefaultGeminiModel
+	}
+
+	return &GeminiProvider{client: client, modelName: modelName}, nil
+}
+
+func (p *GeminiProvider) Name() string  { return ProviderGemini }
+func (p *GeminiProvider) Model() string { return p.modelName }
+
+// Generate runs a single GenerateContent call, or a function-calling loop when tools are set
+func (p *GeminiProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	if len(req.Tools) > 0 {
+		return p.generateWithTools(ctx, req)
+	}
+	model := p.configuredModel(req)
+
+	resp, err := model.GenerateContent(ctx, genai.Text(req.Prompt))
+	if err != nil {
+		return nil, fmt.Errorf("AI generation failed: %w", err)
+	}
+
+	text := geminiResponseText(resp)
+	if text == "" {
+		return &LLMResponse{Model: p.modelName, Usage: geminiUsage(resp)}, fmt.Errorf("empty response from AI")
+	}
+	return &LLMResponse{Text: text, Model: p.modelName, Usage: geminiUsage(resp)}, nil
+}
+
+// GenerateStream streams partial output via GenerateContentStream. With tools
+// the answer is only known after the tool loop, so it arrives as one chunk.
+func (p *GeminiProvider) GenerateStream(ctx context.Context, req LLMRequest, onChunk func(text string)) (*LLMResponse, error) {
+	if len(req.Tools) > 0 {
+		resp, err := p.generateWithTools(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		onChunk(resp.Text)
+		return resp, nil
+	}
+	model := p.configuredModel(req)
+	iter := model.GenerateContentStream(ctx, genai.Text(req.Prompt))
+
+	var full strings.Builder
+	var usage LLMUsage
+	for {
+		resp, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			partial := &LLMResponse{Text: full.String(), Model: p.modelName, Usage: usage}
+			return partial, fmt.Errorf("AI generation failed: %w", err)
+		}
+		if resp.UsageMetadata != nil {
+			usage = geminiUsage(resp) // cumulative; the last chunk holds the totals
+		}
+
+		chunk := geminiResponseText(resp)
+		if chunk == "" {
+			continue
+		}
+		full.WriteString(chunk)
+		onChunk(chunk)
+	}
+
+	if full.Len() == 0 {
+		return &LLMResponse{Model: p.modelName, Usage: usage}, fmt.Errorf("empty response from AI")
+	}
+	return &LLMResponse{Text: full.String(), Model: p.modelName, Usage: usage}, nil
+}
+
+// generateWithTools answers function calls through a chat session until the
+// model replies with text; the final round disables function calling
+func (p *GeminiProvider) generateWithTools(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	model := p.configuredModel(req)
+	session := model.StartChat()
+
+	var usage LLMUsage
+	spent := func() *LLMResponse { return &LLMResponse{Model: p.modelName, Usage: usage} }
+	parts := []genai.Part{genai.Text(req.Prompt)}
+	for round := 1; round <= maxToolRounds; round++ {
+		if err := beforeToolRound(req, round, usage); err != nil {
+			return spent(), err
+		}
+		if round == maxToolRounds {
+			model.ToolConfig = &genai.ToolConfig{
+				FunctionCallingConfig: &genai.FunctionCallingConfig{Mode: genai.FunctionCallingNone},
+			}
+		}
+
+		resp, err := session.SendMessage(ctx, parts...)
+		if err != nil {
+			return spent(), fmt.Errorf("AI generation failed: %w", err)
+		}
+		usage.Add(geminiUsage(resp))
+
+		var calls []genai.FunctionCall
+		if len(resp.Candidates) > 0 {
+			calls = resp.Candidates[0].FunctionCalls()
+		}
+		if len(calls) == 0 {
+			text := geminiResponseText(resp)
+			if text == "" {
+				return spent(), fmt.Errorf("empty response from AI")
+			}
+			return &LLMResponse{Text: text, Model: p.modelName, Usage: usage}, nil
+		}
+
+		parts = make([]genai.Part, 0, len(calls))
+		for _, call := range calls {
+			parts = append(parts, genai.FunctionResponse{
+				Name:     call.Name,
+				Response: req.CallTool(ctx, call.Name, call.Args),
+			})
+		}
+	}
+	return spent(), errToolRoundsExceeded
+}
+
+// Chat replays the history into a Gemini chat session and sends the new message
+func (p *GeminiProvider) Chat(ctx context.Context, req LLMChatRequest) (*LLMResponse, error) {
+	model := p.configuredModel(LLMRequest{Temperature: req.Temperature, TopP: req.TopP, MaxTokens: req.MaxTokens})
+	if req.System != "" {
+		model.SystemInstruction = genai.NewUserContent(genai.Text(req.System))
+	}
+
+	session := model.StartChat()
+	for _, message := range req.History {
+		session.History = append(session.History, &genai.Content{
+			Role:  message.Role,
+			Parts: []genai.Part{genai.Text(message.Text)},
+		})
+	}
+
+	resp, err := session.SendMessage(ctx, genai.Text(req.Message))
+	if err != nil {
+		return nil, fmt.Errorf("AI chat failed: %w", err)
+	}
+
+	text := geminiResponseText(resp)
+	if text == "" {
+		return &LLMResponse{Model: p.modelName, Usage: geminiUsage(resp)}, fmt.Errorf("empty response from AI")
+	}
+	return &LLMResponse{Text: text, Model: p.modelName, Usage: geminiUsage(resp)}, nil
+}
+
+// configuredModel builds a per-request model handle so concurrent requests don't share settings
+func (p *GeminiProvider) configuredModel(req LLMRequest) *genai.GenerativeModel {
+	model := p.client.GenerativeModel(p.modelName)
+	if req.Temperature > 0 {
+		model.SetTemperature(req.Temperature)
+	}
+	if req.TopP > 0 {
+		model.SetTopP(req.TopP)
+	}
+	if req.MaxTokens > 0 {
+		model.SetMaxOutputTokens(req.MaxTokens)
+	}
+	if len(req.Tools) > 0 {
+		declarations := make([]*genai.FunctionDeclaration, 0, len(req.Tools))
+		for _, tool := range req.Tools {
+			declarations = append(declarations, &genai.FunctionDeclaration{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  toGeminiSchema(tool.Parameters),
+			})
+		}
+		model.Tools = []*genai.Tool{{FunctionDeclarations: declarations}}
+	} else if req.Schema != nil {
+		model.ResponseMIMEType = "application/json"
+		model.ResponseSchema = toGeminiSchema(req.Schema)
+	}
+	return model
+}
+
+// Close closes the Gemini client
+func (p *GeminiProvider) Close() error {
+	return p.client.Close()
+}
+
+// geminiResponseText concatenates the text parts of the first candidate
+func geminiResponseText(resp *genai.GenerateContentResponse) string {
+	if resp == nil || len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return ""
+	}
+
+	var text strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if t, ok := part.(genai.Text); ok {
+			text.WriteString(string(t))
+		}
+	}
+	return text.String()
+}
+
+// geminiUsage reads the response's usage metadata
+func geminiUsage(resp *genai.GenerateContentResponse) LLMUsage {
+	if resp == nil || resp.UsageMetadata == nil {
+		return LLMUsage{}
+	}
+	return LLMUsage{
+		PromptTokens: int(resp.UsageMetadata.PromptTokenCount),
+		OutputTokens: int(resp.UsageMetadata.CandidatesTokenCount),
+	}
+}
+
+// toGeminiSchema converts the neutral schema to genai's representation
+func toGeminiSchema(schema *LLMSchema) *genai.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	types := map[string]genai.Type{
+		"object":  genai.TypeObject,
+		"array":   genai.TypeArray,
+		"string":  genai.TypeString,
+		"number":  genai.TypeNumber,
+		"integer": genai.TypeInteger,
+		"boolean": genai.TypeBoolean,
+	}
+
+	result := &genai.Schema{
+		Type:        types[schema.Type],
+		Description: schema.Description,
+		Enum:        schema.Enum,
+		Items:       toGeminiSchema(schema.Items),
+		Required:    schema.Required,
+	}
+	if len(schema.Enum) > 0 {
+		result.Format = "enum"
+	}
+	if len(schema.Properties) > 0 {
+		result.Properties = make(map[string]*genai.Schema, len(schema.Properties))
+		for name, property := range schema.Properties {
+			result.Properties[name] = toGeminiSchema(property)
+		}
+	}
+	return result
+}
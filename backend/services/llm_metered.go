@@ -0,0 +1,69 @@
+package services
+
+import "context"
+
+// meteredProvider reserves a request against the daily budgets before each call and
+// charges its usage whatever the outcome, to the client tagged on the context. Tool
+// rounds are charged as they complete, so the budget check between rounds sees them.
+// Providers that don't report usage are charged an estimate from the text lengths.
+type meteredProvider struct {
+	LLMProvider
+	meter *UsageMeter
+}
+
+func newMeteredProvider(provider LLMProvider, meter *UsageMeter) LLMProvider {
+	return &meteredProvider{LLMProvider: provider, meter: meter}
+}
+
+func (p *meteredProvider) Generate(ctx context.Context, req LLMRequest) (resp *LLMResponse, err error) {
+	reservation, err := p.meter.Reserve(UsageClient(ctx), p.Model())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { p.finish(reservation, resp, len(req.Prompt)) }()
+
+	req.CheckBudget = reservation.Spend
+	return p.LLMProvider.Generate(ctx, req)
+}
+
+func (p *meteredProvider) GenerateStream(ctx context.Context, req LLMRequest, onChunk func(text string)) (resp *LLMResponse, err error) {
+	reservation, err := p.meter.Reserve(UsageClient(ctx), p.Model())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { p.finish(reservation, resp, len(req.Prompt)) }()
+
+	req.CheckBudget = reservation.Spend
+	return p.LLMProvider.GenerateStream(ctx, req, onChunk)
+}
+
+func (p *meteredProvider) Chat(ctx context.Context, req LLMChatRequest) (resp *LLMResponse, err error) {
+	reservation, err := p.meter.Reserve(UsageClient(ctx), p.Model())
+	if err != nil {
+		return nil, err
+	}
+	promptChars := len(req.System) + len(req.Message)
+	for _, message := range req.History {
+		promptChars += len(message.Text)
+	}
+	defer func() { p.finish(reservation, resp, promptChars) }()
+
+	return p.LLMProvider.Chat(ctx, req)
+}
+
+// finish charges a call's usage, including the partial usage a failed call returns with
+// its error. Usage the provider didn't report is estimated when text was produced.
+func (p *meteredProvider) finish(reservation *UsageReservation, resp *LLMResponse, promptChars int) {
+	model := p.Model()
+	var usage LLMUsage
+	if resp != nil {
+		if resp.Usage.Total() == 0 && resp.Text != "" {
+			resp.Usage = LLMUsage{PromptTokens: (promptChars + 3) / 4, OutputTokens: estimateTokens(resp.Text)}
+		}
+		usage = resp.Usage
+		if resp.Model != "" {
+			model = resp.Model
+		}
+	}
+	reservation.Finish(model, usage)
+}
@@ -0,0 +1,386 @@
+package services
+
+import (
+	"backend/config"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIProvider talks to any OpenAI-compatible chat completions endpoint
+// (OpenAI itself, a local llama.cpp server, Ollama, vLLM, ...)
+type OpenAIProvider struct {
+	baseURL   string
+	apiKey    string
+	modelName string
+	client    *http.Client
+}
+
+// NewOpenAIProvider creates a provider for an OpenAI-compatible endpoint
+func NewOpenAIProvider(cfg *config.Config) (*OpenAIProvider, error) {
+	if cfg.LLMBaseURL == "" {
+		return nil, fmt.Errorf("LLM_BASE_URL not configured")
+	}
+	if cfg.LLMModel == "" {
+		return nil, fmt.Errorf("LLM_MODEL not configured")
+	}
+
+	return &OpenAIProvider{
+		baseURL:   strings.TrimSuffix(cfg.LLMBaseURL, "/"),
+		apiKey:    cfg.LLMAPIKey,
+		modelName: cfg.LLMModel,
+		// Local models can be slow; generation timeouts come from the request context
+		client: &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+func (p *OpenAIProvider) Name() string  { return ProviderOpenAI }
+func (p *OpenAIProvider) Model() string { return p.modelName }
+
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"` // JSON-encoded object
+	} `json:"function"`
+}
+
+type openAITool struct {
+	Type     string                 `json:"type"`
+	Function map[string]interface{} `json:"function"`
+}
+
+type openAIChatRequest struct {
+	Model          string                 `json:"model"`
+	Messages       []openAIMessage        `json:"messages"`
+	Tools          []openAITool           `json:"tools,omitempty"`
+	Temperature    float32                `json:"temperature,omitempty"`
+	TopP           float32                `json:"top_p,omitempty"`
+	MaxTokens      int32                  `json:"max_tokens,omitempty"`
+	ResponseFormat map[string]interface{} `json:"response_format,omitempty"`
+	Stream         bool                   `json:"stream,omitempty"`
+}
+
+type openAIStreamChunk struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta openAIMessage `json:"delta"`
+	} `json:"choices"`
+}
+
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage,omitempty"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Generate sends a single-turn chat completion, or a tool-calling loop when tools are set
+func (p *OpenAIProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	if len(req.Tools) > 0 {
+		return p.generateWithTools(ctx, req)
+	}
+	resp, err := p.complete(ctx, p.buildRequest(req))
+	if err != nil {
+		return resp, fmt.Errorf("AI generation failed: %w", err)
+	}
+	return resp, nil
+}
+
+// generateWithTools executes requested tool calls and resends the conversation
+// until the model answers with text; the last round offers no tools
+func (p *OpenAIProvider) generateWithTools(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	body := p.buildRequest(req)
+	for _, tool := range req.Tools {
+		body.Tools = append(body.Tools, openAITool{
+			Type: "function",
+			Function: map[string]interface{}{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"parameters":  toJSONSchema(tool.Parameters),
+			},
+		})
+	}
+
+	var usage LLMUsage
+	spent := func() *LLMResponse { return &LLMResponse{Model: p.modelName, Usage: usage} }
+	for round := 1; round <= maxToolRounds; round++ {
+		if err := beforeToolRound(req, round, usage); err != nil {
+			return spent(), err
+		}
+		if round == maxToolRounds {
+			body.Tools = nil
+		}
+
+		var resp openAIChatResponse
+		if err := p.post(ctx, "/chat/completions", body, &resp); err != nil {
+			return spent(), fmt.Errorf("AI generation failed: %w", err)
+		}
+		if resp.Usage != nil {
+			usage.Add(LLMUsage{PromptTokens: resp.Usage.PromptTokens, OutputTokens: resp.Usage.CompletionTokens})
+		}
+		if len(resp.Choices) == 0 {
+			return spent(), fmt.Errorf("empty response from AI")
+		}
+
+		message := resp.Choices[0].Message
+		if len(message.ToolCalls) == 0 {
+			if message.Content == "" {
+				return spent(), fmt.Errorf("empty response from AI")
+			}
+			model := resp.Model
+			if model == "" {
+				model = p.modelName
+			}
+			return &LLMResponse{Text: message.Content, Model: model, Usage: usage}, nil
+		}
+
+		body.Messages = append(body.Messages, message)
+		for _, call := range message.ToolCalls {
+			args := map[string]interface{}{}
+			if call.Function.Arguments != "" {
+				if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+					args = map[string]interface{}{}
+				}
+			}
+			result, err := json.Marshal(req.CallTool(ctx, call.Function.Name, args))
+			if err != nil {
+				return spent(), err
+			}
+			body.Messages = append(body.Messages, openAIMessage{Role: "tool", Content: string(result), ToolCallID: call.ID})
+		}
+	}
+	return spent(), errToolRoundsExceeded
+}
+
+// Chat sends the system prompt, history and new message as one chat completion
+func (p *OpenAIProvider) Chat(ctx context.Context, req LLMChatRequest) (*LLMResponse, error) {
+	var messages []openAIMessage
+	if req.System != "" {
+		messages = append(messages, openAIMessage{Role: "system", Content: req.System})
+	}
+	for _, message := range req.History {
+		role := "user"
+		if message.Role == LLMRoleModel {
+			role = "assistant"
+		}
+		messages = append(messages, openAIMessage{Role: role, Content: message.Text})
+	}
+	messages = append(messages, openAIMessage{Role: "user", Content: req.Message})
+
+	resp, err := p.complete(ctx, openAIChatRequest{
+		Model:       p.modelName,
+		Messages:    messages,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		MaxTokens:   req.MaxTokens,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("AI chat failed: %w", err)
+	}
+	return resp, nil
+}
+
+// complete posts a non-streamed chat completion and reads the first choice; an empty
+// answer still returns its usage
+func (p *OpenAIProvider) complete(ctx context.Context, body openAIChatRequest) (*LLMResponse, error) {
+	var resp openAIChatResponse
+	if err := p.post(ctx, "/chat/completions", body, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &LLMResponse{Model: resp.Model}
+	if result.Model == "" {
+		result.Model = p.modelName
+	}
+	if resp.Usage != nil {
+		result.Usage = LLMUsage{PromptTokens: resp.Usage.PromptTokens, OutputTokens: resp.Usage.CompletionTokens}
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		return result, fmt.Errorf("empty response from AI")
+	}
+	result.Text = resp.Choices[0].Message.Content
+	return result, nil
+}
+
+// GenerateStream reads the server-sent delta stream of a chat completion. With
+// tools the answer is only known after the tool loop, so it arrives as one chunk.
+func (p *OpenAIProvider) GenerateStream(ctx context.Context, req LLMRequest, onChunk func(text string)) (*LLMResponse, error) {
+	if len(req.Tools) > 0 {
+		resp, err := p.generateWithTools(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		onChunk(resp.Text)
+		return resp, nil
+	}
+	body := p.buildRequest(req)
+	body.Stream = true
+
+	httpResp, err := p.send(ctx, "/chat/completions", body)
+	if err != nil {
+		return nil, fmt.Errorf("AI generation failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("AI generation failed: HTTP %d: %s", httpResp.StatusCode, http.StatusText(httpResp.StatusCode))
+	}
+
+	model := p.modelName
+	var full strings.Builder
+	scanner := bufio.NewScanner(httpResp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil || len(chunk.Choices) == 0 {
+			continue
+		}
+		if chunk.Model != "" {
+			model = chunk.Model
+		}
+		if text := chunk.Choices[0].Delta.Content; text != "" {
+			full.WriteString(text)
+			onChunk(text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		// Streams report no usage; the partial text is charged as an estimate
+		return &LLMResponse{Text: full.String(), Model: model}, fmt.Errorf("AI stream interrupted: %w", err)
+	}
+
+	if full.Len() == 0 {
+		return nil, fmt.Errorf("empty response from AI")
+	}
+	return &LLMResponse{Text: full.String(), Model: model}, nil
+}
+
+// buildRequest maps the neutral request onto the chat completions body
+func (p *OpenAIProvider) buildRequest(req LLMRequest) openAIChatRequest {
+	body := openAIChatRequest{
+		Model:       p.modelName,
+		Messages:    []openAIMessage{{Role: "user", Content: req.Prompt}},
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		MaxTokens:   req.MaxTokens,
+	}
+	if req.Schema != nil && len(req.Tools) == 0 {
+		body.ResponseFormat = map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"name":   "response",
+				"schema": toJSONSchema(req.Schema),
+			},
+		}
+	}
+	return body
+}
+
+// send posts a JSON payload and returns the raw HTTP response
+func (p *OpenAIProvider) send(ctx context.Context, path string, payload interface{}) (*http.Response, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "AlphaAgent/1.0")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	return p.client.Do(httpReq)
+}
+
+// post sends a JSON request and decodes a JSON response
+func (p *OpenAIProvider) post(ctx context.Context, path string, payload interface{}, out *openAIChatResponse) error {
+	httpResp, err := p.send(ctx, path, payload)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("HTTP %d: invalid response body", httpResp.StatusCode)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		if out.Error != nil {
+			return fmt.Errorf("HTTP %d: %s", httpResp.StatusCode, out.Error.Message)
+		}
+		return fmt.Errorf("HTTP %d: %s", httpResp.StatusCode, http.StatusText(httpResp.StatusCode))
+	}
+	return nil
+}
+
+// Close is a no-op; the HTTP client holds no long-lived resources
+func (p *OpenAIProvider) Close() error {
+	return nil
+}
+
+// toJSONSchema converts the neutral schema to a JSON Schema document
+func toJSONSchema(schema *LLMSchema) map[string]interface{} {
+	if schema == nil {
+		return nil
+	}
+
+	result := map[string]interface{}{"type": schema.Type}
+	if schema.Description != "" {
+		result["description"] = schema.Description
+	}
+	if len(schema.Enum) > 0 {
+		result["enum"] = schema.Enum
+	}
+	if schema.Items != nil {
+		result["items"] = toJSONSchema(schema.Items)
+	}
+	if len(schema.Properties) > 0 {
+		properties := make(map[string]interface{}, len(schema.Properties))
+		for name, property := range schema.Properties {
+			properties[name] = toJSONSchema(property)
+		}
+		result["properties"] = properties
+	}
+	if len(schema.Required) > 0 {
+		result["required"] = schema.Required
+	}
+	return result
+}
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"backend/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TemplateModelVersion identifies the rule set used by the template provider
+const TemplateModelVersion = "rule-based-v1"
+
+// TemplateProvider is a deterministic, offline provider that derives answers
+// from the structured request input with fixed rules. It never calls a network.
+type TemplateProvider struct{}
+
+// NewTemplateProvider creates the offline template provider
+func NewTemplateProvider() *TemplateProvider {
+	return &TemplateProvider{}
+}
+
+func (p *TemplateProvider) Name() string  { return ProviderTemplate }
+func (p *TemplateProvider) Model() string { return TemplateModelVersion }
+
+// Generate renders a rule-based answer for the request's structured input
+func (p *TemplateProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	var output interface{}
+
+	switch input := req.Input.(type) {
+	case *models.AnalysisContext:
+		output = templateTokenAnalysis(input)
+	case *models.ComparisonContext:
+		output = templateComparison(input)
+	case *models.BriefInputs:
+		output = templateBrief(input)
+	default:
+		return nil, fmt.Errorf("template provider cannot answer %T requests", req.Input)
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+	return &LLMResponse{Text: string(data), Model: TemplateModelVersion}, nil
+}
+
+// Chat answers follow-up questions with fixed rules keyed on the question's wording
+func (p *TemplateProvider) Chat(ctx context.Context, req LLMChatRequest) (*LLMResponse, error) {
+	cctx, ok := req.Input.(*models.ChatContext)
+	if !ok {
+		return nil, fmt.Errorf("template provider cannot answer %T chats", req.Input)
+	}
+	return &LLMResponse{Text: templateChatReply(cctx), Model: TemplateModelVersion}, nil
+}
+
+// GenerateStream emits the deterministic answer in fixed-size chunks
+func (p *TemplateProvider) GenerateStream(ctx context.Context, req LLMRequest, onChunk func(text string)) (*LLMResponse, error) {
+	resp, err := p.Generate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 256
+	for start := 0; start < len(resp.Text); start += chunkSize {
+		end := start + chunkSize
+		if end > len(resp.Text) {
+			end = len(resp.Text)
+		}
+		onChunk(resp.Text[start:end])
+	}
+	return resp, nil
+}
+
+// Close is a no-op
+func (p *TemplateProvider) Close() error {
+	return nil
+}
+
+// templateTokenAnalysis applies fixed rules to the token metrics
+func templateTokenAnalysis(actx *models.AnalysisContext) models.TokenAnalysis {
+	req := actx.Token
+	var liquidityRatio, volumeRatio float64
+	if req.MarketCap > 0 {
+		liquidityRatio = req.Liquidity / req.MarketCap
+		volumeRatio = req.Volume24h / req.MarketCap
+	}
+
+	// Trend from 7d/30d momentum
+	trend, strength := "Accumulation", "Neutral"
+	switch {
+	case req.Change7d > 10 && req.Change30d > 20:
+		trend, strength = "Uptrend", "Very Strong"
+	case req.Change7d > 3 && req.Change30d > 0:
+		trend, strength = "Uptrend", "Strong"
+	case req.Change7d < -10 && req.Change30d < -20:
+		trend, strength = "Downtrend", "Very Strong"
+	case req.Change7d < -3 && req.Change30d < 0:
+		trend, strength = "Downtrend", "Strong"
+	case req.Change30d > 15 && req.Change7d < 0:
+		trend, strength = "Distribution", "Weak"
+	}
+
+	// Risk from liquidity and trust score
+	var concerns []string
+	riskPoints := 0
+	if req.Liquidity > 0 && liquidityRatio < 0.01 {
+		riskPoints += 2
+		concerns = append(concerns, "Thin liquidity relative to market cap (<1%)")
+	}
+	if req.TrustScore < 50 {
+		riskPoints += 2
+		concerns = append(concerns, fmt.Sprintf("Low Alpha Trust Score (%.0f/100)", req.TrustScore))
+	}
+	if req.MaxSupply > 0 && req.CirculatingSupply/req.MaxSupply < 0.5 {
+		riskPoints++
+		concerns = append(concerns, "Less than half of max supply circulating - unlock/inflation risk")
+	}
+	if math.Abs(req.Change7d) > 25 {
+		riskPoints++
+		concerns = append(concerns, "High short-term volatility")
+	}
+	if len(concerns) == 0 {
+		concerns = append(concerns, "No major structural risks detected from available metrics")
+	}
+
+	riskLevel := "Low"
+	switch {
+	case riskPoints >= 5:
+		riskLevel = "Extreme"
+	case riskPoints >= 3:
+		riskLevel = "High"
+	case riskPoints >= 1:
+		riskLevel = "Medium"
+	}
+
+	// Action from trust score and trend
+	action := "WATCH"
+	switch {
+	case trend == "Uptrend" && req.TrustScore >= 70 && riskPoints <= 1:
+		action = "BUY ZONE"
+	case trend == "Downtrend" && req.TrustScore < 50:
+		action = "SELL"
+	case req.TrustScore >= 60:
+		action = "HOLD"
+	}
+
+	growthScore := math.Round(math.Min(math.Max(req.TrustScore*0.7+req.Change30d*0.3, 0), 100))
+
+	price := req.Price
+
+	sector := "Unclassified"
+	if req.Category != "" {
+		sector = req.Category
+	}
+	betaInsight := "Market beta not assessed - no daily history available"
+	if actx.History != nil {
+		betaInsight = fmt.Sprintf("Beta to BTC %.2f (correlation %.2f), beta to market %.2f",
+			actx.History.BetaBTC, actx.History.CorrelBTC, actx.History.BetaMarket)
+	}
+	return models.TokenAnalysis{
+		Summary: fmt.Sprintf("%s trades at $%.6g with a %s %s and %s risk (rule-based assessment).",
+			req.Symbol, price, strings.ToLower(strength), strings.ToLower(trend), strings.ToLower(riskLevel)),
+		GrowthPotential: models.GrowthPotential{
+			Score:  growthScore,
+			Reason: fmt.Sprintf("Trust score %.0f/100 combined with %.1f%% 30d performance.", req.TrustScore, req.Change30d),
+		},
+		TechnicalAnalysis: models.TechnicalAnalysis{
+			Trend:     trend,
+			Strength:  strength,
+			KeyLevels: fmt.Sprintf("Support ~$%.6g, resistance ~$%.6g", price*0.92, price*1.10),
+		},
+		RiskAnalysis: models.RiskAnalysis{
+			Level:    riskLevel,
+			Concerns: concerns,
+		},
+		FundamentalAnalysis: models.FundamentalAnalysis{
+			Sector:       sector,
+			Tokenomics:   templateTokenomics(req),
+			EconomicMoat: "Not assessed by rule-based provider",
+		},
+		Recommendation: models.Recommendation{
+			Action:    action,
+			EntryZone: fmt.Sprintf("$%.6g - $%.6g", price*0.95, price*0.98),
+			Target:    fmt.Sprintf("$%.6g", price*1.25),
+		},
+		TradingPlan: models.TradingPlan{
+			BuyStrategy: "DCA across the entry zone; add on a confirmed reclaim of resistance",
+			SellTargets: []string{
+				fmt.Sprintf("TP1: $%.6g", price*1.10),
+				fmt.Sprintf("TP2: $%.6g", price*1.25),
+				fmt.Sprintf("TP3: $%.6g", price*1.50),
+			},
+			StopLoss:    fmt.Sprintf("$%.6g", price*0.88),
+			TimeHorizon: "Mid-term",
+		},
+		Insights: []string{
+			fmt.Sprintf("Volume/Mcap velocity is %.2f%%", volumeRatio*100),
+			fmt.Sprintf("Price moved %.1f%% over 30d and %.1f%% over 90d", req.Change30d, req.Change90d),
+			betaInsight,
+		},
+	}
+}
+
+func templateTokenomics(req models.Token) string {
+	switch {
+	case req.MaxSupply == 0:
+		return "Uncapped supply"
+	case req.CirculatingSupply/req.MaxSupply >= 0.9:
+		return "Mostly circulating, limited dilution"
+	case req.CirculatingSupply/req.MaxSupply >= 0.5:
+		return "Moderate dilution ahead"
+	default:
+		return "High inflation / unlock overhang"
+	}
+}
+
+// comparisonMetric is one figure the template provider compares tokens on
+type comparisonMetric struct {
+	label          string
+	value          func(t models.Token) float64
+	higherIsBetter bool
+}
+
+var comparisonMetrics = []comparisonMetric{
+	{"Alpha Trust Score", func(t models.Token) float64 { return t.TrustScore }, true},
+	{"liquidity relative to market cap", func(t models.Token) float64 { return safeRatio(t.Liquidity, t.MarketCap) }, true},
+	{"volume velocity (Vol/Mcap)", func(t models.Token) float64 { return safeRatio(t.Volume24h, t.MarketCap) }, true},
+	{"30d momentum", func(t models.Token) float64 { return t.Change30d }, true},
+	{"sector relative strength", func(t models.Token) float64 { return t.ScoreBreakdown.RelativeStrengthScore }, true},
+	{"dilution (FDV/Mcap)", func(t models.Token) float64 { return safeRatio(t.FullyDilutedValue, t.MarketCap) }, false},
+}
+
+func safeRatio(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+// templateComparison ranks tokens by their rule-based growth score and lists
+// each token's best and worst metric relative to the group
+func templateComparison(cctx *models.ComparisonContext) models.TokenComparison {
+	type scored struct {
+		token    models.Token
+		analysis models.TokenAnalysis
+	}
+	entries := make([]scored, 0, len(cctx.Tokens))
+	for _, actx := range cctx.Tokens {
+		entries = append(entries, scored{token: actx.Token, analysis: templateTokenAnalysis(actx)})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].analysis.GrowthPotential.Score > entries[j].analysis.GrowthPotential.Score
+	})
+
+	var comparison models.TokenComparison
+	for i, entry := range entries {
+		var pros, cons []string
+		for _, metric := range comparisonMetrics {
+			value := metric.value(entry.token)
+			low, high := value, value
+			for _, other := range entries {
+				low = math.Min(low, metric.value(other.token))
+				high = math.Max(high, metric.value(other.token))
+			}
+			if low == high {
+				continue // no difference to report
+			}
+			best, worst := value == high, value == low
+			if !metric.higherIsBetter {
+				best, worst = worst, best
+			}
+			switch {
+			case best:
+				pros = append(pros, "Best "+metric.label+" in the group")
+			case worst:
+				cons = append(cons, "Weakest "+metric.label+" in the group")
+			}
+		}
+		if len(pros) == 0 {
+			pros = append(pros, "No metric leads the group")
+		}
+		if len(cons) == 0 {
+			cons = append(cons, "No metric trails the group")
+		}
+
+		comparison.Ranking = append(comparison.Ranking, models.ComparisonEntry{
+			Rank:    i + 1,
+			Symbol:  entry.token.Symbol,
+			Score:   entry.analysis.GrowthPotential.Score,
+			Pros:    pros,
+			Cons:    cons,
+			Verdict: fmt.Sprintf("%s: %s risk, rule-based action %s", entry.token.Symbol, strings.ToLower(entry.analysis.RiskAnalysis.Level), entry.analysis.Recommendation.Action),
+		})
+	}
+
+	top := entries[0]
+	comparison.Summary = fmt.Sprintf("%s ranks first of %d tokens on trust score and 30d momentum (rule-based assessment).", top.token.Symbol, len(entries))
+	comparison.Recommendation = models.ComparisonRecommendation{
+		Preferred:   top.token.Symbol,
+		Action:      top.analysis.Recommendation.Action,
+		Rationale:   top.analysis.GrowthPotential.Reason,
+		TimeHorizon: top.analysis.TradingPlan.TimeHorizon,
+	}
+	return comparison
+}
+
+// templateBrief summarizes the brief inputs without commentary beyond the numbers
+func templateBrief(inputs *models.BriefInputs) models.MarketBrief {
+	brief := models.MarketBrief{Sentiment: "Neutral"}
+
+	change := 0.0
+	if inputs.Market != nil {
+		change = inputs.Market.MarketCapChange24h
+		brief.Highlights = append(brief.Highlights, fmt.Sprintf("Total market cap $%.0f (%+.2f%% 24h), BTC dominance %.2f%%",
+			inputs.Market.TotalMarketCap, change, inputs.Market.BTCDominance))
+	}
+	switch {
+	case change >= 2:
+		brief.Sentiment = "Bullish"
+	case change <= -2:
+		brief.Sentiment = "Bearish"
+	}
+	brief.Headline = fmt.Sprintf("%s market session for %s", brief.Sentiment, inputs.Date)
+	brief.Summary = fmt.Sprintf("Total crypto market cap moved %+.2f%% over 24h. %d gainers and %d losers are tracked among large caps (rule-based brief).",
+		change, len(inputs.Gainers), len(inputs.Losers))
+
+	for _, m := range inputs.Gainers {
+		brief.Highlights = append(brief.Highlights, fmt.Sprintf("%s gained %+.2f%% in 24h", m.Symbol, m.Change24h))
+	}
+	for _, m := range inputs.Losers {
+		brief.Highlights = append(brief.Highlights, fmt.Sprintf("%s lost %+.2f%% in 24h", m.Symbol, m.Change24h))
+	}
+	if len(brief.Highlights) == 0 {
+		brief.Highlights = []string{"No market data available for today"}
+	}
+
+	for _, sector := range inputs.Sectors {
+		brief.Sectors = append(brief.Sectors, fmt.Sprintf("%s: median 7d %+.2f%%, median 30d %+.2f%%", sector.Category, sector.MedianReturn7d, sector.MedianReturn30d))
+	}
+	for _, m := range inputs.ScoreMovers {
+		brief.Watchlist = append(brief.Watchlist, fmt.Sprintf("%s: trust score %+.1f to %.1f (grade %s)", m.Symbol, m.ScoreChange, m.TrustScore, m.Grade))
+	}
+	for _, m := range inputs.Losers {
+		if m.TrustScore < 50 {
+			brief.Risks = append(brief.Risks, fmt.Sprintf("%s is falling with a low trust score (%.1f)", m.Symbol, m.TrustScore))
+		}
+	}
+	return brief
+}
+
+var chatPercentPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+
+// templateChatReply answers BTC what-if, stop loss, target and risk questions from the grounding data
+func templateChatReply(cctx *models.ChatContext) string {
+	token := cctx.Analysis.Token
+	question := strings.ToLower(cctx.Message)
+	var analysis *models.TokenAnalysis
+	if cctx.LastAnalysis != nil {
+		analysis = cctx.LastAnalysis.Analysis
+	}
+
+	switch {
+	case strings.Contains(question, "btc") || strings.Contains(question, "bitcoin"):
+		history := cctx.Analysis.History
+		match := chatPercentPattern.FindStringSubmatch(question)
+		if history == nil || match == nil {
+			break
+		}
+		move, _ := strconv.ParseFloat(match[1], 64)
+		for _, word := range []string{"drop", "fall", "down", "dump", "crash", "lose"} {
+			if strings.Contains(question, word) {
+				move = -move
+				break
+			}
+		}
+		expected := history.BetaBTC * move
+		return fmt.Sprintf("%s has a beta of %.2f to BTC (correlation %.2f). A %+.1f%% BTC move implies roughly %+.1f%% for %s, i.e. about $%.6g from $%.6g. The lower the correlation, the less reliable this estimate (rule-based answer).",
+			token.Symbol, history.BetaBTC, history.CorrelBTC, move, expected, token.Symbol, token.Price*(1+expected/100), token.Price)
+
+	case strings.Contains(question, "stop"):
+		if analysis != nil {
+			return fmt.Sprintf("The latest analysis places the stop loss at %s with %s risk. It invalidates the %s setup below that level (rule-based answer).",
+				analysis.TradingPlan.StopLoss, strings.ToLower(analysis.RiskAnalysis.Level), analysis.Recommendation.Action)
+		}
+		if indicators := cctx.Analysis.Indicators; indicators != nil && indicators.ATR14 > 0 {
+			return fmt.Sprintf("No analysis exists yet. A volatility-based stop two ATRs below the price would sit near $%.6g (ATR(14) $%.6g) (rule-based answer).",
+				token.Price-2*indicators.ATR14, indicators.ATR14)
+		}
+
+	case strings.Contains(question, "target") || strings.Contains(question, "take profit") || strings.Contains(question, "tp"):
+		if analysis != nil {
+			return fmt.Sprintf("The latest analysis targets %s with sell targets %s over a %s horizon (rule-based answer).",
+				analysis.Recommendation.Target, strings.Join(analysis.TradingPlan.SellTargets, "; "), strings.ToLower(analysis.TradingPlan.TimeHorizon))
+		}
+
+	case strings.Contains(question, "risk"):
+		if analysis != nil {
+			return fmt.Sprintf("Risk is rated %s: %s (rule-based answer).", analysis.RiskAnalysis.Level, strings.Join(analysis.RiskAnalysis.Concerns, "; "))
+		}
+	}
+
+	reply := fmt.Sprintf("%s trades at $%.6g (%+.2f%% 24h, %+.2f%% 7d) with an Alpha Trust Score of %.1f.",
+		token.Symbol, token.Price, token.Change24h, token.Change7d, token.TrustScore)
+	if analysis != nil {
+		reply += " Latest analysis: " + analysis.Summary
+	} else {
+		reply += " No analysis has been generated yet; run one for trade levels."
+	}
+	return reply + " (rule-based answer)"
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"backend/cache"
+	"backend/models"
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// TokensCacheKey is the token cache entry holding the full scored snapshot
+const TokensCacheKey = "tokens_all"
+
+// MarketData serves the current scored token snapshot to any handler or service
+type MarketData struct {
+	aggregator *Aggregator
+	scorer     *EnhancedScorer
+	cache      *cache.CacheManager
+	betas      func(symbol string) float64
+}
+
+// NewMarketData creates a new market data accessor
+func NewMarketData(aggregator *Aggregator, scorer *EnhancedScorer, cacheManager *cache.CacheManager) *MarketData {
+	return &MarketData{
+		aggregator: aggregator,
+		scorer:     scorer,
+		cache:      cacheManager,
+	}
+}
+
+// Tokens returns the cached scored snapshot, refreshing it on a miss
+func (m *MarketData) Tokens(ctx context.Context) ([]models.Token, error) {
+	if cached, found := m.cache.GetTokens(TokensCacheKey); found {
+		return cached.([]models.Token), nil
+	}
+
+	tokens, err := m.aggregator.FetchAllTokenData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Betas are set before scoring, so snapshot observers see them too
+	if m.betas != nil {
+		for i := range tokens {
+			tokens[i].BetaToMarket = m.betas(tokens[i].Symbol)
+		}
+	}
+	tokens = m.scorer.CalculateScoresForAll(tokens)
+	m.cache.SetTokens(TokensCacheKey, tokens)
+	return tokens, nil
+}
+
+// UseBetas sets the source of Token.BetaToMarket, applied to every new snapshot
+func (m *MarketData) UseBetas(betas func(symbol string) float64) {
+	m.betas = betas
+}
+
+// Run keeps the snapshot fresh while nobody is requesting it, so snapshot
+// observers (alerts) still see new data; a refresh only fetches once the cache expired
+func (m *MarketData) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := m.Tokens(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("⚠️  Background snapshot refresh failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FindToken returns a copy of the token matching an ID (slug), symbol or name
+func (m *MarketData) FindToken(ctx context.Context, id string) (models.Token, bool, error) {
+	tokens, err := m.Tokens(ctx)
+	if err != nil {
+		return models.Token{}, false, err
+	}
+
+	// Note: We use lowercase comparison for loose matching
+	normalizedId := strings.ToLower(id)
+	for _, token := range tokens {
+		if strings.ToLower(token.ID) == normalizedId || strings.ToLower(token.Symbol) == normalizedId || strings.ToLower(token.Name) == normalizedId {
+			return token, true, nil
+		}
+	}
+	return models.Token{}, false, nil
+}
@@ -0,0 +1,627 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/store"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	webhookCollection = "webhooks"
+	outboxCollection  = "webhook_outbox"
+
+	maxWebhooks            = 50
+	maxFinishedDeliveries  = 500 // delivered/failed entries kept for inspection
+	webhookMaxBackoff      = time.Hour
+	webhookPollInterval    = 5 * time.Second
+	webhookMaxResponseBody = 512 // bytes of an error response kept in LastError
+)
+
+// Webhook request headers
+const (
+	WebhookSignatureHeader = "X-AlphaAgent-Signature" // "sha256=" + hex HMAC of "<timestamp>.<body>"
+	WebhookTimestampHeader = "X-AlphaAgent-Timestamp" // unix seconds
+	WebhookEventHeader     = "X-AlphaAgent-Event"
+	WebhookDeliveryHeader  = "X-AlphaAgent-Delivery"
+)
+
+var (
+	// ErrInvalidWebhook wraps validation errors in a submitted webhook
+	ErrInvalidWebhook = errors.New("invalid webhook")
+	// ErrWebhookNotFound is returned for an unknown webhook ID
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+// Notifier delivers notification events to registered webhooks through a persistent
+// outbox. Failed deliveries are retried with exponential backoff; a webhook that keeps
+// failing is disabled.
+type Notifier struct {
+	store         *store.Store
+	client        *http.Client
+	maxAttempts   int
+	retryBase     time.Duration
+	disableAfter  int
+	gradeMaxRank  int
+	gradeCooldown time.Duration
+	allowedHosts  []string // exempt from the internal address block
+
+	mu       sync.Mutex
+	webhooks []models.Webhook
+	outbox   []models.WebhookDelivery // oldest first
+	grades   map[string]string        // token ID -> last notified (or first seen) grade
+	gradedAt map[string]time.Time     // token ID -> last grade change notification
+	wake     chan struct{}
+}
+
+// NewNotifier loads registered webhooks and undelivered events
+func NewNotifier(st *store.Store, cfg *config.Config) *Notifier {
+	n := &Notifier{
+		store:         st,
+		client:        &http.Client{Timeout: cfg.WebhookTimeout, Transport: webhookTransport(cfg.WebhookAllowedHosts)},
+		maxAttempts:   cfg.WebhookMaxAttempts,
+		retryBase:     cfg.WebhookRetryBase,
+		disableAfter:  cfg.WebhookDisableAfter,
+		gradeMaxRank:  cfg.GradeChangeMaxRank,
+		gradeCooldown: cfg.GradeChangeCooldown,
+		allowedHosts:  cfg.WebhookAllowedHosts,
+		grades:        make(map[string]string),
+		gradedAt:      make(map[string]time.Time),
+		wake:          make(chan struct{}, 1),
+	}
+
+	if _, err := st.Load(webhookCollection, &n.webhooks); err != nil {
+		log.Printf("⚠️  Failed to load webhooks, starting empty: %v", err)
+		n.webhooks = nil
+	}
+	if _, err := st.Load(outboxCollection, &n.outbox); err != nil {
+		log.Printf("⚠️  Failed to load webhook outbox, starting empty: %v", err)
+		n.outbox = nil
+	}
+	return n
+}
+
+// Create validates and registers a webhook; the returned copy carries the signing secret
+func (n *Notifier) Create(req models.WebhookRequest) (*models.Webhook, error) {
+	webhook := models.Webhook{
+		ID:        store.NewID(),
+		Name:      strings.TrimSpace(req.Name),
+		URL:       strings.TrimSpace(req.URL),
+		Format:    strings.ToLower(strings.TrimSpace(req.Format)),
+		Events:    req.Events,
+		ChatID:    strings.TrimSpace(req.ChatID),
+		Secret:    store.NewSecret("whsec_"),
+		Enabled:   true,
+		CreatedAt: time.Now(),
+	}
+	if webhook.Format == "" {
+		webhook.Format = models.WebhookFormatJSON
+	}
+	if err := validateWebhook(&webhook, n.allowedHosts); err != nil {
+		return nil, err
+	}
+	if webhook.Name == "" {
+		webhook.Name = webhook.URL
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.webhooks) >= maxWebhooks {
+		return nil, fmt.Errorf("%w: at most %d webhooks", ErrInvalidWebhook, maxWebhooks)
+	}
+	n.webhooks = append(n.webhooks, webhook)
+	if err := n.store.Save(webhookCollection, n.webhooks); err != nil {
+		n.webhooks = n.webhooks[:len(n.webhooks)-1]
+		return nil, err
+	}
+
+	log.Printf("📮 Webhook %s registered (%s, %s)", webhook.ID, webhook.Format, webhook.URL)
+	return &webhook, nil
+}
+
+func validateWebhook(webhook *models.Webhook, allowedHosts []string) error {
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	// Names are only resolved when sending, where the dialer applies the same block
+	if host := parsed.Hostname(); !webhookHostAllowed(allowedHosts, host) {
+		ip := net.ParseIP(host)
+		if (ip != nil && internalIP(ip)) || strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+			return fmt.Errorf("%w: url must not point at a loopback, private or link-local address", ErrInvalidWebhook)
+		}
+	}
+	if !containsString(models.WebhookFormats, webhook.Format) {
+		return fmt.Errorf("%w: format must be one of: %s", ErrInvalidWebhook, strings.Join(models.WebhookFormats, ", "))
+	}
+	if webhook.Format == models.WebhookFormatTelegram && webhook.ChatID == "" {
+		return fmt.Errorf("%w: chat_id is required for telegram webhooks", ErrInvalidWebhook)
+	}
+	for _, event := range webhook.Events {
+		if !containsString(models.NotificationEventTypes, event) {
+			return fmt.Errorf("%w: unknown event %q (events: %s)", ErrInvalidWebhook, event, strings.Join(models.NotificationEventTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// webhookTransport dials webhooks through a guard that refuses loopback, private and
+// link-local addresses once DNS has resolved them, so neither a hostname nor a redirect
+// can reach internal services or cloud metadata. Hosts in allowedHosts skip the guard.
+// Proxies are not used: they would connect on our behalf, past the guard.
+func webhookTransport(allowedHosts []string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+
+	direct := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	guarded := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: refuseInternalAddress}
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(address); err == nil && webhookHostAllowed(allowedHosts, host) {
+			return direct.DialContext(ctx, network, address)
+		}
+		return guarded.DialContext(ctx, network, address)
+	}
+	return transport
+}
+
+// refuseInternalAddress is a dialer Control hook; address is the resolved ip:port
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || internalIP(ip) {
+		return fmt.Errorf("webhook address %s is loopback, private or link-local", host)
+	}
+	return nil
+}
+
+// internalIP reports addresses webhooks may not reach, including 169.254.169.254 metadata
+func internalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+func webhookHostAllowed(allowedHosts []string, host string) bool {
+	for _, allowed := range allowedHosts {
+		if strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the registered webhooks without their secrets
+func (n *Notifier) List() []models.Webhook {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	result := make([]models.Webhook, 0, len(n.webhooks))
+	for _, webhook := range n.webhooks {
+		result = append(result, webhook.Redacted())
+	}
+	return result
+}
+
+// Delete removes a webhook and drops its pending deliveries
+func (n *Notifier) Delete(id string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	index := n.webhookIndex(id)
+	if index < 0 {
+		return ErrWebhookNotFound
+	}
+	n.webhooks = append(n.webhooks[:index:index], n.webhooks[index+1:]...)
+
+	kept := n.outbox[:0]
+	for _, delivery := range n.outbox {
+		if delivery.WebhookID != id {
+			kept = append(kept, delivery)
+		}
+	}
+	n.outbox = kept
+
+	log.Printf("📮 Webhook %s deleted", id)
+	n.saveOutbox()
+	return n.store.Save(webhookCollection, n.webhooks)
+}
+
+// Enable re-enables a webhook that was disabled after repeated failures
+func (n *Notifier) Enable(id string) (*models.Webhook, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	index := n.webhookIndex(id)
+	if index < 0 {
+		return nil, ErrWebhookNotFound
+	}
+	webhook := &n.webhooks[index]
+	webhook.Enabled = true
+	webhook.DisabledReason = ""
+	webhook.ConsecutiveFailures = 0
+	if err := n.store.Save(webhookCollection, n.webhooks); err != nil {
+		return nil, err
+	}
+	log.Printf("📮 Webhook %s re-enabled", id)
+	redacted := webhook.Redacted()
+	return &redacted, nil
+}
+
+// Deliveries returns up to limit outbox entries of a webhook, newest first
+func (n *Notifier) Deliveries(id string, limit int) ([]models.WebhookDelivery, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.webhookIndex(id) < 0 {
+		return nil, ErrWebhookNotFound
+	}
+	result := make([]models.WebhookDelivery, 0)
+	for i := len(n.outbox) - 1; i >= 0 && (limit <= 0 || len(result) < limit); i-- {
+		if n.outbox[i].WebhookID == id {
+			result = append(result, n.outbox[i])
+		}
+	}
+	return result, nil
+}
+
+// SendTest queues a test event for one webhook, even if it doesn't subscribe to it
+func (n *Notifier) SendTest(id string) (*models.WebhookDelivery, error) {
+	event := newNotificationEvent(models.EventWebhookTest, "Test notification from AlphaAgent", map[string]string{"webhook_id": id})
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	index := n.webhookIndex(id)
+	if index < 0 {
+		return nil, ErrWebhookNotFound
+	}
+	delivery := n.enqueue(&n.webhooks[index], event)
+	n.saveOutbox()
+	n.signal()
+	return &delivery, nil
+}
+
+// Notify queues an event for every enabled webhook subscribed to its type
+func (n *Notifier) Notify(eventType, summary string, data interface{}) {
+	event := newNotificationEvent(eventType, summary, data)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	queued := 0
+	for i := range n.webhooks {
+		webhook := &n.webhooks[i]
+		if !webhook.Enabled || (len(webhook.Events) > 0 && !containsString(webhook.Events, eventType)) {
+			continue
+		}
+		n.enqueue(webhook, event)
+		queued++
+	}
+	if queued == 0 {
+		return
+	}
+	n.saveOutbox()
+	n.signal()
+	log.Printf("📮 Queued %s for %d webhook(s): %s", eventType, queued, summary)
+}
+
+func newNotificationEvent(eventType, summary string, data interface{}) models.NotificationEvent {
+	return models.NotificationEvent{
+		ID:        store.NewID(),
+		Type:      eventType,
+		Summary:   summary,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+}
+
+// enqueue adds a pending delivery; callers hold n.mu and save the outbox
+func (n *Notifier) enqueue(webhook *models.Webhook, event models.NotificationEvent) models.WebhookDelivery {
+	now := time.Now()
+	delivery := models.WebhookDelivery{
+		ID:            store.NewID(),
+		WebhookID:     webhook.ID,
+		Event:         event,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	n.outbox = append(n.outbox, delivery)
+	return delivery
+}
+
+// signal wakes the delivery worker without blocking
+func (n *Notifier) signal() {
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due outbox entries until ctx is cancelled
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	for {
+		n.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-n.wake:
+		}
+	}
+}
+
+// deliverDue attempts every pending delivery whose retry time has come
+func (n *Notifier) deliverDue(ctx context.Context) {
+	type job struct {
+		delivery models.WebhookDelivery
+		webhook  models.Webhook
+	}
+
+	n.mu.Lock()
+	now := time.Now()
+	var jobs []job
+	for _, delivery := range n.outbox {
+		if delivery.Status != models.DeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		if index := n.webhookIndex(delivery.WebhookID); index >= 0 {
+			jobs = append(jobs, job{delivery: delivery, webhook: n.webhooks[index]})
+		}
+	}
+	n.mu.Unlock()
+
+	for _, j := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		statusCode, err := n.send(ctx, &j.webhook, &j.delivery)
+		n.recordAttempt(j.delivery.ID, statusCode, err)
+	}
+}
+
+// send POSTs one delivery, signed with the webhook secret
+func (n *Notifier) send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	body, err := formatWebhookPayload(webhook, delivery.Event)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "AlphaAgent-Webhooks/1.0")
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, body))
+	req.Header.Set(WebhookEventHeader, delivery.Event.Type)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBody))
+		return resp.StatusCode, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// recordAttempt updates a delivery and its webhook after an attempt
+func (n *Notifier) recordAttempt(deliveryID string, statusCode int, sendErr error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var delivery *models.WebhookDelivery
+	for i := range n.outbox {
+		if n.outbox[i].ID == deliveryID {
+			delivery = &n.outbox[i]
+			break
+		}
+	}
+	if delivery == nil {
+		return // webhook deleted meanwhile
+	}
+	index := n.webhookIndex(delivery.WebhookID)
+	if index < 0 {
+		return
+	}
+	webhook := &n.webhooks[index]
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	if sendErr == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		webhook.ConsecutiveFailures = 0
+		webhook.LastSuccessAt = &now
+		webhook.LastError = ""
+		log.Printf("📮 Delivered %s to webhook %s", delivery.Event.Type, webhook.ID)
+	} else {
+		delivery.LastError = sendErr.Error()
+		webhook.ConsecutiveFailures++
+		webhook.LastFailureAt = &now
+		webhook.LastError = sendErr.Error()
+
+		if delivery.Attempts >= n.maxAttempts {
+			delivery.Status = models.DeliveryFailed
+			log.Printf("❌ Giving up on %s for webhook %s after %d attempts: %v", delivery.Event.Type, webhook.ID, delivery.Attempts, sendErr)
+		} else {
+			delay := webhookBackoff(n.retryBase, delivery.Attempts)
+			delivery.NextAttemptAt = now.Add(delay)
+			log.Printf("⚠️  Webhook %s delivery failed (attempt %d/%d, retry in %v): %v", webhook.ID, delivery.Attempts, n.maxAttempts, delay, sendErr)
+		}
+
+		if webhook.Enabled && n.disableAfter > 0 && webhook.ConsecutiveFailures >= n.disableAfter {
+			webhook.Enabled = false
+			webhook.DisabledReason = fmt.Sprintf("disabled after %d consecutive failures: %s", webhook.ConsecutiveFailures, sendErr)
+			n.failPending(webhook.ID, "webhook disabled")
+			log.Printf("🚫 Webhook %s disabled after %d consecutive failures", webhook.ID, webhook.ConsecutiveFailures)
+		}
+	}
+
+	n.pruneOutbox()
+	n.saveOutbox()
+	if err := n.store.Save(webhookCollection, n.webhooks); err != nil {
+		log.Printf("⚠️  Failed to store webhooks: %v", err)
+	}
+}
+
+// webhookBackoff is retryBase doubled per failed attempt, capped at webhookMaxBackoff
+func webhookBackoff(retryBase time.Duration, attempts int) time.Duration {
+	delay := retryBase
+	for i := 1; i < attempts && delay < webhookMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxBackoff {
+		delay = webhookMaxBackoff
+	}
+	return delay
+}
+
+// failPending marks every pending delivery of a webhook as failed; callers hold n.mu
+func (n *Notifier) failPending(webhookID, reason string) {
+	for i := range n.outbox {
+		if n.outbox[i].WebhookID == webhookID && n.outbox[i].Status == models.DeliveryPending {
+			n.outbox[i].Status = models.DeliveryFailed
+			n.outbox[i].LastError = reason
+		}
+	}
+}
+
+// pruneOutbox keeps every pending entry and the most recent finished ones; callers hold n.mu
+func (n *Notifier) pruneOutbox() {
+	finished := 0
+	for _, delivery := range n.outbox {
+		if delivery.Status != models.DeliveryPending {
+			finished++
+		}
+	}
+	if finished <= maxFinishedDeliveries {
+		return
+	}
+	drop := finished - maxFinishedDeliveries
+	kept := n.outbox[:0]
+	for _, delivery := range n.outbox {
+		if drop > 0 && delivery.Status != models.DeliveryPending {
+			drop--
+			continue
+		}
+		kept = append(kept, delivery)
+	}
+	n.outbox = kept
+}
+
+// saveOutbox persists the outbox; callers hold n.mu
+func (n *Notifier) saveOutbox() {
+	if err := n.store.Save(outboxCollection, n.outbox); err != nil {
+		log.Printf("⚠️  Failed to store webhook outbox: %v", err)
+	}
+}
+
+// webhookIndex finds a webhook by ID; callers hold n.mu
+func (n *Notifier) webhookIndex(id string) int {
+	for i := range n.webhooks {
+		if n.webhooks[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// ObserveGrades notifies grade changes of top-ranked tokens between snapshots. A change is
+// measured against the last notified grade and at most once per token per cooldown, so a score
+// hovering at a grade boundary neither notifies every snapshot nor for flipping straight back.
+func (n *Notifier) ObserveGrades(tokens []models.Token) {
+	var changes []models.GradeChangeEvent
+	now := time.Now()
+	n.mu.Lock()
+	first := len(n.grades) == 0
+	for _, token := range tokens {
+		grade := token.ScoreBreakdown.Grade
+		if grade == "" {
+			continue
+		}
+		previous, seen := n.grades[token.ID]
+		if first || !seen {
+			n.grades[token.ID] = grade
+			continue
+		}
+		if previous == grade {
+			continue
+		}
+		if token.Rank <= 0 || token.Rank > n.gradeMaxRank {
+			n.grades[token.ID] = grade
+			continue
+		}
+		if last, ok := n.gradedAt[token.ID]; ok && now.Sub(last) < n.gradeCooldown {
+			continue // the baseline grade stays until the cooldown ends
+		}
+		n.grades[token.ID] = grade
+		n.gradedAt[token.ID] = now
+		changes = append(changes, models.GradeChangeEvent{
+			TokenID:       token.ID,
+			Symbol:        token.Symbol,
+			Name:          token.Name,
+			Rank:          token.Rank,
+			Grade:         grade,
+			PreviousGrade: previous,
+			TrustScore:    token.TrustScore,
+			Timestamp:     time.Now(),
+		})
+	}
+	n.mu.Unlock()
+
+	for _, change := range changes {
+		direction := "upgraded"
+		if gradeRank(change.Grade) < gradeRank(change.PreviousGrade) {
+			direction = "downgraded"
+		}
+		n.Notify(models.EventGradeChanged, fmt.Sprintf("%s (#%d) %s from %s to %s (trust score %.1f)",
+			change.Symbol, change.Rank, direction, change.PreviousGrade, change.Grade, change.TrustScore), change)
+	}
+}
+
+// SignWebhookPayload returns the signature header value for a payload sent at timestamp
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a signature header in constant time
+func VerifyWebhookSignature(secret, timestamp string, body []byte, signature string) bool {
+	expected := SignWebhookPayload(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/store"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestInternalIP(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true,
+		"0.0.0.0":         true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00::1":         true,
+		"::ffff:10.0.0.1": true,
+		"8.8.8.8":         false,
+		"2606:4700::1111": false,
+	}
+	for address, want := range cases {
+		if got := internalIP(net.ParseIP(address)); got != want {
+			t.Errorf("internalIP(%s) = %v, want %v", address, got, want)
+		}
+	}
+}
+
+func TestValidateWebhookRejectsInternalURLs(t *testing.T) {
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/hook",
+		"http://localhost/hook",
+		"http://10.0.0.5/hook",
+	} {
+		webhook := models.Webhook{URL: target, Format: models.WebhookFormatJSON}
+		if err := validateWebhook(&webhook, nil); !errors.Is(err, ErrInvalidWebhook) {
+			t.Errorf("%s: expected ErrInvalidWebhook, got %v", target, err)
+		}
+	}
+
+	webhook := models.Webhook{URL: "http://localhost:9000/hook", Format: models.WebhookFormatJSON}
+	if err := validateWebhook(&webhook, []string{"localhost"}); err != nil {
+		t.Errorf("allowed host rejected: %v", err)
+	}
+}
+
+func TestSendRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	host := mustHostname(t, server.URL)
+
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhook := &models.Webhook{ID: "w1", URL: server.URL, Format: models.WebhookFormatJSON, Secret: "s"}
+	delivery := &models.WebhookDelivery{ID: "d1", Event: newNotificationEvent(models.EventWebhookTest, "test", nil)}
+
+	blocked := NewNotifier(st, &config.Config{WebhookTimeout: time.Second})
+	if _, err := blocked.send(context.Background(), webhook, delivery); err == nil {
+		t.Fatal("delivery to a loopback address should be refused")
+	}
+
+	allowed := NewNotifier(st, &config.Config{WebhookTimeout: time.Second, WebhookAllowedHosts: []string{host}})
+	if status, err := allowed.send(context.Background(), webhook, delivery); err != nil || status != http.StatusNoContent {
+		t.Fatalf("allowed host: status %d, err %v", status, err)
+	}
+}
+
+func mustHostname(t *testing.T, rawURL string) string {
+	t.Helper()
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Hostname()
+}
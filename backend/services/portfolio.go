@@ -0,0 +1,442 @@
+package services
+
+import (
+	"backend/models"
+	"backend/store"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	portfolioCollection = "portfolios"
+	maxPortfolios       = 10000 // across all owners
+
+	uncategorizedSector = "Uncategorized"
+)
+
+var (
+	// ErrInvalidPortfolio wraps validation errors in a submitted portfolio or holding
+	ErrInvalidPortfolio = errors.New("invalid portfolio")
+	// ErrPortfolioNotFound is returned for an unknown ID or another owner's portfolio
+	ErrPortfolioNotFound = errors.New("portfolio not found")
+	// ErrPortfolioLimit is returned when the owner (or the server) has too many portfolios
+	ErrPortfolioLimit = errors.New("portfolio limit reached")
+)
+
+// PortfolioService stores portfolios per client and values them against the current snapshot
+type PortfolioService struct {
+	store      *store.Store
+	marketData *MarketData
+	scorer     *EnhancedScorer
+
+	mu         sync.Mutex
+	portfolios []models.Portfolio // creation order
+}
+
+// NewPortfolioService loads stored portfolios
+func NewPortfolioService(st *store.Store, marketData *MarketData, scorer *EnhancedScorer) *PortfolioService {
+	s := &PortfolioService{store: st, marketData: marketData, scorer: scorer}
+	if _, err := st.Load(portfolioCollection, &s.portfolios); err != nil {
+		log.Printf("⚠️  Failed to load portfolios, starting empty: %v", err)
+		s.portfolios = nil
+	}
+	return s
+}
+
+// Create stores a new portfolio for an owner
+func (s *PortfolioService) Create(ctx context.Context, owner string, req models.PortfolioRequest) (*models.Portfolio, error) {
+	name, holdings, err := s.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	portfolio := models.Portfolio{
+		ID:        store.NewID(),
+		Owner:     owner,
+		Name:      name,
+		Holdings:  holdings,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	owned := 0
+	for _, p := range s.portfolios {
+		if p.Owner == owner {
+			owned++
+		}
+	}
+	if owned >= models.MaxPortfoliosPerOwner || len(s.portfolios) >= maxPortfolios {
+		return nil, fmt.Errorf("%w (%d per client)", ErrPortfolioLimit, models.MaxPortfoliosPerOwner)
+	}
+	s.portfolios = append(s.portfolios, portfolio)
+	if err := s.store.Save(portfolioCollection, s.portfolios); err != nil {
+		s.portfolios = s.portfolios[:len(s.portfolios)-1]
+		return nil, err
+	}
+	return copyPortfolio(portfolio), nil
+}
+
+// List returns an owner's portfolios in creation order
+func (s *PortfolioService) List(owner string) []models.Portfolio {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]models.Portfolio, 0)
+	for _, p := range s.portfolios {
+		if p.Owner == owner {
+			result = append(result, *copyPortfolio(p))
+		}
+	}
+	return result
+}
+
+// Get returns one of an owner's portfolios
+func (s *PortfolioService) Get(owner, id string) (*models.Portfolio, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(owner, id)
+	if i < 0 {
+		return nil, ErrPortfolioNotFound
+	}
+	return copyPortfolio(s.portfolios[i]), nil
+}
+
+// Detail returns a portfolio valued against the current snapshot
+func (s *PortfolioService) Detail(ctx context.Context, owner, id string) (*models.PortfolioDetail, error) {
+	portfolio, err := s.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.marketData.Tokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PortfolioDetail{
+		Portfolio: *portfolio,
+		Valuation: s.Value(portfolio.Holdings, tokens),
+	}, nil
+}
+
+// ListDetails returns an owner's portfolios, each valued against the current snapshot
+func (s *PortfolioService) ListDetails(ctx context.Context, owner string) ([]models.PortfolioDetail, error) {
+	portfolios := s.List(owner)
+	details := make([]models.PortfolioDetail, 0, len(portfolios))
+	if len(portfolios) == 0 {
+		return details, nil
+	}
+	tokens, err := s.marketData.Tokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, portfolio := range portfolios {
+		details = append(details, models.PortfolioDetail{
+			Portfolio: portfolio,
+			Valuation: s.Value(portfolio.Holdings, tokens),
+		})
+	}
+	return details, nil
+}
+
+// Update replaces the name and holdings of a portfolio
+func (s *PortfolioService) Update(ctx context.Context, owner, id string, req models.PortfolioRequest) (*models.Portfolio, error) {
+	name, holdings, err := s.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.update(owner, id, func(p *models.Portfolio) error {
+		p.Name = name
+		p.Holdings = holdings
+		return nil
+	})
+}
+
+// AddHolding records a purchase: quantity and cost basis add to an existing position
+func (s *PortfolioService) AddHolding(ctx context.Context, owner, id string, req models.HoldingRequest) (*models.Portfolio, error) {
+	holdings, err := s.resolveHoldings(ctx, []models.HoldingRequest{req})
+	if err != nil {
+		return nil, err
+	}
+	added := holdings[0]
+	return s.update(owner, id, func(p *models.Portfolio) error {
+		for i := range p.Holdings {
+			if p.Holdings[i].TokenID == added.TokenID {
+				p.Holdings[i].Quantity += added.Quantity
+				p.Holdings[i].CostBasis += added.CostBasis
+				return nil
+			}
+		}
+		if len(p.Holdings) >= models.MaxPortfolioHoldings {
+			return fmt.Errorf("%w: at most %d holdings", ErrInvalidPortfolio, models.MaxPortfolioHoldings)
+		}
+		p.Holdings = append(p.Holdings, added)
+		return nil
+	})
+}
+
+// RemoveHolding drops a position (by stored token ID)
+func (s *PortfolioService) RemoveHolding(owner, id, tokenID string) (*models.Portfolio, error) {
+	return s.update(owner, id, func(p *models.Portfolio) error {
+		for i, holding := range p.Holdings {
+			if strings.EqualFold(holding.TokenID, tokenID) {
+				p.Holdings = append(p.Holdings[:i:i], p.Holdings[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: no holding of %s", ErrInvalidPortfolio, tokenID)
+	})
+}
+
+// Delete removes a portfolio
+func (s *PortfolioService) Delete(owner, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(owner, id)
+	if i < 0 {
+		return ErrPortfolioNotFound
+	}
+	s.portfolios = append(s.portfolios[:i:i], s.portfolios[i+1:]...)
+	return s.store.Save(portfolioCollection, s.portfolios)
+}
+
+// Value prices holdings at the aggregated token price. Totals, scores and exposures
+// cover priced holdings only; holdings missing from the snapshot are listed as unpriced.
+func (s *PortfolioService) Value(holdings []models.Holding, tokens []models.Token) models.PortfolioValuation {
+	index := indexTokens(tokens)
+	valuation := models.PortfolioValuation{
+		Sectors:  make([]models.SectorExposure, 0),
+		Holdings: make([]models.HoldingValuation, 0, len(holdings)),
+		ValuedAt: time.Now(),
+	}
+
+	var value24hAgo float64
+	for _, holding := range holdings {
+		hv := models.HoldingValuation{
+			TokenID:   holding.TokenID,
+			Quantity:  holding.Quantity,
+			CostBasis: holding.CostBasis,
+		}
+		token, ok := index[holding.TokenID]
+		if !ok {
+			valuation.Unpriced = append(valuation.Unpriced, holding.TokenID)
+			valuation.Holdings = append(valuation.Holdings, hv)
+			continue
+		}
+
+		hv.Symbol = token.Symbol
+		hv.Name = token.Name
+		hv.Category = token.Category
+		hv.Priced = true
+		hv.Price = token.Price
+		hv.Value = holding.Quantity * token.Price
+		hv.UnrealizedPnL = hv.Value - holding.CostBasis
+		if holding.CostBasis > 0 {
+			hv.PnLPercent = hv.UnrealizedPnL / holding.CostBasis * 100
+		}
+		hv.Change24h = token.Change24h
+		hv.TrustScore = token.TrustScore
+		hv.Grade = token.ScoreBreakdown.Grade
+		valuation.Holdings = append(valuation.Holdings, hv)
+
+		valuation.TotalValue += hv.Value
+		valuation.TotalCostBasis += holding.CostBasis
+		if token.Change24h > -100 {
+			value24hAgo += hv.Value / (1 + token.Change24h/100)
+		}
+	}
+
+	valuation.UnrealizedPnL = valuation.TotalValue - valuation.TotalCostBasis
+	if valuation.TotalCostBasis > 0 {
+		valuation.PnLPercent = valuation.UnrealizedPnL / valuation.TotalCostBasis * 100
+	}
+	if value24hAgo > 0 {
+		valuation.Change24hValue = valuation.TotalValue - value24hAgo
+		valuation.Change24hPercent = valuation.Change24hValue / value24hAgo * 100
+	}
+	if valuation.TotalValue <= 0 {
+		valuation.Concentration.Level = models.ConcentrationLow
+		return valuation
+	}
+
+	sectors := make(map[string]*models.SectorExposure)
+	weights := make([]float64, 0, len(valuation.Holdings))
+	for i := range valuation.Holdings {
+		hv := &valuation.Holdings[i]
+		if !hv.Priced {
+			continue
+		}
+		share := hv.Value / valuation.TotalValue
+		hv.Weight = share * 100
+		weights = append(weights, hv.Weight)
+
+		breakdown := index[hv.TokenID].ScoreBreakdown
+		score := &valuation.Score
+		score.LiquidityScore += share * breakdown.LiquidityScore
+		score.VolumeScore += share * breakdown.VolumeScore
+		score.TVLScore += share * breakdown.TVLScore
+		score.TrendScore += share * breakdown.TrendScore
+		score.MarketHealthScore += share * breakdown.MarketHealthScore
+		score.SocialScore += share * breakdown.SocialScore
+		score.RiskScore += share * breakdown.RiskScore
+		score.RelativeStrengthScore += share * breakdown.RelativeStrengthScore
+		score.TotalScore += share * hv.TrustScore
+		score.Confidence += share * breakdown.Confidence
+
+		category := hv.Category
+		if category == "" {
+			category = uncategorizedSector
+		}
+		sector, ok := sectors[category]
+		if !ok {
+			sector = &models.SectorExposure{Category: category}
+			sectors[category] = sector
+		}
+		sector.Value += hv.Value
+		sector.Holdings++
+		sector.TrustScore += hv.Value * hv.TrustScore // normalized below
+		if hv.Weight > valuation.Concentration.TopWeight {
+			valuation.Concentration.TopWeight = hv.Weight
+			valuation.Concentration.TopHolding = hv.Symbol
+		}
+	}
+	valuation.Score.Grade = s.scorer.assignGrade(valuation.Score.TotalScore)
+	valuation.TrustScore = valuation.Score.TotalScore
+
+	for _, sector := range sectors {
+		sector.Weight = sector.Value / valuation.TotalValue * 100
+		sector.TrustScore /= sector.Value
+		valuation.Sectors = append(valuation.Sectors, *sector)
+	}
+	sort.Slice(valuation.Sectors, func(i, j int) bool {
+		if valuation.Sectors[i].Value != valuation.Sectors[j].Value {
+			return valuation.Sectors[i].Value > valuation.Sectors[j].Value
+		}
+		return valuation.Sectors[i].Category < valuation.Sectors[j].Category
+	})
+
+	valuation.Concentration = holdingConcentration(weights, valuation.Concentration)
+	return valuation
+}
+
+// holdingConcentration fills in the index-based measures from holding weights (in %)
+func holdingConcentration(weights []float64, c models.Concentration) models.Concentration {
+	sort.Sort(sort.Reverse(sort.Float64Slice(weights)))
+	for i, w := range weights {
+		if i < 3 {
+			c.Top3Weight += w
+		}
+		c.HHI += w * w
+	}
+	if c.HHI > 0 {
+		c.EffectiveHoldings = 10000 / c.HHI
+	}
+	switch {
+	case c.HHI < 1500:
+		c.Level = models.ConcentrationLow
+	case c.HHI < 2500:
+		c.Level = models.ConcentrationModerate
+	default:
+		c.Level = models.ConcentrationHigh
+	}
+	return c
+}
+
+func (s *PortfolioService) validate(ctx context.Context, req models.PortfolioRequest) (string, []models.Holding, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", nil, fmt.Errorf("%w: name is required", ErrInvalidPortfolio)
+	}
+	holdings, err := s.resolveHoldings(ctx, req.Holdings)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(holdings) > models.MaxPortfolioHoldings {
+		return "", nil, fmt.Errorf("%w: at most %d holdings", ErrInvalidPortfolio, models.MaxPortfolioHoldings)
+	}
+	return name, holdings, nil
+}
+
+// resolveHoldings maps holding tokens to snapshot IDs and merges repeated tokens
+func (s *PortfolioService) resolveHoldings(ctx context.Context, reqs []models.HoldingRequest) ([]models.Holding, error) {
+	holdings := make([]models.Holding, 0, len(reqs))
+	if len(reqs) == 0 {
+		return holdings, nil
+	}
+	lookup, err := newTokenLookup(ctx, s.marketData)
+	if err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	for _, req := range reqs {
+		if req.Quantity <= 0 || req.CostBasis < 0 {
+			return nil, fmt.Errorf("%w: %s needs a positive quantity and a non-negative cost basis", ErrInvalidPortfolio, req.TokenID)
+		}
+		tokenID, ok := lookup.resolve(req.TokenID)
+		if !ok {
+			unknown = append(unknown, req.TokenID)
+			continue
+		}
+		merged := false
+		for i := range holdings {
+			if holdings[i].TokenID == tokenID {
+				holdings[i].Quantity += req.Quantity
+				holdings[i].CostBasis += req.CostBasis
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			holdings = append(holdings, models.Holding{TokenID: tokenID, Quantity: req.Quantity, CostBasis: req.CostBasis})
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: unknown tokens: %s", ErrInvalidPortfolio, strings.Join(unknown, ", "))
+	}
+	return holdings, nil
+}
+
+// update applies a change to a copy of an owner's portfolio and persists it
+func (s *PortfolioService) update(owner, id string, change func(p *models.Portfolio) error) (*models.Portfolio, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(owner, id)
+	if i < 0 {
+		return nil, ErrPortfolioNotFound
+	}
+	updated := copyPortfolio(s.portfolios[i])
+	if err := change(updated); err != nil {
+		return nil, err
+	}
+	updated.UpdatedAt = time.Now()
+
+	previous := s.portfolios[i]
+	s.portfolios[i] = *updated
+	if err := s.store.Save(portfolioCollection, s.portfolios); err != nil {
+		s.portfolios[i] = previous
+		return nil, err
+	}
+	return copyPortfolio(*updated), nil
+}
+
+// index finds an owner's portfolio; callers hold s.mu
+func (s *PortfolioService) index(owner, id string) int {
+	for i, p := range s.portfolios {
+		if p.ID == id && p.Owner == owner {
+			return i
+		}
+	}
+	return -1
+}
+
+func copyPortfolio(p models.Portfolio) *models.Portfolio {
+	p.Holdings = append([]models.Holding{}, p.Holdings...)
+	return &p
+}
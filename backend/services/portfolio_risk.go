@@ -0,0 +1,192 @@
+package services
+
+import (
+	"backend/models"
+	"backend/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+const (
+	// minRiskObservations is the fewest overlapping daily returns a risk report is computed from
+	minRiskObservations = 20
+	// cryptoTradingDays annualizes daily volatility; crypto trades every day
+	cryptoTradingDays = 365
+)
+
+// portfolioRiskConfidences are the VaR / expected shortfall confidence levels reported
+var portfolioRiskConfidences = []float64{0.95, 0.99}
+
+// ErrInsufficientHistory is returned when holdings share too little price history for risk statistics
+var ErrInsufficientHistory = errors.New("insufficient price history")
+
+// PortfolioRisk computes one-day VaR, expected shortfall, drawdown, volatility, beta to BTC and
+// holding correlations from daily closes over the window, holding today's weights constant
+func (a *MarketAnalytics) PortfolioRisk(ctx context.Context, portfolioID string, valuation models.PortfolioValuation, window int) (*models.PortfolioRisk, error) {
+	report := &models.PortfolioRisk{
+		PortfolioID: portfolioID,
+		WindowDays:  window,
+		HorizonDays: 1,
+		Holdings:    make([]models.HoldingRisk, 0),
+		GeneratedAt: time.Now(),
+	}
+
+	var holdings []models.HoldingValuation
+	var series []returnSeries
+	for _, hv := range valuation.Holdings {
+		if !hv.Priced || hv.Value <= 0 {
+			report.Missing = append(report.Missing, hv.TokenID)
+			continue
+		}
+		candles, err := a.history.GetDailyCandles(ctx, hv.Symbol, window+1)
+		if err != nil || len(candles) < 2 {
+			log.Printf("✗ %s history unavailable for portfolio risk: %v", hv.Symbol, err)
+			report.Missing = append(report.Missing, hv.TokenID)
+			continue
+		}
+		holdings = append(holdings, hv)
+		series = append(series, dailyReturns(candles))
+		report.Value += hv.Value
+	}
+	if len(holdings) == 0 {
+		return nil, fmt.Errorf("%w: no priced holding has daily history", ErrInsufficientHistory)
+	}
+	if valuation.TotalValue > 0 {
+		report.Coverage = report.Value / valuation.TotalValue * 100
+	}
+
+	// BTC is aligned with the holdings when the overlap allows; otherwise beta is left at 0
+	var dates []int64
+	var aligned [][]float64
+	var btc []float64
+	if btcCandles, err := a.history.GetDailyCandles(ctx, BenchmarkBTC, window+1); err == nil {
+		dates, aligned = alignReturns(append(series, dailyReturns(btcCandles))...)
+		if len(dates) >= minRiskObservations {
+			btc = aligned[len(aligned)-1]
+			aligned = aligned[:len(aligned)-1]
+		}
+	} else {
+		log.Printf("✗ %s history unavailable for portfolio beta: %v", BenchmarkBTC, err)
+	}
+	if btc == nil {
+		dates, aligned = alignReturns(series...)
+	}
+	if len(dates) < minRiskObservations {
+		return nil, fmt.Errorf("%w: holdings share %d daily returns, need %d", ErrInsufficientHistory, len(dates), minRiskObservations)
+	}
+	report.Observations = len(dates)
+
+	weights := make([]float64, len(holdings))
+	for i, hv := range holdings {
+		weights[i] = hv.Value / report.Value
+	}
+	returns := make([]float64, len(dates))
+	for t := range dates {
+		for i := range holdings {
+			returns[t] += weights[i] * aligned[i][t]
+		}
+	}
+
+	mean := utils.CalculateMean(returns)
+	stdDev := utils.CalculateStdDev(returns, mean)
+	report.MeanDailyReturn = mean * 100
+	report.VolatilityDaily = stdDev * 100
+	report.VolatilityAnnualized = stdDev * math.Sqrt(cryptoTradingDays) * 100
+
+	for _, confidence := range portfolioRiskConfidences {
+		estimate := models.VaREstimate{
+			Confidence:             confidence,
+			HistoricalPct:          utils.CalculateHistoricalVaR(returns, confidence) * 100,
+			ParametricPct:          utils.CalculateParametricVaR(mean, stdDev, confidence) * 100,
+			ExpectedShortfallPct:   utils.CalculateExpectedShortfall(returns, confidence) * 100,
+			ParametricShortfallPct: utils.CalculateParametricES(mean, stdDev, confidence) * 100,
+		}
+		estimate.HistoricalUSD = estimate.HistoricalPct / 100 * report.Value
+		estimate.ParametricUSD = estimate.ParametricPct / 100 * report.Value
+		estimate.ExpectedShortfallUSD = estimate.ExpectedShortfallPct / 100 * report.Value
+		report.VaR = append(report.VaR, estimate)
+	}
+
+	report.Drawdown = portfolioDrawdown(dates, returns)
+
+	if btc != nil {
+		report.BetaToBTC = utils.CalculateBeta(returns, btc)
+		report.CorrelationToBTC = utils.CalculateCorrelation(returns, btc)
+	}
+
+	variance := stdDev * stdDev
+	for i, hv := range holdings {
+		risk := models.HoldingRisk{
+			TokenID:                hv.TokenID,
+			Symbol:                 hv.Symbol,
+			Weight:                 weights[i] * 100,
+			VolatilityAnnualized:   utils.CalculateStdDev(aligned[i], utils.CalculateMean(aligned[i])) * math.Sqrt(cryptoTradingDays) * 100,
+			CorrelationToPortfolio: utils.CalculateCorrelation(aligned[i], returns),
+		}
+		if btc != nil {
+			risk.BetaToBTC = utils.CalculateBeta(aligned[i], btc)
+		}
+		// Euler allocation: contributions sum to 100% of portfolio variance
+		if variance > 0 {
+			risk.RiskContribution = weights[i] * utils.CalculateCovariance(aligned[i], returns) / variance * 100
+		}
+		report.Holdings = append(report.Holdings, risk)
+	}
+
+	report.Correlations = &models.CorrelationMatrix{
+		WindowDays:   window,
+		Observations: len(dates),
+		Matrix:       make([][]float64, len(holdings)),
+		GeneratedAt:  report.GeneratedAt,
+	}
+	for i, hv := range holdings {
+		report.Correlations.Symbols = append(report.Correlations.Symbols, hv.Symbol)
+		report.Correlations.Matrix[i] = make([]float64, len(holdings))
+		for j := range holdings {
+			if i == j {
+				report.Correlations.Matrix[i][j] = 1
+				continue
+			}
+			report.Correlations.Matrix[i][j] = utils.CalculateCorrelation(aligned[i], aligned[j])
+		}
+	}
+
+	return report, nil
+}
+
+// portfolioDrawdown finds the worst decline of the compounded return series.
+// Value k of the series is the close on dates[k-1]; value 0 is the close a day before dates[0].
+func portfolioDrawdown(dates []int64, returns []float64) models.DrawdownStats {
+	values := utils.CalculateCumulativeValues(returns)
+	valueDate := func(k int) time.Time {
+		if k == 0 {
+			return time.Unix(dates[0], 0).UTC().AddDate(0, 0, -1)
+		}
+		return time.Unix(dates[k-1], 0).UTC()
+	}
+
+	drawdown, peak, trough := utils.CalculateMaxDrawdown(values)
+	stats := models.DrawdownStats{
+		MaxDrawdownPct: drawdown * 100,
+		PeakDate:       valueDate(peak),
+		TroughDate:     valueDate(trough),
+	}
+	for k := trough + 1; k < len(values) && drawdown > 0; k++ {
+		if values[k] >= values[peak] {
+			recovered := valueDate(k)
+			stats.RecoveryDate = &recovered
+			break
+		}
+	}
+
+	high := values[0]
+	for _, v := range values {
+		high = math.Max(high, v)
+	}
+	stats.CurrentDrawdownPct = (high - values[len(values)-1]) / high * 100
+	return stats
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// riskDates returns n consecutive daily closes from 2025-01-02, as unix seconds
+func riskDates(n int) []int64 {
+	start := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	dates := make([]int64, n)
+	for i := range dates {
+		dates[i] = start.AddDate(0, 0, i).Unix()
+	}
+	return dates
+}
+
+func day(d int) time.Time {
+	return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPortfolioDrawdown(t *testing.T) {
+	tests := []struct {
+		name       string
+		returns    []float64
+		maxPct     float64
+		peak       time.Time
+		trough     time.Time
+		recovery   *time.Time
+		currentPct float64
+	}{
+		{
+			// values 1, 1.1, 0.55, 0.66, 1.32
+			name: "recovered", returns: []float64{0.1, -0.5, 0.2, 1},
+			maxPct: 50, peak: day(2), trough: day(3), recovery: timePtr(day(5)), currentPct: 0,
+		},
+		{
+			// values 1, 1.1, 0.55, 0.66
+			name: "still under water", returns: []float64{0.1, -0.5, 0.2},
+			maxPct: 50, peak: day(2), trough: day(3), currentPct: 40,
+		},
+		{
+			// values 1, 0.8, 0.88: the peak is the starting value, the day before the first return
+			name: "decline from the start", returns: []float64{-0.2, 0.1},
+			maxPct: 20, peak: day(1), trough: day(2), currentPct: 12,
+		},
+		{
+			name: "only gains", returns: []float64{0.01, 0.02},
+			maxPct: 0, peak: day(1), trough: day(1), currentPct: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := portfolioDrawdown(riskDates(len(tt.returns)), tt.returns)
+			if math.Abs(stats.MaxDrawdownPct-tt.maxPct) > 1e-9 {
+				t.Errorf("MaxDrawdownPct = %v, want %v", stats.MaxDrawdownPct, tt.maxPct)
+			}
+			if !stats.PeakDate.Equal(tt.peak) || !stats.TroughDate.Equal(tt.trough) {
+				t.Errorf("peak, trough = %v, %v, want %v, %v", stats.PeakDate, stats.TroughDate, tt.peak, tt.trough)
+			}
+			switch {
+			case tt.recovery == nil && stats.RecoveryDate != nil:
+				t.Errorf("RecoveryDate = %v, want none", *stats.RecoveryDate)
+			case tt.recovery != nil && (stats.RecoveryDate == nil || !stats.RecoveryDate.Equal(*tt.recovery)):
+				t.Errorf("RecoveryDate = %v, want %v", stats.RecoveryDate, *tt.recovery)
+			}
+			if math.Abs(stats.CurrentDrawdownPct-tt.currentPct) > 1e-9 {
+				t.Errorf("CurrentDrawdownPct = %v, want %v", stats.CurrentDrawdownPct, tt.currentPct)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// rateLimiterPruneSize is the bucket count above which idle (full) buckets are dropped
+const rateLimiterPruneSize = 10000
+
+// RateDecision is the outcome of one rate-limited request
+type RateDecision struct {
+	Allowed    bool
+	Limit      int // requests per minute, 0 = unlimited
+	Remaining  int
+	RetryAfter time.Duration // until the next request is allowed, when denied
+}
+
+// RateLimiter is a token bucket per caller: each holds up to a minute of requests and
+// refills continuously
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*rateBucket
+}
+
+type rateBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewRateLimiter creates an empty rate limiter
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{buckets: make(map[string]*rateBucket)}
+}
+
+// Allow spends one request from the caller's bucket, at perMinute requests per minute
+func (l *RateLimiter) Allow(caller string, perMinute int, now time.Time) RateDecision {
+	if perMinute <= 0 {
+		return RateDecision{Allowed: true}
+	}
+	capacity := float64(perMinute)
+	perSecond := capacity / 60
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	bucket, ok := l.buckets[caller]
+	if !ok {
+		if len(l.buckets) >= rateLimiterPruneSize {
+			l.pruneLocked(now)
+		}
+		bucket = &rateBucket{tokens: capacity, updated: now}
+		l.buckets[caller] = bucket
+	}
+	bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.updated).Seconds()*perSecond)
+	bucket.updated = now
+
+	decision := RateDecision{Limit: perMinute}
+	if bucket.tokens < 1 {
+		decision.RetryAfter = time.Duration((1 - bucket.tokens) / perSecond * float64(time.Second))
+		return decision
+	}
+	bucket.tokens--
+	decision.Allowed = true
+	decision.Remaining = int(bucket.tokens)
+	return decision
+}
+
+// pruneLocked drops buckets idle for a minute; they would have refilled completely
+func (l *RateLimiter) pruneLocked(now time.Time) {
+	for caller, bucket := range l.buckets {
+		if now.Sub(bucket.updated) >= time.Minute {
+			delete(l.buckets, caller)
+		}
+	}
+}
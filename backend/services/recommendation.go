@@ -0,0 +1,128 @@
+package services
+
+import (
+	"backend/models"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Horizon lengths used when replaying a recommendation
+var horizonDays = map[string]int{
+	"Short-term": 14,
+	"Mid-term":   60,
+	"Long-term":  180,
+}
+
+const defaultHorizonDays = 60
+
+var (
+	// "TP1:", "TP 2 -" style labels carry digits that aren't prices
+	targetLabelPattern = regexp.MustCompile(`(?i)\b(tp|target)\s*\d+\s*[:\-]?`)
+	priceNumberPattern = regexp.MustCompile(`(\d+(?:,\d{3})*(?:\.\d+)?|\.\d+)\s*([kKmMbB]\b|%)?`)
+)
+
+// parsePriceLevels extracts every price in a free-text level like "$0.95 - $1.02" or "TP2: 105k"
+func parsePriceLevels(text string) []float64 {
+	text = targetLabelPattern.ReplaceAllString(text, " ")
+
+	var prices []float64
+	for _, match := range priceNumberPattern.FindAllStringSubmatch(text, -1) {
+		if match[2] == "%" {
+			continue // percentages are not price levels
+		}
+		value, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+		if err != nil || value <= 0 {
+			continue
+		}
+		switch strings.ToLower(match[2]) {
+		case "k":
+			value *= 1e3
+		case "m":
+			value *= 1e6
+		case "b":
+			value *= 1e9
+		}
+		prices = append(prices, value)
+	}
+	return prices
+}
+
+// parsePlanLevels converts the free-text trading plan of an analysis into numeric levels,
+// as written: guardrails check every level, including nonsensical ones
+func parsePlanLevels(analysis *models.TokenAnalysis) models.ParsedRecommendation {
+	plan := models.ParsedRecommendation{
+		Action:      analysis.Recommendation.Action,
+		HorizonDays: defaultHorizonDays,
+	}
+	if days, ok := horizonDays[analysis.TradingPlan.TimeHorizon]; ok {
+		plan.HorizonDays = days
+	}
+
+	switch plan.Action {
+	case "BUY NOW", "BUY ZONE", "HOLD":
+		plan.Direction = "long"
+	case "SELL":
+		plan.Direction = "short"
+	default:
+		plan.Direction = "none"
+	}
+
+	// Levels are parsed for every action, so guardrails can range-check a WATCH plan too
+	entries := parsePriceLevels(analysis.Recommendation.EntryZone)
+	if len(entries) > 0 {
+		sort.Float64s(entries)
+		plan.EntryLow, plan.EntryHigh = entries[0], entries[len(entries)-1]
+	}
+
+	for _, target := range analysis.TradingPlan.SellTargets {
+		if levels := parsePriceLevels(target); len(levels) > 0 {
+			plan.Targets = append(plan.Targets, levels[0])
+		}
+	}
+	if len(plan.Targets) == 0 {
+		plan.Targets = parsePriceLevels(analysis.Recommendation.Target)
+	}
+
+	if stops := parsePriceLevels(analysis.TradingPlan.StopLoss); len(stops) > 0 {
+		plan.StopLoss = stops[0]
+	}
+	return plan
+}
+
+// ParseRecommendation converts the trading plan of an analysis into the levels the backtester
+// replays: targets on the losing side of the stop are dropped, the nearest target comes first
+func ParseRecommendation(analysis *models.TokenAnalysis) (models.ParsedRecommendation, error) {
+	plan := parsePlanLevels(analysis)
+	if plan.Direction == "none" {
+		return plan, nil
+	}
+
+	// Targets on the losing side of the stop are misparsed or nonsensical
+	if plan.StopLoss > 0 {
+		valid := make([]float64, 0, len(plan.Targets))
+		for _, target := range plan.Targets {
+			if (plan.Direction == "long" && target > plan.StopLoss) || (plan.Direction == "short" && target < plan.StopLoss) {
+				valid = append(valid, target)
+			}
+		}
+		plan.Targets = valid
+	}
+
+	// Nearest target first in the trade direction
+	if plan.Direction == "long" {
+		sort.Float64s(plan.Targets)
+	} else {
+		sort.Sort(sort.Reverse(sort.Float64Slice(plan.Targets)))
+	}
+
+	if len(plan.Targets) == 0 {
+		return plan, fmt.Errorf("no numeric take-profit level")
+	}
+	if plan.StopLoss == 0 {
+		return plan, fmt.Errorf("no numeric stop loss")
+	}
+	return plan, nil
+}
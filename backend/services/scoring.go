@@ -1,9 +1,10 @@
 package services
 
 import (
+	"backend/config"
 	"backend/models"
 	"backend/utils"
-	"math"
+	"sync"
 )
 
 // EnhancedScorer implements comprehensive 6-category scoring system
@@ -15,10 +16,27 @@ type EnhancedScorer struct {
 	trendWeight        float64 // 20%
 	marketHealthWeight float64 // 10%
 	riskWeight         float64 // 5%
+
+	// Stablecoin monitoring
+	depegBands   depegBands
+	depegMonitor *DepegMonitor
+
+	// Sector benchmarks from the latest scoring pass
+	mu                 sync.RWMutex
+	categoryBenchmarks []models.CategoryBenchmark
+
+	// Called with every freshly scored snapshot
+	observers []func(tokens []models.Token)
+}
+
+// scoringContext carries cross-token aggregates computed once per scoring pass
+type scoringContext struct {
+	stablecoinMarketCap float64
+	benchmarks          map[string]*models.CategoryBenchmark
 }
 
 // NewEnhancedScorer creates a new comprehensive scorer
-func NewEnhancedScorer() *EnhancedScorer {
+func NewEnhancedScorer(cfg *config.Config) *EnhancedScorer {
 	return &EnhancedScorer{
 		liquidityWeight:    25.0,
 		volumeWeight:       20.0,
@@ -26,19 +44,52 @@ func NewEnhancedScorer() *EnhancedScorer {
 		trendWeight:        20.0,
 		marketHealthWeight: 10.0,
 		riskWeight:         5.0,
+		depegBands:         newDepegBands(cfg),
+		depegMonitor:       NewDepegMonitor(),
 	}
 }
 
+// DepegMonitor exposes the scorer's stablecoin depeg monitor
+func (s *EnhancedScorer) DepegMonitor() *DepegMonitor {
+	return s.depegMonitor
+}
+
+// OnSnapshot registers an observer called after every scoring pass.
+// Observers run synchronously and must not modify the tokens.
+func (s *EnhancedScorer) OnSnapshot(observer func(tokens []models.Token)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observers = append(s.observers, observer)
+}
+
+// CategoryBenchmarks returns the sector benchmarks computed on the latest refresh
+func (s *EnhancedScorer) CategoryBenchmarks() []models.CategoryBenchmark {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.categoryBenchmarks
+}
+
 // CalculateComprehensiveScore computes all scoring components with dynamic weighting
 func (s *EnhancedScorer) CalculateComprehensiveScore(token *models.Token) models.DetailedScoreBreakdown {
+	return s.scoreToken(token, &scoringContext{})
+}
+
+// scoreToken picks the scoring model for a token and computes its breakdown
+func (s *EnhancedScorer) scoreToken(token *models.Token, mctx *scoringContext) models.DetailedScoreBreakdown {
+	if isStablecoin(token) {
+		return s.calculateStablecoinScore(token, mctx)
+	}
+
 	breakdown := models.DetailedScoreBreakdown{
+		Model:   "standard",
 		Details: models.ScoreDetails{},
 	}
 
 	// 1. Calculate raw scores (0-100 scale for each)
+	bench := mctx.benchmarks[token.Category]
 	lScore := s.calculateLiquidityScore(token, &breakdown.Details)          // raw 0-100
 	vScore := s.calculateVolumeScore(token, &breakdown.Details)             // raw 0-100
-	tvlScore := s.calculateTVLScore(token, &breakdown.Details)              // raw 0-100
+	tvlScore := s.calculateTVLScore(token, &breakdown.Details, bench)       // raw 0-100
 	trendScore := s.calculateTrendScore(token, &breakdown.Details)          // raw 0-100
 	mHealthScore := s.calculateMarketHealthScore(token, &breakdown.Details) // raw 0-100
 	riskScore := s.calculateRiskScore(token, &breakdown.Details)            // raw 0-100
@@ -88,6 +139,12 @@ func (s *EnhancedScorer) CalculateComprehensiveScore(token *models.Token) models
 		(mHealthScore/100.0)*weights["market"] +
 		(riskScore/100.0)*weights["risk"]
 
+	// Blend in sector-relative strength when the token has a sector to compare against
+	if bench != nil {
+		breakdown.RelativeStrengthScore = s.calculateRelativeStrength(token, bench, &breakdown.Details)
+		weightedTotal = weightedTotal*(1-relativeStrengthBlend) + breakdown.RelativeStrengthScore*relativeStrengthBlend
+	}
+
 	breakdown.TotalScore = weightedTotal
 
 	// Boost for Top-Tier tokens (Rank 1-50)
@@ -104,7 +161,7 @@ func (s *EnhancedScorer) CalculateComprehensiveScore(token *models.Token) models
 
 	// Assign grade and confidence
 	breakdown.Grade = s.assignGrade(breakdown.TotalScore)
-	breakdown.Confidence = s.calculateConfidence(token)
+	breakdown.Confidence = s.calculateConfidence(token, &breakdown.Coverage)
 
 	return breakdown
 }
@@ -261,7 +318,7 @@ func (s *EnhancedScorer) calculateVolumeGrowth(history models.VolumeHistory) flo
 }
 
 // 3. TVL Scoring (20%)
-func (s *EnhancedScorer) calculateTVLScore(token *models.Token, details *models.ScoreDetails) float64 {
+func (s *EnhancedScorer) calculateTVLScore(token *models.Token, details *models.ScoreDetails, bench *models.CategoryBenchmark) float64 {
 	var totalRaw float64
 
 	// A. TVL to Market Cap Ratio (50%)
@@ -271,6 +328,9 @@ func (s *EnhancedScorer) calculateTVLScore(token *models.Token, details *models.
 
 		var ratioScore float64
 		switch {
+		// Judge against the sector's own TVL norms when enough peers report TVL
+		case bench != nil && bench.TVLSampleSize >= minBenchmarkSize && bench.MedianTVLToMcap > 0:
+			ratioScore = s.scoreRelativeTVL(tvlRatio / bench.MedianTVLToMcap)
 		case tvlRatio >= 0.5: // Healthy (50%+)
 			ratioScore = 1.0
 		case tvlRatio >= 0.2: // Good
@@ -323,6 +383,19 @@ func (s *EnhancedScorer) calculateTVLScore(token *models.Token, details *models.
 	return totalRaw
 }
 
+func (s *EnhancedScorer) scoreRelativeTVL(relative float64) float64 {
+	switch {
+	case relative >= 2.0: // Twice the sector median
+		return 1.0
+	case relative >= 1.0:
+		return 0.9
+	case relative >= 0.5:
+		return 0.7
+	default:
+		return 0.4
+	}
+}
+
 func (s *EnhancedScorer) calculateTVLVolatility(history models.TVLHistory) float64 {
 	if len(history.Last30Days) < 30 {
 		return 50.0 // High uncertainty
@@ -622,40 +695,38 @@ func (s *EnhancedScorer) assignGrade(score float64) string {
 	}
 }
 
-func (s *EnhancedScorer) calculateConfidence(token *models.Token) float64 {
-	confidence := 100.0
-
-	// Deduct for missing data
-	if token.TVL == 0 {
-		confidence -= 15
-	}
-	if token.Liquidity == 0 {
-		confidence -= 15
-	}
-	if token.HolderCount == 0 {
-		confidence -= 10
-	}
-	if len(token.PriceHistory.Last30Days) < 30 {
-		confidence -= 10
-	}
-	if token.Volume24h < token.MarketCap*0.001 {
-		confidence -= 10
-	}
-	if token.ContractAge < 30 {
-		confidence -= 15
-	} else if token.ContractAge < 90 {
-		confidence -= 10
-	}
-
-	return math.Max(confidence, 0)
-}
-
 // CalculateScoresForAll calculates scores for all tokens using enhanced scorer
 func (s *EnhancedScorer) CalculateScoresForAll(tokens []models.Token) []models.Token {
+	mctx := s.buildScoringContext(tokens)
+
 	for i := range tokens {
-		breakdown := s.CalculateComprehensiveScore(&tokens[i])
+		breakdown := s.scoreToken(&tokens[i], mctx)
 		tokens[i].TrustScore = breakdown.TotalScore
 		tokens[i].ScoreBreakdown = breakdown
 	}
+
+	benchmarks := finalizeBenchmarks(mctx.benchmarks, tokens)
+	s.mu.Lock()
+	s.categoryBenchmarks = benchmarks
+	observers := s.observers
+	s.mu.Unlock()
+
+	s.depegMonitor.Observe(tokens)
+	for _, observe := range observers {
+		observe(tokens)
+	}
 	return tokens
 }
+
+// buildScoringContext computes the aggregates that per-token scoring compares against
+func (s *EnhancedScorer) buildScoringContext(tokens []models.Token) *scoringContext {
+	mctx := &scoringContext{
+		benchmarks: buildCategoryBenchmarks(tokens),
+	}
+	for i := range tokens {
+		if isStablecoin(&tokens[i]) {
+			mctx.stablecoinMarketCap += tokens[i].MarketCap
+		}
+	}
+	return mctx
+}
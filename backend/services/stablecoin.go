@@ -0,0 +1,306 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/utils"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Depeg band names, ordered by severity
+const (
+	DepegBandNormal   = "normal"
+	DepegBandWarning  = "warning"
+	DepegBandAlert    = "alert"
+	DepegBandCritical = "critical"
+	// DepegBandUnknown marks a stablecoin without a price; it never raises an event
+	DepegBandUnknown = "unknown"
+)
+
+// maxDepegEvents bounds the in-memory event log
+const maxDepegEvents = 500
+
+// nonUSDPegTags mark stablecoins pegged to something other than USD; without
+// an FX feed we can't measure their peg, so they stay on the standard model
+var nonUSDPegTags = []string{"eur-stablecoin", "gold", "commodity", "cny-stablecoin", "jpy-stablecoin"}
+
+// isStablecoin detects USD stablecoins from CMC tags or the merged category
+func isStablecoin(token *models.Token) bool {
+	found := strings.Contains(strings.ToLower(token.Category), "stablecoin")
+	for _, tag := range token.Tags {
+		tag = strings.ToLower(tag)
+		for _, excluded := range nonUSDPegTags {
+			if strings.Contains(tag, excluded) {
+				return false
+			}
+		}
+		if strings.Contains(tag, "stablecoin") {
+			found = true
+		}
+	}
+	return found
+}
+
+// calculateStablecoinScore scores a stablecoin on peg quality instead of trend and momentum
+func (s *EnhancedScorer) calculateStablecoinScore(token *models.Token, mctx *scoringContext) models.DetailedScoreBreakdown {
+	breakdown := models.DetailedScoreBreakdown{
+		Model:   "stablecoin",
+		Details: models.ScoreDetails{},
+	}
+	metrics := &models.StablecoinMetrics{PegTarget: 1.0}
+
+	// 1. Peg Deviation (40%) - a missing price is unknown, not a 100% depeg
+	if token.Price > 0 {
+		metrics.PegDeviationBps = (token.Price - metrics.PegTarget) / metrics.PegTarget * 10000
+		absDeviation := math.Abs(metrics.PegDeviationBps)
+		metrics.DepegBand = s.depegBands.classify(absDeviation)
+		metrics.PegStabilityScore = s.depegBands.stabilityScore(absDeviation)
+	} else {
+		metrics.DepegBand = DepegBandUnknown
+		metrics.PegStabilityScore = 50
+	}
+
+	// 2. Peg Deviation Volatility (15%)
+	metrics.DeviationVolatility = pegDeviationVolatility(token, metrics.PegTarget)
+	switch {
+	case metrics.DeviationVolatility < 0: // No series available
+		metrics.PegVolatilityScore = 50
+	case metrics.DeviationVolatility <= 5:
+		metrics.PegVolatilityScore = 100
+	case metrics.DeviationVolatility <= 20:
+		metrics.PegVolatilityScore = 80
+	case metrics.DeviationVolatility <= 50:
+		metrics.PegVolatilityScore = 50
+	default:
+		metrics.PegVolatilityScore = 20
+	}
+
+	// 3. Reserve & Market Cap Concentration (15%)
+	// Reserve attestations aren't sourced yet, so holder concentration stands in for them
+	if mctx.stablecoinMarketCap > 0 {
+		metrics.MarketCapShare = token.MarketCap / mctx.stablecoinMarketCap * 100
+	}
+	var shareScore float64
+	switch {
+	case metrics.MarketCapShare >= 10: // Systemic, deeply integrated
+		shareScore = 1.0
+	case metrics.MarketCapShare >= 1:
+		shareScore = 0.8
+	case metrics.MarketCapShare >= 0.1:
+		shareScore = 0.6
+	default:
+		shareScore = 0.4
+	}
+	holderScore := 0.5 // Unknown distribution
+	if token.Top10HoldersRatio > 0 {
+		holderScore = 1.0 - math.Min(token.Top10HoldersRatio, 100)/100
+	}
+	metrics.ConcentrationScore = (shareScore*0.7 + holderScore*0.3) * 100
+
+	// 4. Liquidity (20%) - reuse the standard liquidity and volume views
+	lScore := s.calculateLiquidityScore(token, &breakdown.Details)
+	vScore := s.calculateVolumeScore(token, &breakdown.Details)
+	metrics.LiquidityScore = lScore*0.5 + vScore*0.5
+
+	// 5. Market Health (10%)
+	mHealthScore := s.calculateMarketHealthScore(token, &breakdown.Details)
+	riskScore := s.calculateRiskScore(token, &breakdown.Details)
+
+	// Stablecoins don't trend; the trend slot carries peg stability for radar compatibility
+	breakdown.Details.ShortTermTrend = "pegged"
+	breakdown.Details.MediumTermTrend = "pegged"
+	breakdown.Details.LongTermTrend = "pegged"
+	if metrics.DepegBand != DepegBandNormal && metrics.DepegBand != DepegBandUnknown {
+		breakdown.Details.ShortTermTrend = "depegged"
+	}
+
+	breakdown.LiquidityScore = lScore
+	breakdown.VolumeScore = vScore
+	breakdown.TrendScore = metrics.PegStabilityScore
+	breakdown.MarketHealthScore = mHealthScore
+	breakdown.RiskScore = riskScore
+	breakdown.Stablecoin = metrics
+
+	breakdown.TotalScore = metrics.PegStabilityScore*0.40 +
+		metrics.PegVolatilityScore*0.15 +
+		metrics.ConcentrationScore*0.15 +
+		metrics.LiquidityScore*0.20 +
+		mHealthScore*0.10
+
+	// A depegged coin can't score well regardless of its other qualities
+	if metrics.DepegBand == DepegBandCritical {
+		breakdown.TotalScore = math.Min(breakdown.TotalScore, 40)
+	}
+
+	if breakdown.TotalScore > 100 {
+		breakdown.TotalScore = 100
+	}
+
+	breakdown.Grade = s.assignGrade(breakdown.TotalScore)
+	breakdown.Confidence = s.calculateConfidence(token, &breakdown.Coverage)
+
+	return breakdown
+}
+
+// pegDeviationVolatility returns the std dev (bps) of the deviation series, or -1 without data
+func pegDeviationVolatility(token *models.Token, peg float64) float64 {
+	series := token.PriceHistory.Last30Days
+	if len(series) < 2 {
+		series = token.Sparkline
+	}
+	if len(series) < 2 {
+		return -1
+	}
+
+	deviations := make([]float64, 0, len(series))
+	for _, price := range series {
+		if price > 0 {
+			deviations = append(deviations, (price-peg)/peg*10000)
+		}
+	}
+	if len(deviations) < 2 {
+		return -1
+	}
+
+	return utils.CalculateStdDev(deviations, utils.CalculateMean(deviations))
+}
+
+// depegBands holds the configured thresholds in basis points
+type depegBands struct {
+	warn     float64
+	alert    float64
+	critical float64
+}
+
+func newDepegBands(cfg *config.Config) depegBands {
+	return depegBands{
+		warn:     cfg.DepegWarnBps,
+		alert:    cfg.DepegAlertBps,
+		critical: cfg.DepegCriticalBps,
+	}
+}
+
+func (b depegBands) classify(absDeviationBps float64) string {
+	switch {
+	case absDeviationBps >= b.critical:
+		return DepegBandCritical
+	case absDeviationBps >= b.alert:
+		return DepegBandAlert
+	case absDeviationBps >= b.warn:
+		return DepegBandWarning
+	default:
+		return DepegBandNormal
+	}
+}
+
+// stabilityScore steps the peg stability score down through the configured bands;
+// a deviation under a fifth of the warning band counts as a tight peg
+func (b depegBands) stabilityScore(absDeviationBps float64) float64 {
+	switch {
+	case absDeviationBps <= b.warn/5:
+		return 100
+	case absDeviationBps < b.warn:
+		return 85
+	case absDeviationBps < b.alert:
+		return 60
+	case absDeviationBps < b.critical:
+		return 30
+	default:
+		return 0
+	}
+}
+
+// DepegMonitor tracks the band of each stablecoin and records transitions as events
+type DepegMonitor struct {
+	mu        sync.RWMutex
+	lastBands map[string]string // token ID -> band
+	events    []models.DepegEvent
+	observers []func(event models.DepegEvent)
+}
+
+// NewDepegMonitor creates an empty depeg monitor
+func NewDepegMonitor() *DepegMonitor {
+	return &DepegMonitor{
+		lastBands: make(map[string]string),
+	}
+}
+
+// OnEvent registers an observer called for every new depeg event
+func (m *DepegMonitor) OnEvent(observer func(event models.DepegEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observers = append(m.observers, observer)
+}
+
+// Observe compares each scored stablecoin to its previous band and returns new events
+func (m *DepegMonitor) Observe(tokens []models.Token) []models.DepegEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var raised []models.DepegEvent
+	for _, token := range tokens {
+		metrics := token.ScoreBreakdown.Stablecoin
+		// Without a price the band is unknown; keep the last known one
+		if metrics == nil || token.Price <= 0 || metrics.DepegBand == DepegBandUnknown {
+			continue
+		}
+
+		// Keyed by ID: different tokens can share a symbol
+		previous, seen := m.lastBands[token.ID]
+		m.lastBands[token.ID] = metrics.DepegBand
+
+		// First sighting of a healthy coin isn't an event; a coin already off-peg is
+		if !seen {
+			previous = DepegBandNormal
+		}
+		if previous == metrics.DepegBand {
+			continue
+		}
+
+		event := models.DepegEvent{
+			TokenID:         token.ID,
+			Symbol:          token.Symbol,
+			Name:            token.Name,
+			Price:           token.Price,
+			PegTarget:       metrics.PegTarget,
+			PegDeviationBps: metrics.PegDeviationBps,
+			Band:            metrics.DepegBand,
+			PreviousBand:    previous,
+			Timestamp:       time.Now(),
+		}
+		raised = append(raised, event)
+		log.Printf("⚠️  Depeg %s: %s %.1f bps (%s → %s)", event.Band, event.Symbol, event.PegDeviationBps, previous, event.Band)
+	}
+
+	m.events = append(m.events, raised...)
+	if len(m.events) > maxDepegEvents {
+		m.events = m.events[len(m.events)-maxDepegEvents:]
+	}
+
+	for _, event := range raised {
+		for _, observe := range m.observers {
+			observe(event)
+		}
+	}
+	return raised
+}
+
+// Events returns the most recent depeg events, newest first
+func (m *DepegMonitor) Events(limit int) []models.DepegEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if limit <= 0 || limit > len(m.events) {
+		limit = len(m.events)
+	}
+
+	result := make([]models.DepegEvent, 0, limit)
+	for i := len(m.events) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, m.events[i])
+	}
+	return result
+}
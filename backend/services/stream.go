@@ -0,0 +1,368 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/store"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidStream is returned for malformed subscription parameters
+	ErrInvalidStream = errors.New("invalid stream subscription")
+	// ErrStreamLimit is returned when the subscription cap is reached
+	ErrStreamLimit = errors.New("too many stream subscriptions")
+)
+
+// StreamMessage is one event to write to a live stream client
+type StreamMessage struct {
+	Event string
+	ID    string // resume token; empty for heartbeats
+	Data  interface{}
+}
+
+// LiveStream turns every scored snapshot into per-subscription diffs.
+// Subscriptions outlive their connection for the resume window, so a client
+// that reconnects with its last resume token receives only what it missed.
+type LiveStream struct {
+	cfg *config.Config
+
+	mu       sync.Mutex
+	seq      uint64
+	tokens   []models.Token
+	previous map[string]*models.Token // snapshot before tokens, for crosses/downgraded filters
+	subs     map[string]*streamSubscription
+}
+
+// streamSubscription is one client's view of the market, guarded by LiveStream.mu
+type streamSubscription struct {
+	id      string
+	ids     map[string]bool // lowercase IDs and symbols; empty = all tokens
+	filter  *compiledAlert
+	view    map[string]models.TokenTick // what the client holds after the latest update
+	seq     uint64                      // snapshot the view reflects
+	backlog []models.StreamUpdate
+	floor   uint64 // every update after this seq is still in the backlog
+
+	conn       *StreamConn
+	detachedAt time.Time
+}
+
+// StreamConn is a connection attached to a subscription
+type StreamConn struct {
+	sub    *streamSubscription
+	notify chan struct{} // holds at most one signal, so publishing never blocks
+	done   chan struct{} // closed when another connection resumes the subscription
+	cursor uint64        // last seq written to this connection
+}
+
+// Notify fires when updates are pending
+func (c *StreamConn) Notify() <-chan struct{} { return c.notify }
+
+// Done is closed when the subscription is taken over by a newer connection
+func (c *StreamConn) Done() <-chan struct{} { return c.done }
+
+// NewLiveStream creates a live stream hub; register Publish with EnhancedScorer.OnSnapshot
+func NewLiveStream(cfg *config.Config) *LiveStream {
+	return &LiveStream{
+		cfg:  cfg,
+		subs: make(map[string]*streamSubscription),
+	}
+}
+
+// Publish diffs a new scored snapshot into every subscription
+func (ls *LiveStream) Publish(tokens []models.Token) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ls.seq++
+	ls.previous = indexTokens(ls.tokens)
+	ls.tokens = tokens
+	now := time.Now()
+
+	for id, sub := range ls.subs {
+		if ls.expired(sub, now) {
+			delete(ls.subs, id)
+			continue
+		}
+		update := sub.apply(tokens, ls.previous, ls.seq, now)
+		sub.seq = ls.seq
+		if update == nil {
+			continue
+		}
+		sub.backlog = append(sub.backlog, *update)
+		if excess := len(sub.backlog) - ls.backlogSize(); excess > 0 {
+			sub.floor = sub.backlog[excess-1].Seq
+			sub.backlog = append([]models.StreamUpdate(nil), sub.backlog[excess:]...)
+		}
+		if sub.conn != nil {
+			select {
+			case sub.conn.notify <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Attach resumes the subscription named by a resume token, or starts a new one,
+// and returns the messages to write first
+func (ls *LiveStream) Attach(req models.StreamRequest, resume string) (*StreamConn, []StreamMessage, error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	now := time.Now()
+	for id, sub := range ls.subs {
+		if ls.expired(sub, now) {
+			delete(ls.subs, id)
+		}
+	}
+
+	if resume != "" {
+		if sub, seq, ok := ls.lookupResume(resume); ok {
+			conn := ls.connect(sub)
+			if seq < sub.floor {
+				conn.cursor = sub.seq
+				return conn, []StreamMessage{sub.snapshot(models.StreamReasonResumeExpired, now)}, nil
+			}
+			conn.cursor = seq
+			return conn, ls.pending(conn), nil
+		}
+	}
+
+	sub, err := newStreamSubscription(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if limit := ls.cfg.StreamMaxSubscriptions; limit > 0 && len(ls.subs) >= limit {
+		return nil, nil, ErrStreamLimit
+	}
+	sub.id = store.NewID()
+	sub.seq = ls.seq
+	sub.floor = ls.seq
+	for i := range ls.tokens {
+		t := &ls.tokens[i]
+		if sub.matches(t, ls.previous[t.ID]) {
+			sub.view[t.ID] = models.NewTokenTick(t)
+		}
+	}
+	ls.subs[sub.id] = sub
+
+	conn := ls.connect(sub)
+	conn.cursor = sub.seq
+	reason := models.StreamReasonInitial
+	if resume != "" {
+		reason = models.StreamReasonResumeExpired
+	}
+	return conn, []StreamMessage{sub.snapshot(reason, now)}, nil
+}
+
+// Pending returns the messages a connection has not written yet. A connection that fell
+// behind the backlog gets a fresh snapshot instead of the updates it can no longer replay.
+func (ls *LiveStream) Pending(conn *StreamConn) []StreamMessage {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if conn.sub.conn != conn {
+		return nil
+	}
+	if conn.cursor < conn.sub.floor {
+		conn.cursor = conn.sub.seq
+		return []StreamMessage{conn.sub.snapshot(models.StreamReasonLagged, time.Now())}
+	}
+	return ls.pending(conn)
+}
+
+// Detach marks a connection closed; its subscription stays resumable for the resume window
+func (ls *LiveStream) Detach(conn *StreamConn) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if conn.sub.conn == conn {
+		conn.sub.conn = nil
+		conn.sub.detachedAt = time.Now()
+	}
+}
+
+// Heartbeat describes the current stream position
+func (ls *LiveStream) Heartbeat() StreamMessage {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return StreamMessage{
+		Event: models.StreamEventHeartbeat,
+		Data:  models.StreamHeartbeat{Seq: ls.seq, Timestamp: time.Now()},
+	}
+}
+
+// Subscriptions returns the number of live and resumable subscriptions
+func (ls *LiveStream) Subscriptions() (connected, detached int) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	for _, sub := range ls.subs {
+		if sub.conn != nil {
+			connected++
+		} else {
+			detached++
+		}
+	}
+	return connected, detached
+}
+
+func (ls *LiveStream) connect(sub *streamSubscription) *StreamConn {
+	if sub.conn != nil {
+		close(sub.conn.done)
+	}
+	conn := &StreamConn{
+		sub:    sub,
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	sub.conn = conn
+	return conn
+}
+
+// pending returns the backlog after the connection's cursor; callers hold ls.mu
+func (ls *LiveStream) pending(conn *StreamConn) []StreamMessage {
+	var messages []StreamMessage
+	for _, update := range conn.sub.backlog {
+		if update.Seq > conn.cursor {
+			messages = append(messages, StreamMessage{Event: models.StreamEventUpdate, ID: update.Resume, Data: update})
+		}
+	}
+	conn.cursor = conn.sub.seq
+	return messages
+}
+
+// lookupResume parses "<subscription>.<seq>"; callers hold ls.mu
+func (ls *LiveStream) lookupResume(token string) (*streamSubscription, uint64, bool) {
+	id, rawSeq, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, 0, false
+	}
+	seq, err := strconv.ParseUint(rawSeq, 10, 64)
+	if err != nil {
+		return nil, 0, false
+	}
+	sub, ok := ls.subs[id]
+	if !ok || seq > sub.seq {
+		return nil, 0, false
+	}
+	return sub, seq, true
+}
+
+func (ls *LiveStream) expired(sub *streamSubscription, now time.Time) bool {
+	return sub.conn == nil && now.Sub(sub.detachedAt) > ls.cfg.StreamResumeWindow
+}
+
+func (ls *LiveStream) backlogSize() int {
+	if ls.cfg.StreamBacklog > 0 {
+		return ls.cfg.StreamBacklog
+	}
+	return 1
+}
+
+func newStreamSubscription(req models.StreamRequest) (*streamSubscription, error) {
+	sub := &streamSubscription{
+		ids:  make(map[string]bool),
+		view: make(map[string]models.TokenTick),
+	}
+	for _, id := range strings.Split(req.IDs, ",") {
+		if id = strings.ToLower(strings.TrimSpace(id)); id != "" {
+			sub.ids[id] = true
+		}
+	}
+	if len(sub.ids) > models.MaxStreamTokenIDs {
+		return nil, fmt.Errorf("%w: at most %d token IDs", ErrInvalidStream, models.MaxStreamTokenIDs)
+	}
+	if filter := strings.TrimSpace(req.Filter); filter != "" {
+		if len(filter) > models.MaxAlertExpressionLength {
+			return nil, fmt.Errorf("%w: filter longer than %d characters", ErrInvalidStream, models.MaxAlertExpressionLength)
+		}
+		compiled, err := parseTokenFilter(filter)
+		if err != nil {
+			return nil, fmt.Errorf("%w: filter: %v", ErrInvalidStream, err)
+		}
+		sub.filter = compiled
+	}
+	return sub, nil
+}
+
+func (sub *streamSubscription) matches(cur, prev *models.Token) bool {
+	if len(sub.ids) > 0 && !sub.ids[strings.ToLower(cur.ID)] && !sub.ids[strings.ToLower(cur.Symbol)] {
+		return false
+	}
+	return sub.filter == nil || sub.filter.matches(cur, prev)
+}
+
+// apply moves the view to a new snapshot and returns the diff, or nil when nothing changed
+func (sub *streamSubscription) apply(tokens []models.Token, previous map[string]*models.Token, seq uint64, now time.Time) *models.StreamUpdate {
+	update := &models.StreamUpdate{Seq: seq, Timestamp: now}
+	seen := make(map[string]bool, len(sub.view))
+	for i := range tokens {
+		t := &tokens[i]
+		if seen[t.ID] || !sub.matches(t, previous[t.ID]) {
+			continue
+		}
+		seen[t.ID] = true
+		tick := models.NewTokenTick(t)
+		if old, ok := sub.view[t.ID]; !ok {
+			update.Added = append(update.Added, tick)
+		} else if diff, changed := models.DiffTokenTicks(old, tick); changed {
+			update.Changed = append(update.Changed, diff)
+		}
+		sub.view[t.ID] = tick
+	}
+	for id := range sub.view {
+		if !seen[id] {
+			update.Removed = append(update.Removed, id)
+			delete(sub.view, id)
+		}
+	}
+	if len(update.Added) == 0 && len(update.Changed) == 0 && len(update.Removed) == 0 {
+		return nil
+	}
+	sort.Strings(update.Removed)
+	update.Resume = streamResumeToken(sub.id, seq)
+	return update
+}
+
+// snapshot renders the whole view, ordered by rank
+func (sub *streamSubscription) snapshot(reason string, now time.Time) StreamMessage {
+	ticks := make([]models.TokenTick, 0, len(sub.view))
+	for _, tick := range sub.view {
+		ticks = append(ticks, tick)
+	}
+	sort.Slice(ticks, func(i, j int) bool {
+		if ticks[i].Rank != ticks[j].Rank {
+			return ticks[i].Rank < ticks[j].Rank
+		}
+		return ticks[i].ID < ticks[j].ID
+	})
+	resume := streamResumeToken(sub.id, sub.seq)
+	return StreamMessage{
+		Event: models.StreamEventSnapshot,
+		ID:    resume,
+		Data: models.StreamSnapshot{
+			Resume:    resume,
+			Seq:       sub.seq,
+			Reason:    reason,
+			Tokens:    ticks,
+			Timestamp: now,
+		},
+	}
+}
+
+func streamResumeToken(subID string, seq uint64) string {
+	return subID + "." + strconv.FormatUint(seq, 10)
+}
+
+func indexTokens(tokens []models.Token) map[string]*models.Token {
+	index := make(map[string]*models.Token, len(tokens))
+	for i := range tokens {
+		index[tokens[i].ID] = &tokens[i]
+	}
+	return index
+}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"backend/config"
+	"backend/models"
+	"backend/store"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	usageCollection = "ai_usage"
+	usageRetainDays = 90
+
+	// SystemUsageClient is the client of background jobs; it is exempt from per-client budgets
+	SystemUsageClient = "system"
+)
+
+// defaultModelPrices are estimated list prices (USD per million tokens); AI_MODEL_PRICES overrides them
+var defaultModelPrices = map[string]models.ModelPrice{
+	DefaultGeminiModel:   {InputPerMillion: 0.50, OutputPerMillion: 3.00},
+	"gemini-2.5-flash":   {InputPerMillion: 0.30, OutputPerMillion: 2.50},
+	"gemini-2.5-pro":     {InputPerMillion: 1.25, OutputPerMillion: 10.00},
+	"gpt-4o-mini":        {InputPerMillion: 0.15, OutputPerMillion: 0.60},
+	"gpt-4o":             {InputPerMillion: 2.50, OutputPerMillion: 10.00},
+	TemplateModelVersion: {},
+}
+
+// BudgetError is returned when a daily AI budget is exhausted
+type BudgetError struct {
+	Scope      string // "global" or the client ID
+	Limit      string // "tokens" or "requests"
+	RetryAfter time.Duration
+}
+
+func (e *BudgetError) Error() string {
+	if e.Scope == "global" {
+		return fmt.Sprintf("daily AI %s budget exhausted", e.Limit)
+	}
+	return fmt.Sprintf("daily AI %s budget exhausted for client %s", e.Limit, e.Scope)
+}
+
+type usageClientKey struct{}
+
+// WithUsageClient tags a context with the client AI usage is charged to
+func WithUsageClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, usageClientKey{}, client)
+}
+
+// UsageClient returns the client a context is charged to (SystemUsageClient if untagged)
+func UsageClient(ctx context.Context) string {
+	if client, ok := ctx.Value(usageClientKey{}).(string); ok && client != "" {
+		return client
+	}
+	return SystemUsageClient
+}
+
+// UsageMeter records AI token usage per day, client and model and enforces daily budgets
+type UsageMeter struct {
+	store  *store.Store
+	limits models.UsageLimits
+	prices map[string]models.ModelPrice
+
+	mu   sync.Mutex
+	days map[string]*models.UsageDay // date -> usage
+}
+
+// NewUsageMeter loads stored usage and the configured budgets and prices
+func NewUsageMeter(st *store.Store, cfg *config.Config) *UsageMeter {
+	m := &UsageMeter{
+		store: st,
+		limits: models.UsageLimits{
+			DailyTokens:         cfg.AIDailyTokenBudget,
+			DailyRequests:       cfg.AIDailyRequestBudget,
+			ClientDailyTokens:   cfg.AIClientDailyTokenBudget,
+			ClientDailyRequests: cfg.AIClientDailyRequestBudget,
+		},
+		prices: make(map[string]models.ModelPrice),
+		days:   make(map[string]*models.UsageDay),
+	}
+
+	for model, price := range defaultModelPrices {
+		m.prices[model] = price
+	}
+	for model, price := range parseModelPrices(cfg.AIModelPrices) {
+		m.prices[model] = price
+	}
+
+	if _, err := st.Load(usageCollection, &m.days); err != nil {
+		log.Printf("⚠️  Failed to load AI usage, starting empty: %v", err)
+		m.days = make(map[string]*models.UsageDay)
+	}
+	return m
+}
+
+// Check returns a *BudgetError if the client or the global budget is exhausted for today
+func (m *UsageMeter) Check(client string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.checkLocked(client, true)
+}
+
+// UsageReservation is one metered LLM call. Its request is counted when it is reserved and
+// its tokens as the provider reports them, so concurrent calls and later tool rounds see the
+// spend of earlier ones.
+type UsageReservation struct {
+	meter  *UsageMeter
+	client string
+	model  string // priced until the call reports the model that answered
+
+	charged LLMUsage // tokens already added to today's totals
+	cost    float64
+}
+
+// Reserve checks the budgets and counts one request of the client under the same lock,
+// so concurrent calls cannot all pass the request budget
+func (m *UsageMeter) Reserve(client, model string) (*UsageReservation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkLocked(client, true); err != nil {
+		return nil, err
+	}
+	day := m.dayLocked(time.Now())
+	day.Total.Requests++
+	usageEntry(day.Clients, client).Requests++
+	return &UsageReservation{meter: m, client: client, model: model}, nil
+}
+
+// Spend charges the tokens of the call so far (cumulative) and returns a *BudgetError
+// if the token budgets are now exhausted; tool loops call it between rounds
+func (r *UsageReservation) Spend(spent LLMUsage) error {
+	m := r.meter
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r.chargeLocked(r.model, spent)
+	return m.checkLocked(r.client, false)
+}
+
+// Finish charges the call's final usage and attributes it to the model that answered
+func (r *UsageReservation) Finish(model string, spent LLMUsage) {
+	m := r.meter
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r.chargeLocked(model, spent)
+
+	totals := usageEntry(m.dayLocked(time.Now()).Models, model)
+	totals.Requests++
+	totals.PromptTokens += r.charged.PromptTokens
+	totals.OutputTokens += r.charged.OutputTokens
+	totals.CostUSD += r.cost
+
+	if err := m.store.Save(usageCollection, m.days); err != nil {
+		log.Printf("⚠️  Failed to save AI usage: %v", err)
+	}
+}
+
+// chargeLocked adds the difference between spent and what was already charged to today's
+// global and client totals; callers hold mu
+func (r *UsageReservation) chargeLocked(model string, spent LLMUsage) {
+	price := r.meter.price(model)
+	cost := (float64(spent.PromptTokens)*price.InputPerMillion + float64(spent.OutputTokens)*price.OutputPerMillion) / 1e6
+	day := r.meter.dayLocked(time.Now())
+	for _, totals := range []*models.UsageTotals{&day.Total, usageEntry(day.Clients, r.client)} {
+		totals.PromptTokens += spent.PromptTokens - r.charged.PromptTokens
+		totals.OutputTokens += spent.OutputTokens - r.charged.OutputTokens
+		totals.CostUSD += cost - r.cost
+	}
+	r.charged, r.cost = spent, cost
+}
+
+// checkLocked returns a *BudgetError for an exhausted budget; request budgets are only
+// checked before a call is reserved. Callers hold mu.
+func (m *UsageMeter) checkLocked(client string, requests bool) error {
+	now := time.Now()
+	day := m.dayLocked(now)
+	retryAfter := time.Until(nextUsageReset(now))
+
+	requestLimit, clientRequestLimit := 0, 0
+	if requests {
+		requestLimit, clientRequestLimit = m.limits.DailyRequests, m.limits.ClientDailyRequests
+	}
+	if limit := exhausted(&day.Total, m.limits.DailyTokens, requestLimit); limit != "" {
+		return &BudgetError{Scope: "global", Limit: limit, RetryAfter: retryAfter}
+	}
+	if client == SystemUsageClient {
+		return nil
+	}
+	if totals, ok := day.Clients[client]; ok {
+		if limit := exhausted(totals, m.limits.ClientDailyTokens, clientRequestLimit); limit != "" {
+			return &BudgetError{Scope: client, Limit: limit, RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// Report returns the limits, prices and the usage of the last `days` days
+func (m *UsageMeter) Report(days int) models.UsageReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	report := models.UsageReport{
+		Limits:   m.limits,
+		Prices:   m.prices,
+		ResetsAt: nextUsageReset(now),
+	}
+
+	unpriced := make(map[string]bool)
+	for i := 0; i < days; i++ {
+		date := now.UTC().AddDate(0, 0, -i).Format(models.BriefDateLayout)
+		day, ok := m.days[date]
+		if !ok {
+			continue
+		}
+		report.Days = append(report.Days, copyUsageDay(day))
+		for model := range day.Models {
+			if _, priced := m.prices[model]; !priced {
+				unpriced[model] = true
+			}
+		}
+	}
+	for model := range unpriced {
+		report.UnpricedModels = append(report.UnpricedModels, model)
+	}
+	sort.Strings(report.UnpricedModels)
+	return report
+}
+
+// dayLocked returns today's usage, creating it and pruning old days; callers hold mu
+func (m *UsageMeter) dayLocked(now time.Time) *models.UsageDay {
+	date := now.UTC().Format(models.BriefDateLayout)
+	day, ok := m.days[date]
+	if ok {
+		return day
+	}
+
+	day = &models.UsageDay{
+		Date:    date,
+		Clients: make(map[string]*models.UsageTotals),
+		Models:  make(map[string]*models.UsageTotals),
+	}
+	m.days[date] = day
+
+	cutoff := now.UTC().AddDate(0, 0, -usageRetainDays).Format(models.BriefDateLayout)
+	for old := range m.days {
+		if old < cutoff {
+			delete(m.days, old)
+		}
+	}
+	return day
+}
+
+// price returns a model's price, matching versioned names by prefix
+func (m *UsageMeter) price(model string) models.ModelPrice {
+	if price, ok := m.prices[model]; ok {
+		return price
+	}
+	best := ""
+	for name := range m.prices {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	return m.prices[best] // zero when unknown
+}
+
+// exhausted names the first limit totals has reached, or "" if none
+func exhausted(totals *models.UsageTotals, tokenLimit, requestLimit int) string {
+	if tokenLimit > 0 && totals.Tokens() >= tokenLimit {
+		return "tokens"
+	}
+	if requestLimit > 0 && totals.Requests >= requestLimit {
+		return "requests"
+	}
+	return ""
+}
+
+func usageEntry(entries map[string]*models.UsageTotals, key string) *models.UsageTotals {
+	totals, ok := entries[key]
+	if !ok {
+		totals = &models.UsageTotals{}
+		entries[key] = totals
+	}
+	return totals
+}
+
+func copyUsageDay(day *models.UsageDay) models.UsageDay {
+	result := models.UsageDay{
+		Date:    day.Date,
+		Total:   day.Total,
+		Clients: make(map[string]*models.UsageTotals, len(day.Clients)),
+		Models:  make(map[string]*models.UsageTotals, len(day.Models)),
+	}
+	for client, totals := range day.Clients {
+		copied := *totals
+		result.Clients[client] = &copied
+	}
+	for model, totals := range day.Models {
+		copied := *totals
+		result.Models[model] = &copied
+	}
+	return result
+}
+
+// nextUsageReset is the next UTC midnight, when daily budgets reset
+func nextUsageReset(now time.Time) time.Time {
+	utc := now.UTC()
+	return time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+}
+
+// parseModelPrices reads "model=input:output,..." (USD per million tokens), skipping malformed entries
+func parseModelPrices(raw string) map[string]models.ModelPrice {
+	prices := make(map[string]models.ModelPrice)
+	for _, entry := range strings.Split(raw, ",") {
+		model, rates, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		input, output, ok := strings.Cut(rates, ":")
+		if !ok {
+			continue
+		}
+		in, errIn := strconv.ParseFloat(strings.TrimSpace(input), 64)
+		out, errOut := strconv.ParseFloat(strings.TrimSpace(output), 64)
+		if errIn != nil || errOut != nil {
+			log.Printf("⚠️  Ignoring malformed model price %q", entry)
+			continue
+		}
+		prices[strings.TrimSpace(model)] = models.ModelPrice{InputPerMillion: in, OutputPerMillion: out}
+	}
+	return prices
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"backend/config"
+	"backend/store"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// failingProvider spends two tool rounds of usage and then fails
+type failingProvider struct {
+	TemplateProvider
+	checks []LLMUsage
+}
+
+func (p *failingProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	var usage LLMUsage
+	for round := 1; round <= 3; round++ {
+		if err := beforeToolRound(req, round, usage); err != nil {
+			return &LLMResponse{Usage: usage}, err
+		}
+		p.checks = append(p.checks, usage)
+		usage.Add(LLMUsage{PromptTokens: 100, OutputTokens: 20})
+	}
+	return &LLMResponse{Usage: usage}, errToolRoundsExceeded
+}
+
+func newTestMeter(t *testing.T, cfg *config.Config) *UsageMeter {
+	t.Helper()
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewUsageMeter(st, cfg)
+}
+
+func TestMeteredProviderChargesFailedCalls(t *testing.T) {
+	meter := newTestMeter(t, &config.Config{})
+	provider := newMeteredProvider(&failingProvider{}, meter)
+	ctx := WithUsageClient(context.Background(), "key:a")
+
+	if _, err := provider.Generate(ctx, LLMRequest{Prompt: "x"}); !errors.Is(err, errToolRoundsExceeded) {
+		t.Fatalf("Generate error = %v, want errToolRoundsExceeded", err)
+	}
+	day := meter.Report(1).Days[0]
+	if got := day.Clients["key:a"]; got.Requests != 1 || got.PromptTokens != 300 || got.OutputTokens != 60 {
+		t.Errorf("client usage = %+v, want 1 request, 300 prompt and 60 output tokens", *got)
+	}
+	if day.Total.Tokens() != 360 {
+		t.Errorf("total tokens = %d, want 360", day.Total.Tokens())
+	}
+}
+
+func TestMeteredProviderStopsAtTokenBudgetBetweenRounds(t *testing.T) {
+	meter := newTestMeter(t, &config.Config{AIClientDailyTokenBudget: 200})
+	inner := &failingProvider{}
+	provider := newMeteredProvider(inner, meter)
+	ctx := WithUsageClient(context.Background(), "key:a")
+
+	_, err := provider.Generate(ctx, LLMRequest{Prompt: "x"})
+	var budgetErr *BudgetError
+	if !errors.As(err, &budgetErr) || budgetErr.Limit != "tokens" {
+		t.Fatalf("Generate error = %v, want a token budget error", err)
+	}
+	// Round 2 sees round 1's 120 tokens; round 3 sees 240 and stops
+	if len(inner.checks) != 2 {
+		t.Errorf("rounds run = %d, want 2", len(inner.checks))
+	}
+	if got := meter.Report(1).Days[0].Clients["key:a"].Tokens(); got != 240 {
+		t.Errorf("client tokens = %d, want 240", got)
+	}
+}
+
+func TestUsageReserveIsAtomic(t *testing.T) {
+	meter := newTestMeter(t, &config.Config{AIClientDailyRequestBudget: 5})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := meter.Reserve("key:a", "m"); err == nil {
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if reserved != 5 {
+		t.Errorf("reserved = %d, want 5", reserved)
+	}
+}
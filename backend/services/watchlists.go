@@ -0,0 +1,287 @@
+package services
+
+import (
+	"backend/models"
+	"backend/store"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	watchlistCollection = "watchlists"
+	maxWatchlists       = 10000 // across all owners
+)
+
+var (
+	// ErrInvalidWatchlist wraps validation errors in a submitted watchlist
+	ErrInvalidWatchlist = errors.New("invalid watchlist")
+	// ErrWatchlistNotFound is returned for an unknown ID or another owner's watchlist
+	ErrWatchlistNotFound = errors.New("watchlist not found")
+	// ErrWatchlistLimit is returned when the owner (or the server) has too many watchlists
+	ErrWatchlistLimit = errors.New("watchlist limit reached")
+)
+
+// WatchlistService stores watchlists per client
+type WatchlistService struct {
+	store      *store.Store
+	marketData *MarketData
+
+	mu         sync.Mutex
+	watchlists []models.Watchlist // creation order
+}
+
+// NewWatchlistService loads stored watchlists
+func NewWatchlistService(st *store.Store, marketData *MarketData) *WatchlistService {
+	s := &WatchlistService{store: st, marketData: marketData}
+	if _, err := st.Load(watchlistCollection, &s.watchlists); err != nil {
+		log.Printf("⚠️  Failed to load watchlists, starting empty: %v", err)
+		s.watchlists = nil
+	}
+	return s
+}
+
+// Create stores a new watchlist for an owner
+func (s *WatchlistService) Create(ctx context.Context, owner string, req models.WatchlistRequest) (*models.Watchlist, error) {
+	name, tokenIDs, err := s.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	watchlist := models.Watchlist{
+		ID:        store.NewID(),
+		Owner:     owner,
+		Name:      name,
+		TokenIDs:  tokenIDs,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	owned := 0
+	for _, w := range s.watchlists {
+		if w.Owner == owner {
+			owned++
+		}
+	}
+	if owned >= models.MaxWatchlistsPerOwner || len(s.watchlists) >= maxWatchlists {
+		return nil, fmt.Errorf("%w (%d per client)", ErrWatchlistLimit, models.MaxWatchlistsPerOwner)
+	}
+	s.watchlists = append(s.watchlists, watchlist)
+	if err := s.store.Save(watchlistCollection, s.watchlists); err != nil {
+		s.watchlists = s.watchlists[:len(s.watchlists)-1]
+		return nil, err
+	}
+	return copyWatchlist(watchlist), nil
+}
+
+// List returns an owner's watchlists in creation order
+func (s *WatchlistService) List(owner string) []models.Watchlist {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]models.Watchlist, 0)
+	for _, w := range s.watchlists {
+		if w.Owner == owner {
+			result = append(result, *copyWatchlist(w))
+		}
+	}
+	return result
+}
+
+// Get returns a watchlist with its tokens from the current snapshot
+func (s *WatchlistService) Get(ctx context.Context, owner, id string) (*models.WatchlistDetail, error) {
+	s.mu.Lock()
+	i := s.index(owner, id)
+	if i < 0 {
+		s.mu.Unlock()
+		return nil, ErrWatchlistNotFound
+	}
+	watchlist := copyWatchlist(s.watchlists[i])
+	s.mu.Unlock()
+
+	tokens, err := s.marketData.Tokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index := indexTokens(tokens)
+
+	detail := &models.WatchlistDetail{Watchlist: *watchlist, Tokens: make([]models.Token, 0, len(watchlist.TokenIDs))}
+	for _, tokenID := range watchlist.TokenIDs {
+		if token, ok := index[tokenID]; ok {
+			detail.Tokens = append(detail.Tokens, *token)
+		} else {
+			detail.Missing = append(detail.Missing, tokenID)
+		}
+	}
+	return detail, nil
+}
+
+// Update replaces the name and tokens of a watchlist
+func (s *WatchlistService) Update(ctx context.Context, owner, id string, req models.WatchlistRequest) (*models.Watchlist, error) {
+	name, tokenIDs, err := s.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.update(owner, id, func(w *models.Watchlist) error {
+		w.Name = name
+		w.TokenIDs = tokenIDs
+		return nil
+	})
+}
+
+// AddToken appends a token to a watchlist; adding a token already present is a no-op
+func (s *WatchlistService) AddToken(ctx context.Context, owner, id, tokenID string) (*models.Watchlist, error) {
+	resolved, err := resolveTokenIDs(ctx, s.marketData, []string{tokenID})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWatchlist, err)
+	}
+	return s.update(owner, id, func(w *models.Watchlist) error {
+		if containsString(w.TokenIDs, resolved[0]) {
+			return nil
+		}
+		if len(w.TokenIDs) >= models.MaxWatchlistTokens {
+			return fmt.Errorf("%w: at most %d tokens", ErrInvalidWatchlist, models.MaxWatchlistTokens)
+		}
+		w.TokenIDs = append(w.TokenIDs, resolved[0])
+		return nil
+	})
+}
+
+// RemoveToken drops a token (by stored ID) from a watchlist
+func (s *WatchlistService) RemoveToken(owner, id, tokenID string) (*models.Watchlist, error) {
+	return s.update(owner, id, func(w *models.Watchlist) error {
+		for i, existing := range w.TokenIDs {
+			if strings.EqualFold(existing, tokenID) {
+				w.TokenIDs = append(w.TokenIDs[:i:i], w.TokenIDs[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s is not on the watchlist", ErrInvalidWatchlist, tokenID)
+	})
+}
+
+// Delete removes a watchlist
+func (s *WatchlistService) Delete(owner, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(owner, id)
+	if i < 0 {
+		return ErrWatchlistNotFound
+	}
+	s.watchlists = append(s.watchlists[:i:i], s.watchlists[i+1:]...)
+	return s.store.Save(watchlistCollection, s.watchlists)
+}
+
+func (s *WatchlistService) validate(ctx context.Context, req models.WatchlistRequest) (string, []string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", nil, fmt.Errorf("%w: name is required", ErrInvalidWatchlist)
+	}
+	if len(req.TokenIDs) > models.MaxWatchlistTokens {
+		return "", nil, fmt.Errorf("%w: at most %d tokens", ErrInvalidWatchlist, models.MaxWatchlistTokens)
+	}
+	tokenIDs, err := resolveTokenIDs(ctx, s.marketData, req.TokenIDs)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidWatchlist, err)
+	}
+	return name, tokenIDs, nil
+}
+
+// update applies a change to a copy of an owner's watchlist and persists it
+func (s *WatchlistService) update(owner, id string, change func(w *models.Watchlist) error) (*models.Watchlist, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(owner, id)
+	if i < 0 {
+		return nil, ErrWatchlistNotFound
+	}
+	updated := copyWatchlist(s.watchlists[i])
+	if err := change(updated); err != nil {
+		return nil, err
+	}
+	updated.UpdatedAt = time.Now()
+
+	previous := s.watchlists[i]
+	s.watchlists[i] = *updated
+	if err := s.store.Save(watchlistCollection, s.watchlists); err != nil {
+		s.watchlists[i] = previous
+		return nil, err
+	}
+	return copyWatchlist(*updated), nil
+}
+
+// index finds an owner's watchlist; callers hold s.mu
+func (s *WatchlistService) index(owner, id string) int {
+	for i, w := range s.watchlists {
+		if w.ID == id && w.Owner == owner {
+			return i
+		}
+	}
+	return -1
+}
+
+func copyWatchlist(w models.Watchlist) *models.Watchlist {
+	w.TokenIDs = append([]string{}, w.TokenIDs...)
+	return &w
+}
+
+// tokenLookup resolves IDs, symbols or names to token IDs in one snapshot
+type tokenLookup map[string]string
+
+// newTokenLookup indexes the current snapshot; the first token matching by ID,
+// symbol or name wins, as in MarketData.FindToken
+func newTokenLookup(ctx context.Context, marketData *MarketData) (tokenLookup, error) {
+	tokens, err := marketData.Tokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lookup := make(tokenLookup, len(tokens)*3)
+	for _, token := range tokens {
+		for _, key := range []string{token.ID, token.Symbol, token.Name} {
+			key = strings.ToLower(key)
+			if _, taken := lookup[key]; !taken && key != "" {
+				lookup[key] = token.ID
+			}
+		}
+	}
+	return lookup, nil
+}
+
+func (l tokenLookup) resolve(ref string) (string, bool) {
+	id, ok := l[strings.ToLower(strings.TrimSpace(ref))]
+	return id, ok
+}
+
+// resolveTokenIDs maps token references to IDs in the current snapshot and drops duplicates
+func resolveTokenIDs(ctx context.Context, marketData *MarketData, refs []string) ([]string, error) {
+	if len(refs) == 0 {
+		return []string{}, nil
+	}
+	lookup, err := newTokenLookup(ctx, marketData)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]string, 0, len(refs))
+	var unknown []string
+	for _, ref := range refs {
+		id, ok := lookup.resolve(ref)
+		if !ok {
+			unknown = append(unknown, ref)
+			continue
+		}
+		if !containsString(resolved, id) {
+			resolved = append(resolved, id)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown tokens: %s", strings.Join(unknown, ", "))
+	}
+	return resolved, nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"backend/models"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Discord embed colours per event type
+var discordEventColors = map[string]int{
+	models.EventAlertFired:      0xF5A623,
+	models.EventDepeg:           0xD0021B,
+	models.EventGradeChanged:    0x4A90E2,
+	models.EventSourceDown:      0xD0021B,
+	models.EventSourceRecovered: 0x7ED321,
+	models.EventWebhookTest:     0x9B9B9B,
+}
+
+// formatWebhookPayload renders an event in the webhook's payload format
+func formatWebhookPayload(webhook *models.Webhook, event models.NotificationEvent) ([]byte, error) {
+	title := fmt.Sprintf("[%s] %s", event.Type, event.Summary)
+
+	var payload interface{}
+	switch webhook.Format {
+	case models.WebhookFormatSlack:
+		// Incoming webhooks accept plain text plus optional blocks
+		payload = map[string]interface{}{
+			"text": title,
+			"blocks": []map[string]interface{}{
+				{
+					"type": "section",
+					"text": map[string]string{"type": "mrkdwn", "text": fmt.Sprintf("*%s*\n%s", event.Type, event.Summary)},
+				},
+				{
+					"type": "context",
+					"elements": []map[string]string{
+						{"type": "mrkdwn", "text": event.CreatedAt.UTC().Format(time.RFC1123)},
+					},
+				},
+			},
+		}
+	case models.WebhookFormatDiscord:
+		payload = map[string]interface{}{
+			"content": "",
+			"embeds": []map[string]interface{}{
+				{
+					"title":       event.Type,
+					"description": event.Summary,
+					"color":       discordEventColors[event.Type],
+					"timestamp":   event.CreatedAt.UTC().Format(time.RFC3339),
+				},
+			},
+		}
+	case models.WebhookFormatTelegram:
+		// Bot API sendMessage; plain text avoids escaping Markdown in token names
+		payload = map[string]interface{}{
+			"chat_id":                  webhook.ChatID,
+			"text":                     title,
+			"disable_web_page_preview": true,
+		}
+	default:
+		payload = event
+	}
+	return json.Marshal(payload)
+}
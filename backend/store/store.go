@@ -0,0 +1,90 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store persists named JSON documents as files under a data directory.
+// Each collection is loaded once at startup and rewritten whole on change.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// New creates a store rooted at dir, creating the directory if needed
+func New(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data dir %s: %w", dir, err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the data directory
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Load decodes the named document into v. It reports false if the document doesn't exist yet.
+func (s *Store) Load(name string, v interface{}) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// Save encodes v and atomically replaces the named document
+func (s *Store) Save(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Write to a temp file first so a crash never leaves a truncated document
+	tmp := s.path(name) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(name))
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// NewID returns a random 16-character hex identifier
+func NewID() string {
+	return randomHex(8)
+}
+
+// NewSecret returns 32 random bytes, hex-encoded after a recognizable prefix
+func NewSecret(prefix string) string {
+	return prefix + randomHex(32)
+}
+
+func randomHex(size int) string {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(buf)
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "math"
+import (
+	"backend/indicators"
+	"math"
+	"sort"
+)
 
 // Mathematical utility functions for scoring calculations
 
@@ -17,6 +21,23 @@ func CalculateMean(values []float64) float64 {
 	return sum / float64(len(values))
 }
 
+// CalculateMedian computes the median without mutating the input slice
+func CalculateMedian(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
 // CalculateStdDev computes standard deviation given values and their mean
 func CalculateStdDev(values []float64, mean float64) float64 {
 	if len(values) == 0 {
@@ -33,35 +54,13 @@ func CalculateStdDev(values []float64, mean float64) float64 {
 	return math.Sqrt(variance)
 }
 
-// CalculateRSI computes the Relative Strength Index
+// CalculateRSI computes the latest Wilder-smoothed Relative Strength Index
 func CalculateRSI(prices []float64, period int) float64 {
 	if len(prices) < period+1 {
 		return 50.0 // Neutral
 	}
 
-	gains := 0.0
-	losses := 0.0
-
-	for i := 1; i <= period; i++ {
-		change := prices[i] - prices[i-1]
-		if change > 0 {
-			gains += change
-		} else {
-			losses -= change
-		}
-	}
-
-	avgGain := gains / float64(period)
-	avgLoss := losses / float64(period)
-
-	if avgLoss == 0 {
-		return 100.0
-	}
-
-	rs := avgGain / avgLoss
-	rsi := 100 - (100 / (1 + rs))
-
-	return rsi
+	return indicators.Latest(indicators.RSI(prices, period))
 }
 
 // CalculateSMA computes Simple Moving Average
@@ -140,3 +139,159 @@ func CalculateVolatility(prices []float64) float64 {
 	stdDev := CalculateStdDev(prices, mean)
 	return (stdDev / mean) * 100
 }
+
+// CalculateReturns converts a price series into simple period returns
+func CalculateReturns(prices []float64) []float64 {
+	if len(prices) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, 0, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		if prices[i-1] == 0 {
+			returns = append(returns, 0)
+			continue
+		}
+		returns = append(returns, (prices[i]-prices[i-1])/prices[i-1])
+	}
+	return returns
+}
+
+// CalculateCovariance computes the population covariance of two equal-length series
+func CalculateCovariance(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	meanA := CalculateMean(a)
+	meanB := CalculateMean(b)
+
+	sum := 0.0
+	for i := range a {
+		sum += (a[i] - meanA) * (b[i] - meanB)
+	}
+	return sum / float64(len(a))
+}
+
+// CalculateCorrelation computes the Pearson correlation of two equal-length series
+func CalculateCorrelation(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	stdA := CalculateStdDev(a, CalculateMean(a))
+	stdB := CalculateStdDev(b, CalculateMean(b))
+	if stdA == 0 || stdB == 0 {
+		return 0
+	}
+
+	return CalculateCovariance(a, b) / (stdA * stdB)
+}
+
+// CalculateBeta computes the sensitivity of asset returns to benchmark returns
+func CalculateBeta(asset, benchmark []float64) float64 {
+	if len(asset) == 0 || len(asset) != len(benchmark) {
+		return 0
+	}
+
+	variance := CalculateCovariance(benchmark, benchmark)
+	if variance == 0 {
+		return 0
+	}
+
+	return CalculateCovariance(asset, benchmark) / variance
+}
+
+// CalculateQuantile returns the q-quantile (0-1) with linear interpolation, without mutating the input
+func CalculateQuantile(values []float64, q float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	q = math.Max(0, math.Min(1, q))
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+// NormalQuantile returns the standard normal z-score below which probability p lies
+func NormalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// NormalPDF evaluates the standard normal density
+func NormalPDF(z float64) float64 {
+	return math.Exp(-z*z/2) / math.Sqrt(2*math.Pi)
+}
+
+// CalculateHistoricalVaR returns the loss (positive) not exceeded by returns at the given confidence, e.g. 0.95
+func CalculateHistoricalVaR(returns []float64, confidence float64) float64 {
+	if len(returns) == 0 {
+		return 0
+	}
+	return -CalculateQuantile(returns, 1-confidence)
+}
+
+// CalculateParametricVaR returns the Gaussian (variance-covariance) VaR for a return mean and standard deviation
+func CalculateParametricVaR(mean, stdDev, confidence float64) float64 {
+	return -(mean + NormalQuantile(1-confidence)*stdDev)
+}
+
+// CalculateExpectedShortfall returns the average loss (positive) of returns at or beyond the historical VaR
+func CalculateExpectedShortfall(returns []float64, confidence float64) float64 {
+	if len(returns) == 0 {
+		return 0
+	}
+
+	threshold := CalculateQuantile(returns, 1-confidence)
+	sum, count := 0.0, 0
+	for _, r := range returns {
+		if r <= threshold {
+			sum += r
+			count++
+		}
+	}
+	return -sum / float64(count)
+}
+
+// CalculateParametricES returns the Gaussian expected shortfall for a return mean and standard deviation
+func CalculateParametricES(mean, stdDev, confidence float64) float64 {
+	tail := 1 - confidence
+	if tail <= 0 {
+		return 0
+	}
+	return -(mean - stdDev*NormalPDF(NormalQuantile(tail))/tail)
+}
+
+// CalculateMaxDrawdown returns the largest peak-to-trough decline of a value series (0-1)
+// and the indices of that peak and trough
+func CalculateMaxDrawdown(values []float64) (drawdown float64, peak, trough int) {
+	peakIdx := 0
+	for i, v := range values {
+		if v > values[peakIdx] {
+			peakIdx = i
+		}
+		if values[peakIdx] <= 0 {
+			continue
+		}
+		if dd := (values[peakIdx] - v) / values[peakIdx]; dd > drawdown {
+			drawdown, peak, trough = dd, peakIdx, i
+		}
+	}
+	return drawdown, peak, trough
+}
+
+// CalculateCumulativeValues compounds period returns into a value series starting at 1
+func CalculateCumulativeValues(returns []float64) []float64 {
+	values := make([]float64, len(returns)+1)
+	values[0] = 1
+	for i, r := range returns {
+		values[i+1] = values[i] * (1 + r)
+	}
+	return values
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+const tolerance = 1e-6
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < tolerance
+}
+
+// evenReturns is -10% to +10% in steps of 1%
+func evenReturns() []float64 {
+	returns := make([]float64, 21)
+	for i := range returns {
+		returns[i] = float64(i-10) / 100
+	}
+	return returns
+}
+
+func TestCalculateHistoricalVaR(t *testing.T) {
+	tests := []struct {
+		name       string
+		returns    []float64
+		confidence float64
+		want       float64
+	}{
+		{"5th percentile", evenReturns(), 0.95, 0.09},
+		{"interpolated", evenReturns(), 0.99, 0.098},
+		{"worst return at full confidence", evenReturns(), 1, 0.10},
+		{"profit at the quantile is a negative loss", []float64{0.01, 0.02, 0.03}, 0.5, -0.02},
+		{"empty", nil, 0.95, 0},
+	}
+	for _, tt := range tests {
+		if got := CalculateHistoricalVaR(tt.returns, tt.confidence); !approxEqual(got, tt.want) {
+			t.Errorf("%s: CalculateHistoricalVaR = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateExpectedShortfall(t *testing.T) {
+	tests := []struct {
+		name       string
+		returns    []float64
+		confidence float64
+		want       float64
+	}{
+		{"average of the tail", evenReturns(), 0.95, 0.095},
+		{"tail of one", evenReturns(), 0.99, 0.10},
+		{"half the returns", []float64{-0.04, -0.02, 0.02, 0.04}, 0.5, 0.03},
+		{"empty", nil, 0.95, 0},
+	}
+	for _, tt := range tests {
+		if got := CalculateExpectedShortfall(tt.returns, tt.confidence); !approxEqual(got, tt.want) {
+			t.Errorf("%s: CalculateExpectedShortfall = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	returns := evenReturns()
+	if es, tailVaR := CalculateExpectedShortfall(returns, 0.95), CalculateHistoricalVaR(returns, 0.95); es < tailVaR {
+		t.Errorf("expected shortfall %v is below VaR %v", es, tailVaR)
+	}
+}
+
+func TestCalculateParametricES(t *testing.T) {
+	tests := []struct {
+		name                     string
+		mean, stdDev, confidence float64
+		want                     float64
+	}{
+		// Standard normal tail expectations: φ(z)/(1-c)
+		{"standard normal 95%", 0, 1, 0.95, 2.0627128},
+		{"standard normal 97.5%", 0, 1, 0.975, 2.3378027},
+		{"standard normal 99%", 0, 1, 0.99, 2.6652142},
+		{"scaled and shifted", 0.001, 0.02, 0.95, 0.02*2.0627128 - 0.001},
+		{"no tail", 0, 1, 1, 0},
+	}
+	for _, tt := range tests {
+		if got := CalculateParametricES(tt.mean, tt.stdDev, tt.confidence); !approxEqual(got, tt.want) {
+			t.Errorf("%s: CalculateParametricES = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// The shortfall lies beyond the VaR at the same confidence
+	if es, tailVaR := CalculateParametricES(0, 1, 0.95), CalculateParametricVaR(0, 1, 0.95); !approxEqual(tailVaR, 1.6448536) || es <= tailVaR {
+		t.Errorf("parametric ES %v should exceed VaR %v (want 1.6448536)", es, tailVaR)
+	}
+}
+
+func TestCalculateMaxDrawdown(t *testing.T) {
+	tests := []struct {
+		name         string
+		values       []float64
+		want         float64
+		peak, trough int
+	}{
+		{"deepest of two declines", []float64{1, 1.2, 0.9, 1.1, 0.6, 1.3}, 0.5, 1, 4},
+		{"later peak with a smaller decline", []float64{1, 0.8, 1.5, 1.35}, 0.2, 0, 1},
+		{"rising series", []float64{1, 1.1, 1.2}, 0, 0, 0},
+		{"single value", []float64{1}, 0, 0, 0},
+		{"empty", nil, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		drawdown, peak, trough := CalculateMaxDrawdown(tt.values)
+		if !approxEqual(drawdown, tt.want) || peak != tt.peak || trough != tt.trough {
+			t.Errorf("%s: CalculateMaxDrawdown = (%v, %d, %d), want (%v, %d, %d)",
+				tt.name, drawdown, peak, trough, tt.want, tt.peak, tt.trough)
+		}
+	}
+}
+
+func TestCalculateCumulativeValues(t *testing.T) {
+	values := CalculateCumulativeValues([]float64{0.1, -0.5, 1})
+	want := []float64{1, 1.1, 0.55, 1.1}
+	if len(values) != len(want) {
+		t.Fatalf("CalculateCumulativeValues = %v, want %v", values, want)
+	}
+	for i := range want {
+		if !approxEqual(values[i], want[i]) {
+			t.Errorf("CalculateCumulativeValues = %v, want %v", values, want)
+			break
+		}
+	}
+}
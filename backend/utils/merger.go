@@ -4,6 +4,7 @@ import (
 	"backend/models"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // MergeEnhancedData combines data from all new sources with priority weighting
@@ -26,6 +27,7 @@ func MergeEnhancedData(results map[string]interface{}) []models.Token {
 					}
 					return ""
 				}(),
+				Tags:            coin.Tags,
 				Rank:            coin.CMCRank,
 				Price:           coin.Quote.USD.Price,
 				MarketCap:       coin.Quote.USD.MarketCap,
@@ -50,6 +52,12 @@ func MergeEnhancedData(results map[string]interface{}) []models.Token {
 				FullyDilutedValue: coin.Quote.USD.FullyDilutedMarketCap,
 				Sparkline:         coin.Quote.USD.Sparkline,
 				MarketCapDom:      coin.Quote.USD.MarketCapDominance,
+				Sources: map[string]models.SourceQuote{
+					"CoinMarketCap": {Price: coin.Quote.USD.Price, Volume24h: coin.Quote.USD.Volume24h},
+				},
+			}
+			if updated, err := time.Parse(time.RFC3339, coin.Quote.USD.LastUpdated); err == nil {
+				token.LastUpdated = updated
 			}
 			tokenMap[symbol] = token
 		}
@@ -96,6 +104,8 @@ func MergeEnhancedData(results map[string]interface{}) []models.Token {
 		for _, ma := range messariData {
 			symbol := NormalizeSymbol(ma.Symbol)
 			if token, exists := tokenMap[symbol]; exists {
+				recordSourceQuote(token, "Messari", ma.MarketData.PriceUSD, ma.MarketData.Volume24h)
+
 				// Fallback for missing metrics
 				if token.Price == 0 {
 					token.Price = ma.MarketData.PriceUSD
@@ -114,11 +124,14 @@ func MergeEnhancedData(results map[string]interface{}) []models.Token {
 	if dexData, ok := results["DexScreener"].(map[string]models.DexScreenerResponse); ok {
 		for symbol, dex := range dexData {
 			upperSymbol := NormalizeSymbol(symbol)
-			if token, exists := tokenMap[upperSymbol]; exists {
+			if token, exists := tokenMap[upperSymbol]; exists && len(dex.Pairs) > 0 {
+				var p float64
+				fmt.Sscanf(dex.Pairs[0].PriceUsd, "%f", &p)
+				// DEX volume only covers on-chain pairs, so only the price is comparable
+				recordSourceQuote(token, "DexScreener", p, 0)
+
 				// If we still don't have a price, take it from DexScreener
-				if token.Price == 0 && len(dex.Pairs) > 0 {
-					var p float64
-					fmt.Sscanf(dex.Pairs[0].PriceUsd, "%f", &p)
+				if token.Price == 0 {
 					token.Price = p
 				}
 			}
@@ -166,6 +179,17 @@ func MergeTokenData(
 	return MergeEnhancedData(results)
 }
 
+// recordSourceQuote keeps the figures a source reported so agreement can be checked later
+func recordSourceQuote(token *models.Token, source string, price, volume float64) {
+	if price <= 0 && volume <= 0 {
+		return
+	}
+	if token.Sources == nil {
+		token.Sources = make(map[string]models.SourceQuote)
+	}
+	token.Sources[source] = models.SourceQuote{Price: price, Volume24h: volume}
+}
+
 // NormalizeSymbol standardizes token symbols
 func NormalizeSymbol(symbol string) string {
 	return strings.ToUpper(strings.TrimSpace(symbol))
@@ -12,26 +12,8 @@ export function useAIAnalysis() {
     analysis.value = null
     
     try {
-      // Prepare minimal data for AI to reduce token count
-      const payload = {
-         symbol: tokenData.symbol,
-         name: tokenData.name,
-         price: tokenData.price,
-         market_cap: tokenData.market_cap,
-         volume_24h: tokenData.volume_24h,
-         change_24h: tokenData.change_24h,
-         change_7d: tokenData.change_7d,
-         change_30d: tokenData.change_30d || 0,
-         change_90d: tokenData.change_90d || 0,
-         tvl: tokenData.tvl || 0,
-         trust_score: tokenData.trust_score,
-         liquidity: tokenData.liquidity || 0,
-         rank: tokenData.rank || 0,
-         holder_count: tokenData.holder_count || 0,
-         circulating_supply: tokenData.circulating_supply || 0,
-         max_supply: tokenData.max_supply || 0,
-         total_supply: tokenData.total_supply || 0
-      }
+      // The server assembles market data itself; only the token is identified
+      const payload = { token_id: tokenData.id }
       
       const result = await api.analyzeToken(payload)
       
@@ -45,7 +27,18 @@ export function useAIAnalysis() {
       }
     } catch (e) {
       console.error('AI Analysis failed:', e)
-      error.value = "Failed to generate analysis. Please try again."
+      const status = e.response && e.response.status
+      if (status === 401 || status === 403) {
+        // The key is kept for this browser session only, never built into the app
+        const key = window.prompt('AI analysis needs a backend API key with the analyst role:')
+        if (key && key.trim()) {
+          api.setApiKey(key.trim())
+          return await analyzeToken(tokenData)
+        }
+        error.value = "AI analysis needs an API key with the analyst role."
+      } else {
+        error.value = "Failed to generate analysis. Please try again."
+      }
     } finally {
       isAnalyzing.value = false
     }
@@ -27,6 +27,76 @@ const filters = ref({
 
 const marketStats = ref(null)
 
+// Live updates for the loaded tokens. The server rejects subscriptions to more
+// than MAX_STREAM_IDS tokens, so only the first ones loaded are streamed.
+const MAX_STREAM_IDS = 500
+const STREAM_RETRY_BASE = 5000
+const STREAM_RETRY_MAX = 60000
+let liveStream = null
+let liveRetry = null
+let liveRetryDelay = STREAM_RETRY_BASE
+
+// Apply a streamed tick or diff to the matching loaded token
+const applyTick = (tick) => {
+  const token = tokens.value.find(t => t.id === tick.id)
+  if (!token) return
+  for (const [key, value] of Object.entries(tick)) {
+    if (key === 'grade') {
+      token.score_breakdown = { ...token.score_breakdown, grade: value }
+    } else if (key !== 'id' && key in token) {
+      token[key] = value
+    }
+  }
+}
+
+const subscribeLive = async () => {
+  if (liveStream instanceof EventSource) liveStream.close()
+  liveStream = null
+  clearTimeout(liveRetry)
+  if (typeof EventSource === 'undefined' || tokens.value.length === 0) return
+
+  // A stream token expires, so EventSource alone cannot outlive it: a closed stream or a
+  // failed subscription resubscribes with backoff, with a fresh token
+  const retry = () => {
+    console.warn(`⚠️ Live updates stopped, retrying in ${liveRetryDelay / 1000}s`)
+    liveRetry = setTimeout(subscribeLive, liveRetryDelay)
+    liveRetryDelay = Math.min(liveRetryDelay * 2, STREAM_RETRY_MAX)
+  }
+
+  const ids = tokens.value.slice(0, MAX_STREAM_IDS).map(t => t.id)
+  const subscription = {}
+  liveStream = subscription
+  let stream
+  try {
+    stream = await api.openTokenStream(ids)
+  } catch (e) {
+    if (liveStream === subscription) retry()
+    return
+  }
+  // Superseded by a newer subscribeLive while the stream token was fetched
+  if (liveStream !== subscription) {
+    stream.close()
+    return
+  }
+  liveStream = stream
+
+  stream.addEventListener('snapshot', (e) => {
+    JSON.parse(e.data).tokens.forEach(applyTick)
+    lastFetch.value = new Date()
+    liveRetryDelay = STREAM_RETRY_BASE
+  })
+  // EventSource reconnects by itself after a dropped connection, but gives up on an
+  // error response (rejected subscription or token, server down)
+  stream.addEventListener('error', () => {
+    if (stream.readyState === EventSource.CLOSED && liveStream === stream) retry()
+  })
+  stream.addEventListener('update', (e) => {
+    const { changed = [], added = [] } = JSON.parse(e.data)
+    changed.concat(added).forEach(applyTick)
+    lastFetch.value = new Date()
+  })
+}
+
 export function useTokens() {
   /**
    * Fetch tokens from API 
@@ -61,6 +131,7 @@ export function useTokens() {
       hasMore.value = tokensResp.hasMore
       marketStats.value = statsResp
       lastFetch.value = new Date()
+      subscribeLive()
       console.log(`✅ Total tokens in memory: ${tokens.value.length}, HasMore: ${hasMore.value}`)
 
     } catch (err) {
@@ -3,6 +3,14 @@ import axios from 'axios'
 
 // Configuration
 const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || '/api'
+// Optional backend API key, entered by the user (AI analysis needs the analyst role).
+// It lives in sessionStorage rather than the bundle and is only sent to the backend.
+const API_KEY_STORAGE = 'apiKey'
+const getApiKey = () => sessionStorage.getItem(API_KEY_STORAGE) || ''
+const backendHeaders = () => {
+  const key = getApiKey()
+  return key ? { 'X-API-Key': key } : {}
+}
 const COINGECKO_API = 'https://api.coingecko.com/api/v3'
 const DEXSCREENER_API = 'https://api.dexscreener.com/latest/dex/tokens'
 const GOPLUS_API = 'https://api.goplusecurity.com/api/v1/token_security'
@@ -44,11 +52,12 @@ export const api = {
         min_score: filters.minScore,
         max_score: filters.maxScore,
         min_change: filters.minChange,
-        max_change: filters.maxChange
+        max_change: filters.maxChange,
+        min_confidence: filters.minConfidence
       }
 
       console.log('🌍 Fetching from Backend:', `${API_BASE_URL}/tokens`, params)
-      const response = await axios.get(`${API_BASE_URL}/tokens`, { params })
+      const response = await axios.get(`${API_BASE_URL}/tokens`, { params, headers: backendHeaders() })
 
       // Backend returns { status: "success", data: [...], has_more: true, ... }
       if (response.data && response.data.status === 'success') {
@@ -104,7 +113,7 @@ export const api = {
     try {
       console.log('🤖 Sending AI Analysis Request:', payload)
       // Call backend AI service
-      const response = await axios.post(`${API_BASE_URL}/analyze`, payload)
+      const response = await axios.post(`${API_BASE_URL}/analyze`, payload, { headers: backendHeaders() })
       console.log('✅ AI Analysis Response:', response.data)
       return response.data
     } catch (e) {
@@ -126,7 +135,7 @@ export const api = {
     try {
       console.log(`🌍 Fetching Token Detail for ${id} from Backend...`)
       // The 'id' here is usually the symbol or name passed from transformBackendToken
-      const response = await axios.get(`${API_BASE_URL}/tokens/${id}`)
+      const response = await axios.get(`${API_BASE_URL}/tokens/${id}`, { headers: backendHeaders() })
 
       if (response.data) {
         const data = response.data
@@ -146,7 +155,7 @@ export const api = {
    */
   async fetchMarketStats() {
     try {
-      const response = await axios.get(`${API_BASE_URL}/market/stats`)
+      const response = await axios.get(`${API_BASE_URL}/market/stats`, { headers: backendHeaders() })
       return response.data
     } catch (e) {
       console.error('Failed to fetch market stats', e)
@@ -154,6 +163,30 @@ export const api = {
     }
   },
 
+  /**
+   * Store (or clear, with an empty key) the backend API key for this browser session
+   */
+  setApiKey(key) {
+    if (key) sessionStorage.setItem(API_KEY_STORAGE, key)
+    else sessionStorage.removeItem(API_KEY_STORAGE)
+  },
+
+  /**
+   * Live token stream (SSE): a snapshot on connect, then a diff per backend refresh.
+   * EventSource reconnects on its own and resumes through Last-Event-ID.
+   * It cannot set headers, so an API key is exchanged for a short-lived stream token.
+   */
+  async openTokenStream(ids = []) {
+    const base = API_BASE_URL.replace(/\/api\/?$/, '')
+    const params = new URLSearchParams()
+    if (ids.length) params.set('ids', ids.join(','))
+    if (getApiKey()) {
+      const response = await axios.post(`${API_BASE_URL}/auth/stream-token`, null, { headers: backendHeaders() })
+      params.set('stream_token', response.data.data.token)
+    }
+    return new EventSource(`${base}/ws?${params}`)
+  },
+
   // ...
 }
 
@@ -15,6 +15,10 @@ export default defineConfig(({ mode }) => {
         '/api': {
           target: env.VITE_API_PROXY_TARGET || 'http://localhost:8899',
           changeOrigin: true,
+        },
+        '/ws': {
+          target: env.VITE_API_PROXY_TARGET || 'http://localhost:8899',
+          changeOrigin: true,
         }
       }
     }